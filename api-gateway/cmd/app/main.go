@@ -2,22 +2,42 @@ package main
 
 import (
 	"api-gateway/internal/app"
-	"fmt"
+	"context"
+	"log/slog"
 	"os"
+
+	"platform/lifecycle"
+	"platform/logging"
+	"platform/tracing"
 )
 
 func main() {
+	// Настраиваем JSON-логгер сервиса
+	logger := logging.Setup("api-gateway")
+
+	// Настраиваем трассировку, экспортер задается переменной OTEL_TRACES_EXPORTER
+	shutdownTracing, err := tracing.Setup(context.Background(), "api-gateway")
+	if err != nil {
+		logger.Error("ошибка настройки трассировки", slog.Any("error", err))
+		os.Exit(1)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Создаем приложение
-	application, err := app.New()
+	application, err := app.New(logger)
 	if err != nil {
-		fmt.Printf("Ошибка при создании приложения: %v\n", err)
+		logger.Error("ошибка при создании приложения", slog.Any("error", err))
 		os.Exit(1)
 	}
 
+	// Останавливаем шлюз по SIGTERM или SIGINT
+	ctx, stop := lifecycle.SignalContext(context.Background())
+	defer stop()
+
 	// Запускаем сервер
-	fmt.Println("Запуск API Gateway...")
-	if err := application.Run(); err != nil {
-		fmt.Printf("Ошибка при запуске сервера: %v\n", err)
+	logger.Info("запуск API Gateway")
+	if err := application.Run(ctx); err != nil {
+		logger.Error("ошибка работы сервера", slog.Any("error", err))
 		os.Exit(1)
 	}
 }
@@ -2,31 +2,80 @@ package app
 
 import (
 	"api-gateway/internal/config"
+	"api-gateway/internal/database"
+	"api-gateway/internal/middleware"
 	"api-gateway/internal/proxy"
+	"api-gateway/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"time"
 
+	"platform/auth"
+	"platform/health"
+	"platform/lifecycle"
+	"platform/logging"
+	"platform/metrics"
+	"platform/tracing"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 // App представляет собой API-шлюз
 type App struct {
 	cfg        *config.Config
-	httpServer *gin.Engine
+	logger     *slog.Logger
+	router     *gin.Engine
+	httpServer *http.Server
+	db         *sql.DB
+	grpcConn   *grpc.ClientConn
+	checker    *health.Checker
+	workers    *lifecycle.Workers
 }
 
 // New создает новый экземпляр App
-func New() (*App, error) {
-	app := new(App)
+func New(logger *slog.Logger) (*App, error) {
+	app := &App{logger: logger, workers: lifecycle.NewWorkers()}
 
 	// Загружаем конфигурацию
-	app.cfg = config.New()
+	var err error
+	app.cfg, err = config.New()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка конфигурации: %w", err)
+	}
+
+	// Подключаемся к базе данных
+	app.db, err = database.NewPostgresDB(app.cfg.Database.URL)
+	if err != nil {
+		return nil, err
+	}
+	metrics.RegisterDB(app.db, "api-gateway")
+
+	// Подключаемся к gRPC серверу авторизации
+	app.grpcConn, err = grpc.Dial(
+		app.cfg.Services.AuthGRPCService.URL,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(
+			tracing.UnaryClientInterceptor(),
+			logging.UnaryClientInterceptor(),
+			metrics.UnaryClientInterceptor(),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подключения к серверу авторизации: %w", err)
+	}
 
 	// Настраиваем CORS
 	corsConfig := cors.Config{
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", middleware.HeaderIdempotencyKey, logging.HeaderRequestID, "traceparent", "tracestate"},
+		ExposeHeaders:    []string{middleware.HeaderIdempotentReplayed, logging.HeaderRequestID},
 		AllowCredentials: true,
 		AllowAllOrigins:  true,
 		MaxAge:           12 * time.Hour,
@@ -36,33 +85,99 @@ func New() (*App, error) {
 	gin.SetMode(gin.DebugMode)
 
 	// Создаем HTTP-сервер с Gin
-	app.httpServer = gin.Default()
+	app.router = gin.New()
 
-	// Добавляем middleware
-	app.httpServer.Use(
+	// Добавляем middleware: трассировка, X-Request-ID и журнал запросов в JSON
+	app.router.Use(
 		cors.New(corsConfig),
-		gin.Recovery(),
-		gin.Logger(),
+		tracing.Middleware(),
+		logging.Middleware(logger),
+		logging.Recovery(logger),
 	)
 
+	// Метрики запросов по маршрутам и GET /metrics
+	metrics.Setup(app.router)
+
 	// Добавляем простой хелсчек
-	app.httpServer.GET("/health", func(c *gin.Context) {
+	app.router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
 			"status": "ok",
 		})
 	})
 
-	// Создаем и настраиваем прокси для сервисов
-	serviceProxy := proxy.NewServiceProxy(app.cfg)
-	serviceProxy.SetupRoutes(app.httpServer)
+	// Включаем идемпотентность для настроенных изменяющих маршрутов
+	authMiddleware := auth.NewMiddleware(app.grpcConn, app.cfg.AuthCheck)
+	idempotency := middleware.NewIdempotencyMiddleware(
+		repositories.NewIdempotencyRepository(app.db),
+		authMiddleware,
+		app.cfg.Idempotency,
+	)
+	app.router.Use(idempotency.Middleware())
+	app.workers.Go(idempotency.CleanExpired)
+
+	// Создаем и настраиваем прокси для сервисов по таблице маршрутов
+	serviceProxy, err := proxy.NewServiceProxy(app.cfg, authMiddleware)
+	if err != nil {
+		return nil, err
+	}
+	app.workers.Go(serviceProxy.RunHealthChecks)
+	app.workers.Go(serviceProxy.WatchRoutes)
+	serviceProxy.SetupRoutes(app.router)
+
+	// Проверки живости и готовности шлюза и сводное состояние всех сервисов
+	app.checker = health.NewChecker(app.cfg.Proxy.HealthCheck.Timeout)
+	app.checker.Add("postgres", health.Postgres(app.db))
+	app.checker.Add("auth_grpc", health.GRPCConn(app.grpcConn))
+	serviceProxy.SetupHealthRoutes(app.router, app.checker)
+
+	// Состояние выключателей, экземпляров сервисов и таблица маршрутов доступны только администраторам
+	serviceProxy.SetupAdminRoutes(app.router, authMiddleware.RequireRoles("admin"))
 
-	fmt.Printf("API Gateway настроен на порту %s\n", app.cfg.Server.Port)
+	app.httpServer = lifecycle.NewHTTPServer(app.cfg.Server.Port, app.router)
+
+	logger.Info("API Gateway настроен", slog.String("port", app.cfg.Server.Port))
 
 	return app, nil
 }
 
-// Run запускает API-шлюз
-func (a *App) Run() error {
-	fmt.Printf("Запуск API-шлюза на порту %s\n", a.cfg.Server.Port)
-	return a.httpServer.Run(":" + a.cfg.Server.Port)
+// Run запускает API-шлюз и останавливает его после отмены контекста
+func (a *App) Run(ctx context.Context) error {
+	return lifecycle.Run(ctx, a.cfg.Shutdown.Timeout, a.Stop, func() error {
+		a.logger.Info("запуск API-шлюза", slog.String("port", a.cfg.Server.Port))
+		if err := lifecycle.ServeHTTP(a.httpServer); err != nil {
+			return fmt.Errorf("ошибка HTTP-сервера: %w", err)
+		}
+		return nil
+	})
+}
+
+// Stop останавливает API-шлюз: /readyz начинает отвечать 503, через DrainDelay
+// сервер перестает принимать соединения и ждет завершения начатых запросов,
+// затем останавливаются фоновые задачи и закрываются соединения
+func (a *App) Stop(ctx context.Context) error {
+	a.logger.Info("остановка API-шлюза", slog.Duration("drain_delay", a.cfg.Shutdown.DrainDelay))
+
+	a.checker.Drain()
+	a.httpServer.SetKeepAlivesEnabled(false)
+	lifecycle.Wait(ctx, a.cfg.Shutdown.DrainDelay)
+
+	var errs []error
+	if err := a.httpServer.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("остановка HTTP-сервера: %w", err))
+	}
+	if err := a.workers.Stop(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("остановка фоновых задач: %w", err))
+	}
+	if err := a.grpcConn.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("закрытие соединения с сервером авторизации: %w", err))
+	}
+	if err := a.db.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("закрытие базы данных: %w", err))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	a.logger.Info("API-шлюз остановлен")
+	return nil
 }
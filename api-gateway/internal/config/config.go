@@ -1,62 +1,242 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
+
+	"platform/auth"
+	"platform/config"
 )
 
 // Config содержит настройки API-шлюза
 type Config struct {
-	Server   ServerConfig
-	Services ServicesConfig
+	Server      ServerConfig      `yaml:"server"`
+	Database    DatabaseConfig    `yaml:"database"`
+	Services    ServicesConfig    `yaml:"services"`
+	Proxy       ProxyConfig       `yaml:"proxy"`
+	Routes      RoutesConfig      `yaml:"routes"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	Shutdown    ShutdownConfig    `yaml:"shutdown"`
+	// AuthCheck - таймаут проверки токена в сервисе авторизации и кэш решений
+	AuthCheck auth.Config `yaml:"auth_check"`
 }
 
 // ServerConfig содержит настройки HTTP-сервера
 type ServerConfig struct {
-	Port string
+	Port string `yaml:"port" env:"API_GATEWAY_PORT" default:"8090" validate:"required"`
+}
+
+// ShutdownConfig содержит настройки остановки шлюза
+type ShutdownConfig struct {
+	// Timeout - общее время на остановку, включая DrainDelay
+	Timeout time.Duration `yaml:"timeout" env:"SHUTDOWN_TIMEOUT" default:"20s" validate:"gt=0"`
+	// DrainDelay - время между переходом /readyz в 503 и закрытием HTTP-сервера
+	DrainDelay time.Duration `yaml:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY" default:"5s" validate:"gte=0,ltefield=Timeout"`
+}
+
+// DatabaseConfig содержит настройки подключения к базе данных
+type DatabaseConfig struct {
+	URL string `yaml:"url" env:"DB_URL" validate:"required"`
+}
+
+// IdempotencyConfig содержит настройки идемпотентности запросов
+type IdempotencyConfig struct {
+	// Routes - маршруты вида "POST /api/v1/edu/student/courses/purchase"
+	Routes []string `yaml:"routes" env:"IDEMPOTENCY_ROUTES" default:"POST /api/v1/edu/student/courses/purchase,POST /api/v1/game/clicker/clicks"`
+	// TTL - время хранения ответа для ключа
+	TTL time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL" default:"24h" validate:"gt=0"`
+	// LockTimeout - время, после которого незавершенная обработка считается прерванной
+	LockTimeout time.Duration `yaml:"lock_timeout" env:"IDEMPOTENCY_LOCK_TIMEOUT" default:"1m" validate:"gt=0"`
+	// CleanupInterval - период удаления устаревших ключей
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env:"IDEMPOTENCY_CLEANUP_INTERVAL" default:"10m" validate:"gt=0"`
 }
 
 // ServicesConfig содержит настройки сервисов
 type ServicesConfig struct {
-	AuthService     ServiceConfig
-	AuthGRPCService ServiceConfig
-	EduService      ServiceConfig
-	GameService     ServiceConfig
+	AuthService     AuthServiceConfig `yaml:"auth"`
+	AuthGRPCService GRPCServiceConfig `yaml:"auth_grpc"`
+	EduService      EduServiceConfig  `yaml:"edu"`
+	GameService     GameServiceConfig `yaml:"game"`
 }
 
-// ServiceConfig содержит настройки для сервиса
+// Стратегии балансировки нагрузки между экземплярами сервиса
+const (
+	BalancerRoundRobin     = "round_robin"
+	BalancerLeastConn      = "least_conn"
+	BalancerConsistentHash = "consistent_hash"
+)
+
+// ServiceConfig содержит настройки для HTTP сервиса
 type ServiceConfig struct {
+	// Endpoints - экземпляры сервиса, между которыми распределяются запросы
+	Endpoints Endpoints
+	// Balancer - стратегия балансировки: round_robin, least_conn или consistent_hash
+	Balancer string
+	// Timeout - время ожидания ответа сервиса по умолчанию для его маршрутов
+	Timeout time.Duration
+}
+
+// AuthServiceConfig содержит настройки HTTP сервиса авторизации
+type AuthServiceConfig struct {
+	Endpoints Endpoints     `yaml:"endpoints" env:"AUTH_SERVICE_URL" default:"http://auth-service:8080" validate:"required"`
+	Balancer  string        `yaml:"balancer" env:"AUTH_SERVICE_BALANCER" default:"round_robin" validate:"oneof=round_robin least_conn consistent_hash"`
+	Timeout   time.Duration `yaml:"timeout" env:"AUTH_SERVICE_TIMEOUT" default:"15s" validate:"gt=0"`
+}
+
+// EduServiceConfig содержит настройки образовательного сервиса
+type EduServiceConfig struct {
+	Endpoints Endpoints     `yaml:"endpoints" env:"EDU_SERVICE_URL" default:"http://edu-service:8081" validate:"required"`
+	Balancer  string        `yaml:"balancer" env:"EDU_SERVICE_BALANCER" default:"round_robin" validate:"oneof=round_robin least_conn consistent_hash"`
+	Timeout   time.Duration `yaml:"timeout" env:"EDU_SERVICE_TIMEOUT" default:"15s" validate:"gt=0"`
+}
+
+// GameServiceConfig содержит настройки игрового сервиса
+type GameServiceConfig struct {
+	Endpoints Endpoints     `yaml:"endpoints" env:"GAME_SERVICE_URL" default:"http://game-service:8083" validate:"required"`
+	Balancer  string        `yaml:"balancer" env:"GAME_SERVICE_BALANCER" default:"round_robin" validate:"oneof=round_robin least_conn consistent_hash"`
+	Timeout   time.Duration `yaml:"timeout" env:"GAME_SERVICE_TIMEOUT" default:"5s" validate:"gt=0"`
+}
+
+// ByName возвращает настройки HTTP сервисов по именам upstream из таблицы маршрутов.
+// Типы настроек сервисов отличаются только тегами, поэтому приводятся к ServiceConfig.
+func (s ServicesConfig) ByName() map[string]ServiceConfig {
+	return map[string]ServiceConfig{
+		"auth": ServiceConfig(s.AuthService),
+		"edu":  ServiceConfig(s.EduService),
+		"game": ServiceConfig(s.GameService),
+	}
+}
+
+// EndpointConfig содержит настройки экземпляра сервиса
+type EndpointConfig struct {
 	URL string
+	// Weight - относительная доля трафика экземпляра
+	Weight int
 }
 
-// New создает новую конфигурацию с значениями по умолчанию или из переменных окружения
-func New() *Config {
-	cfg := &Config{
-		Server: ServerConfig{
-			Port: getEnv("API_GATEWAY_PORT", "8090"),
-		},
-		Services: ServicesConfig{
-			AuthService: ServiceConfig{
-				URL: getEnv("AUTH_SERVICE_URL", "http://auth-service:8080"),
-			},
-			AuthGRPCService: ServiceConfig{
-				URL: getEnv("AUTH_GRPC_SERVICE_URL", "auth-service:9090"),
-			},
-			EduService: ServiceConfig{
-				URL: getEnv("EDU_SERVICE_URL", "http://edu-service:8081"),
-			},
-			GameService: ServiceConfig{
-				URL: getEnv("GAME_SERVICE_URL", "http://game-service:8083"),
-			},
-		},
+// Endpoints - экземпляры сервиса. В переменной окружения и файле конфигурации
+// задаются строкой вида "http://edu-v1:8081=90,http://edu-v2:8081=10"; вес по умолчанию равен 1.
+type Endpoints []EndpointConfig
+
+// UnmarshalText разбирает список экземпляров сервиса
+func (e *Endpoints) UnmarshalText(text []byte) error {
+	var endpoints Endpoints
+	for _, item := range strings.Split(string(text), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		endpoint := EndpointConfig{URL: item, Weight: 1}
+		if i := strings.LastIndex(item, "="); i > 0 {
+			weight, err := strconv.Atoi(item[i+1:])
+			if err != nil {
+				return fmt.Errorf("некорректный вес экземпляра %q", item)
+			}
+			endpoint.URL = item[:i]
+			endpoint.Weight = weight
+		}
+		// Убеждаемся, что URL содержит схему
+		endpoint.URL = normalizeURL(endpoint.URL)
+		if endpoint.Weight > 0 {
+			endpoints = append(endpoints, endpoint)
+		}
 	}
+	*e = endpoints
+	return nil
+}
 
-	// Проверяем и нормализуем URL для HTTP сервисов
-	cfg.Services.AuthService.URL = normalizeURL(cfg.Services.AuthService.URL)
-	cfg.Services.EduService.URL = normalizeURL(cfg.Services.EduService.URL)
-	cfg.Services.GameService.URL = normalizeURL(cfg.Services.GameService.URL)
+// MarshalText возвращает список экземпляров в том же формате
+func (e Endpoints) MarshalText() ([]byte, error) {
+	items := make([]string, 0, len(e))
+	for _, endpoint := range e {
+		items = append(items, fmt.Sprintf("%s=%d", endpoint.URL, endpoint.Weight))
+	}
+	return []byte(strings.Join(items, ",")), nil
+}
+
+// GRPCServiceConfig содержит настройки для gRPC сервиса
+type GRPCServiceConfig struct {
+	URL string `yaml:"url" env:"AUTH_GRPC_SERVICE_URL" default:"auth-service:9090" validate:"required"`
+}
+
+// ProxyConfig содержит настройки проксирования запросов к сервисам
+type ProxyConfig struct {
+	Transport   TransportConfig   `yaml:"transport"`
+	Retry       RetryConfig       `yaml:"retry"`
+	Breaker     BreakerConfig     `yaml:"breaker"`
+	HealthCheck HealthCheckConfig `yaml:"health_check"`
+	Outlier     OutlierConfig     `yaml:"outlier"`
+}
+
+// RoutesConfig содержит настройки таблицы маршрутов
+type RoutesConfig struct {
+	// File - путь к YAML или JSON файлу с маршрутами
+	File string `yaml:"file" env:"ROUTES_FILE" default:"routes.yaml" validate:"required"`
+	// ReloadInterval - период проверки изменений файла маршрутов
+	ReloadInterval time.Duration `yaml:"reload_interval" env:"ROUTES_RELOAD_INTERVAL" default:"5s" validate:"gt=0"`
+}
+
+// TransportConfig содержит настройки общего HTTP-транспорта для всех сервисов
+type TransportConfig struct {
+	MaxIdleConns        int           `yaml:"max_idle_conns" env:"PROXY_MAX_IDLE_CONNS" default:"200" validate:"gte=0"`
+	MaxIdleConnsPerHost int           `yaml:"max_idle_conns_per_host" env:"PROXY_MAX_IDLE_CONNS_PER_HOST" default:"50" validate:"gte=0"`
+	IdleConnTimeout     time.Duration `yaml:"idle_conn_timeout" env:"PROXY_IDLE_CONN_TIMEOUT" default:"90s" validate:"gte=0"`
+	DialTimeout         time.Duration `yaml:"dial_timeout" env:"PROXY_DIAL_TIMEOUT" default:"3s" validate:"gt=0"`
+	TLSHandshakeTimeout time.Duration `yaml:"tls_handshake_timeout" env:"PROXY_TLS_HANDSHAKE_TIMEOUT" default:"5s" validate:"gt=0"`
+}
+
+// RetryConfig содержит настройки повторов для идемпотентных методов
+type RetryConfig struct {
+	// MaxAttempts - максимальное количество попыток, включая первую
+	MaxAttempts int           `yaml:"max_attempts" env:"PROXY_RETRY_MAX_ATTEMPTS" default:"3" validate:"gte=1"`
+	BaseDelay   time.Duration `yaml:"base_delay" env:"PROXY_RETRY_BASE_DELAY" default:"50ms" validate:"gte=0"`
+	MaxDelay    time.Duration `yaml:"max_delay" env:"PROXY_RETRY_MAX_DELAY" default:"1s" validate:"gtefield=BaseDelay"`
+}
+
+// BreakerConfig содержит настройки автоматического выключателя для сервиса
+type BreakerConfig struct {
+	// Window - окно, за которое считается доля ошибок
+	Window time.Duration `yaml:"window" env:"BREAKER_WINDOW" default:"30s" validate:"gt=0"`
+	// MinRequests - минимальное количество запросов в окне для срабатывания
+	MinRequests int `yaml:"min_requests" env:"BREAKER_MIN_REQUESTS" default:"20" validate:"gte=1"`
+	// ErrorRate - доля ошибок (от 0 до 1), при которой выключатель размыкается
+	ErrorRate float64 `yaml:"error_rate" env:"BREAKER_ERROR_RATE" default:"0.5" validate:"gt=0,lte=1"`
+	// OpenTimeout - время, на которое выключатель размыкается
+	OpenTimeout time.Duration `yaml:"open_timeout" env:"BREAKER_OPEN_TIMEOUT" default:"30s" validate:"gt=0"`
+	// HalfOpenRequests - количество пробных запросов для замыкания
+	HalfOpenRequests int `yaml:"half_open_requests" env:"BREAKER_HALF_OPEN_REQUESTS" default:"3" validate:"gte=1"`
+}
 
-	return cfg
+// HealthCheckConfig содержит настройки активных проверок экземпляров сервисов
+type HealthCheckConfig struct {
+	Path     string        `yaml:"path" env:"HEALTH_CHECK_PATH" default:"/readyz" validate:"required"`
+	Interval time.Duration `yaml:"interval" env:"HEALTH_CHECK_INTERVAL" default:"10s" validate:"gt=0"`
+	Timeout  time.Duration `yaml:"timeout" env:"HEALTH_CHECK_TIMEOUT" default:"2s" validate:"gt=0"`
+	// HealthyThreshold - количество успешных проверок подряд для возврата экземпляра
+	HealthyThreshold int `yaml:"healthy_threshold" env:"HEALTH_CHECK_HEALTHY_THRESHOLD" default:"2" validate:"gte=1"`
+	// UnhealthyThreshold - количество неудачных проверок подряд для вывода экземпляра
+	UnhealthyThreshold int `yaml:"unhealthy_threshold" env:"HEALTH_CHECK_UNHEALTHY_THRESHOLD" default:"3" validate:"gte=1"`
+}
+
+// OutlierConfig содержит настройки пассивного исключения экземпляров по ошибкам запросов
+type OutlierConfig struct {
+	// ConsecutiveFailures - количество ошибок подряд, после которого экземпляр исключается
+	ConsecutiveFailures int `yaml:"consecutive_failures" env:"OUTLIER_CONSECUTIVE_FAILURES" default:"5" validate:"gte=0"`
+	// EjectionTime - время, на которое экземпляр исключается
+	EjectionTime time.Duration `yaml:"ejection_time" env:"OUTLIER_EJECTION_TIME" default:"30s" validate:"gte=0"`
+}
+
+// New загружает конфигурацию из значений по умолчанию, YAML-файла, переменных окружения и флагов
+func New() (*Config, error) {
+	cfg := &Config{}
+	if err := config.Load(cfg, os.Args[1:]); err != nil {
+		return nil, err
+	}
+	return cfg, nil
 }
 
 // normalizeURL убеждается, что URL содержит схему
@@ -66,12 +246,3 @@ func normalizeURL(url string) string {
 	}
 	return url
 }
-
-// getEnv получает значение переменной окружения или значение по умолчанию
-func getEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-	return value
-}
@@ -0,0 +1,23 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"platform/tracing"
+
+	_ "github.com/lib/pq"
+)
+
+// NewPostgresDB создает новое подключение к базе данных PostgreSQL
+func NewPostgresDB(url string) (*sql.DB, error) {
+	db, err := tracing.OpenDB("postgres", url)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подключения к базе данных: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("ошибка проверки подключения к базе данных: %w", err)
+	}
+
+	return db, nil
+}
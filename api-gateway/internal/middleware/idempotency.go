@@ -2,172 +2,301 @@ package middleware
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
-	"sync"
+	"strings"
 	"time"
 
+	"api-gateway/internal/config"
+	"api-gateway/internal/models"
+	"api-gateway/internal/repositories"
+	"platform/auth"
+	"platform/logging"
+	"platform/problem"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
-// IdempotencyMiddleware обеспечивает идемпотентность запросов
-type IdempotencyMiddleware struct {
-	// Храним информацию о запросах в памяти
-	// В реальном приложении лучше использовать Redis или другое хранилище
-	processedRequests sync.Map
-	// Время жизни записей в кэше
-	cacheTTL time.Duration
-}
+const (
+	// HeaderIdempotencyKey - заголовок с ключом идемпотентности (draft-ietf-httpapi-idempotency-key-header)
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed - заголовок, которым помечается повторно отданный ответ
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	maxIdempotentBodySize   = 1 << 20
+)
+
+// Коды ошибок проверки ключа идемпотентности
+var (
+	errIdempotencyKeyTooLong = problem.Define("IDEMPOTENCY_KEY_TOO_LONG", http.StatusBadRequest, problem.Messages{
+		"ru": "Слишком длинный ключ идемпотентности",
+		"en": "Idempotency key is too long",
+	})
+	errIdempotencyKeyInProgress = problem.Define("IDEMPOTENCY_KEY_IN_PROGRESS", http.StatusConflict, problem.Messages{
+		"ru": "Запрос с этим ключом идемпотентности еще обрабатывается",
+		"en": "A request with this idempotency key is still being processed",
+	})
+	errIdempotencyKeyReused = problem.Define("IDEMPOTENCY_KEY_REUSED", http.StatusUnprocessableEntity, problem.Messages{
+		"ru": "Ключ идемпотентности уже использован для другого запроса",
+		"en": "Idempotency key was already used for a different request",
+	})
+	errRequestTooLarge = problem.Define("REQUEST_TOO_LARGE", http.StatusRequestEntityTooLarge, problem.Messages{
+		"ru": "Невозможно прочитать тело запроса",
+		"en": "Request body is too large",
+	})
+)
 
-// RequestKey структура для хранения информации о запросе
-type RequestKey struct {
-	Method string
-	Path   string
-	Body   string
+// Заголовки, которые не сохраняются вместе с ответом
+var skippedResponseHeaders = map[string]bool{
+	"Content-Length":    true,
+	"Date":              true,
+	"Connection":        true,
+	"Transfer-Encoding": true,
 }
 
-// CachedResponse структура для хранения ответа
-type CachedResponse struct {
-	Status       int
-	Body         []byte
-	Headers      map[string][]string
-	LastAccessed time.Time
+// IdempotencyMiddleware обеспечивает идемпотентность запросов с заголовком Idempotency-Key.
+// Ключ действует в пределах пользователя, ответы хранятся в PostgreSQL.
+type IdempotencyMiddleware struct {
+	repo *repositories.IdempotencyRepository
+	auth *auth.Middleware
+	cfg  config.IdempotencyConfig
+	// routes - множество маршрутов вида "POST /path"
+	routes map[string]bool
 }
 
 // NewIdempotencyMiddleware создает новый экземпляр IdempotencyMiddleware
-func NewIdempotencyMiddleware(ttl time.Duration) *IdempotencyMiddleware {
+func NewIdempotencyMiddleware(repo *repositories.IdempotencyRepository, authMiddleware *auth.Middleware, cfg config.IdempotencyConfig) *IdempotencyMiddleware {
 	middleware := &IdempotencyMiddleware{
-		cacheTTL: ttl,
+		repo:   repo,
+		auth:   authMiddleware,
+		cfg:    cfg,
+		routes: make(map[string]bool, len(cfg.Routes)),
 	}
 
-	// Запускаем горутину для очистки кэша
-	go middleware.cleanCache()
+	for _, route := range cfg.Routes {
+		middleware.routes[normalizeRoute(route)] = true
+	}
 
-	fmt.Println("Идемпотентность запросов инициализирована с TTL:", ttl)
+	slog.Info("идемпотентность запросов включена", slog.Any("routes", cfg.Routes), slog.Duration("ttl", cfg.TTL))
 	return middleware
 }
 
 // Middleware возвращает Gin middleware для обеспечения идемпотентности запросов
 func (m *IdempotencyMiddleware) Middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Игнорируем GET запросы, так как они идемпотентны по определению
-		if c.Request.Method == http.MethodGet {
+		if !m.routes[c.Request.Method+" "+c.Request.URL.Path] {
 			c.Next()
 			return
 		}
 
-		// Получаем идентификатор идемпотентности из заголовка, если он есть
-		idempotencyKey := c.GetHeader("X-Idempotency-Key")
-		if idempotencyKey == "" {
-			// Если заголовок не предоставлен, создаем ключ на основе запроса
-			// Читаем тело запроса
-			bodyBytes, err := io.ReadAll(c.Request.Body)
-			if err != nil {
-				fmt.Printf("Ошибка при чтении тела запроса: %v\n", err)
-				c.JSON(http.StatusBadRequest, gin.H{"error": "невозможно прочитать тело запроса"})
-				c.Abort()
-				return
-			}
+		// Без явного ключа запрос обрабатывается как обычно:
+		// две одинаковые покупки без ключа - это две разные покупки
+		key := c.GetHeader(HeaderIdempotencyKey)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			problem.Abort(c, errIdempotencyKeyTooLong)
+			return
+		}
 
-			// Восстанавливаем тело запроса для дальнейшего использования
-			c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+		userID, ok, err := m.resolveUser(c)
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "ошибка при проверке пользователя для ключа идемпотентности", slog.Any("error", err))
+			problem.Abort(c, problem.ServiceUnavailable)
+			return
+		}
+		if !ok {
+			// Неавторизованный запрос отклонит сам сервис
+			c.Next()
+			return
+		}
+		logging.SetUserID(c, userID)
 
-			// Создаем уникальный ключ для запроса
-			idempotencyKey = m.createRequestKey(c.Request.Method, c.Request.URL.Path, string(bodyBytes))
+		bodyBytes, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodySize))
+		if err != nil {
+			problem.Abort(c, errRequestTooLarge)
+			return
 		}
+		c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 
-		fmt.Printf("Обработка запроса с ключом идемпотентности: %s\n", idempotencyKey)
+		now := time.Now()
+		record := &models.IdempotencyRecord{
+			UserID:      userID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: m.fingerprint(c.Request.Method, c.Request.URL.Path, bodyBytes),
+			LockedUntil: now.Add(m.cfg.LockTimeout),
+			ExpiresAt:   now.Add(m.cfg.TTL),
+		}
 
-		// Проверяем, был ли такой запрос уже обработан
-		if cachedResp, found := m.processedRequests.Load(idempotencyKey); found {
-			resp := cachedResp.(*CachedResponse)
-			// Обновляем время последнего доступа
-			resp.LastAccessed = time.Now()
-			m.processedRequests.Store(idempotencyKey, resp)
+		acquired, err := m.repo.Acquire(c.Request.Context(), record)
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "ошибка при захвате ключа идемпотентности", slog.String("idempotency_key", key), slog.Any("error", err))
+			problem.Abort(c, problem.ServiceUnavailable)
+			return
+		}
 
-			fmt.Printf("Найден кэшированный ответ для ключа: %s (статус: %d)\n", idempotencyKey, resp.Status)
+		if !acquired {
+			m.handleExisting(c, record)
+			return
+		}
 
-			// Устанавливаем заголовки
-			for name, values := range resp.Headers {
-				for _, value := range values {
-					c.Header(name, value)
-				}
-			}
+		m.process(c, record)
+	}
+}
 
-			// Добавляем заголовок идемпотентности
-			c.Header("X-Idempotency-Key", idempotencyKey)
-			c.Header("X-Idempotency-From-Cache", "true")
+// handleExisting обрабатывает повторный запрос с уже использованным ключом
+func (m *IdempotencyMiddleware) handleExisting(c *gin.Context, record *models.IdempotencyRecord) {
+	existing, err := m.repo.Get(c.Request.Context(), record.UserID, record.Key)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "ошибка при чтении ключа идемпотентности", slog.String("idempotency_key", record.Key), slog.Any("error", err))
+		problem.Abort(c, problem.ServiceUnavailable)
+		return
+	}
 
-			// Возвращаем кэшированный ответ
-			c.Data(resp.Status, "application/json", resp.Body)
-			c.Abort()
-			return
+	switch {
+	case existing == nil:
+		// Запись удалили между захватом и чтением - пусть клиент повторит запрос
+		c.Header("Retry-After", "1")
+		problem.Abort(c, errIdempotencyKeyInProgress)
+	case existing.RequestHash != record.RequestHash:
+		problem.Abort(c, errIdempotencyKeyReused)
+	case existing.Status == models.IdempotencyStatusInProgress:
+		c.Header("Retry-After", "1")
+		problem.Abort(c, errIdempotencyKeyInProgress)
+	default:
+		m.replay(c, existing)
+	}
+}
+
+// process выполняет запрос и сохраняет ответ для ключа
+func (m *IdempotencyMiddleware) process(c *gin.Context, record *models.IdempotencyRecord) {
+	// Ответ сохраняется независимо от того, дождался ли его клиент
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	completed := false
+	defer func() {
+		if !completed {
+			if err := m.repo.Release(ctx, record.UserID, record.Key); err != nil {
+				slog.ErrorContext(ctx, "ошибка при освобождении ключа идемпотентности", slog.String("idempotency_key", record.Key), slog.Any("error", err))
+			}
 		}
+	}()
 
-		// Создаем ResponseWriter для перехвата ответа
-		w := &responseWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
-		c.Writer = w
+	w := &responseWriter{body: &bytes.Buffer{}, ResponseWriter: c.Writer}
+	c.Writer = w
 
-		// Добавляем заголовок идемпотентности в оригинальный запрос
-		c.Header("X-Idempotency-Key", idempotencyKey)
+	c.Next()
 
-		// Выполняем запрос
-		c.Next()
+	// Ошибки сервера не сохраняются: клиент может повторить запрос с тем же ключом
+	status := w.Status()
+	if status >= http.StatusInternalServerError {
+		return
+	}
 
-		// Кэшируем ответ
-		cachedResp := &CachedResponse{
-			Status:       w.status,
-			Body:         w.body.Bytes(),
-			Headers:      make(map[string][]string),
-			LastAccessed: time.Now(),
+	record.ResponseStatus = status
+	record.ResponseBody = w.body.Bytes()
+	record.ResponseHeaders = make(map[string][]string)
+	for name, values := range w.Header() {
+		if !skippedResponseHeaders[name] {
+			record.ResponseHeaders[name] = values
 		}
+	}
 
-		// Копируем заголовки
-		for k, v := range w.Header() {
-			cachedResp.Headers[k] = v
+	if err := m.repo.Complete(ctx, record); err != nil {
+		slog.ErrorContext(ctx, "ошибка при сохранении ответа для ключа идемпотентности", slog.String("idempotency_key", record.Key), slog.Any("error", err))
+		return
+	}
+	completed = true
+}
+
+// replay возвращает сохраненный ответ
+func (m *IdempotencyMiddleware) replay(c *gin.Context, record *models.IdempotencyRecord) {
+	for name, values := range record.ResponseHeaders {
+		for _, value := range values {
+			c.Writer.Header().Add(name, value)
 		}
+	}
+	c.Header(HeaderIdempotentReplayed, "true")
 
-		// Сохраняем в кэше
-		m.processedRequests.Store(idempotencyKey, cachedResp)
-		fmt.Printf("Кэширован новый ответ для ключа: %s (статус: %d)\n", idempotencyKey, w.status)
+	c.Data(record.ResponseStatus, c.Writer.Header().Get("Content-Type"), record.ResponseBody)
+	c.Abort()
+}
+
+// resolveUser определяет пользователя по токену через сервис авторизации.
+// Возвращает false, если токен отсутствует или недействителен.
+func (m *IdempotencyMiddleware) resolveUser(c *gin.Context) (uuid.UUID, bool, error) {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" {
+		return uuid.Nil, false, nil
+	}
+
+	identity, err := m.auth.Check(c.Request.Context(), token)
+	if _, denied := err.(*auth.DeniedError); denied {
+		return uuid.Nil, false, nil
 	}
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+
+	return identity.UserID, true, nil
 }
 
-// createRequestKey создает уникальный ключ для запроса
-func (m *IdempotencyMiddleware) createRequestKey(method, path, body string) string {
+// fingerprint вычисляет отпечаток запроса для обнаружения повторного использования ключа
+func (m *IdempotencyMiddleware) fingerprint(method, path string, body []byte) string {
 	h := sha256.New()
-	h.Write([]byte(method + path + body))
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
 	return hex.EncodeToString(h.Sum(nil))
 }
 
-// cleanCache периодически удаляет устаревшие записи из кэша
-func (m *IdempotencyMiddleware) cleanCache() {
-	ticker := time.NewTicker(m.cacheTTL / 2)
+// CleanExpired периодически удаляет устаревшие ключи до отмены контекста
+func (m *IdempotencyMiddleware) CleanExpired(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.CleanupInterval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		now := time.Now()
-		count := 0
-		m.processedRequests.Range(func(key, value interface{}) bool {
-			resp := value.(*CachedResponse)
-			if now.Sub(resp.LastAccessed) > m.cacheTTL {
-				m.processedRequests.Delete(key)
-				count++
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		count, err := m.repo.DeleteExpired(ctx, time.Now())
+		if err != nil {
+			if ctx.Err() != nil {
+				return
 			}
-			return true
-		})
-		fmt.Printf("Очистка кэша: удалено %d устаревших записей\n", count)
+			slog.Error("ошибка при очистке ключей идемпотентности", slog.Any("error", err))
+			continue
+		}
+		slog.Info("очистка ключей идемпотентности", slog.Int64("deleted", count))
+	}
+}
+
+// normalizeRoute приводит маршрут вида "post /path" к виду "POST /path"
+func normalizeRoute(route string) string {
+	fields := strings.Fields(route)
+	if len(fields) != 2 {
+		return route
 	}
+	return strings.ToUpper(fields[0]) + " " + fields[1]
 }
 
 // responseWriter перехватывает ответ от обработчика
 type responseWriter struct {
 	gin.ResponseWriter
-	body   *bytes.Buffer
-	status int
+	body *bytes.Buffer
 }
 
 // Write записывает данные в буфер и в исходный ResponseWriter
@@ -176,8 +305,8 @@ func (w *responseWriter) Write(b []byte) (int, error) {
 	return w.ResponseWriter.Write(b)
 }
 
-// WriteHeader записывает статус ответа
-func (w *responseWriter) WriteHeader(code int) {
-	w.status = code
-	w.ResponseWriter.WriteHeader(code)
+// WriteString записывает строку в буфер и в исходный ResponseWriter
+func (w *responseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
 }
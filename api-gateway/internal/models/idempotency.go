@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы записи идемпотентности
+const (
+	IdempotencyStatusInProgress = "in_progress"
+	IdempotencyStatusCompleted  = "completed"
+)
+
+// IdempotencyRecord представляет сохраненный запрос с ключом идемпотентности
+type IdempotencyRecord struct {
+	UserID          uuid.UUID
+	Key             string
+	Method          string
+	Path            string
+	RequestHash     string
+	Status          string
+	ResponseStatus  int
+	ResponseHeaders map[string][]string
+	ResponseBody    []byte
+	LockedUntil     time.Time
+	ExpiresAt       time.Time
+	CreatedAt       time.Time
+}
@@ -0,0 +1,172 @@
+package proxy
+
+import (
+	"fmt"
+	"hash/crc32"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"api-gateway/internal/config"
+)
+
+// virtualNodesPerWeight - количество точек на кольце хеширования на единицу веса
+const virtualNodesPerWeight = 40
+
+// Balancer выбирает экземпляр сервиса для запроса
+type Balancer interface {
+	// Pick возвращает доступный экземпляр, отличный от exclude, если это возможно,
+	// или nil, если доступных экземпляров нет. key используется для консистентного хеширования.
+	Pick(key string, exclude *Endpoint) *Endpoint
+}
+
+// NewBalancer создает балансировщик с указанной стратегией
+func NewBalancer(strategy string, endpoints []*Endpoint) (Balancer, error) {
+	switch strategy {
+	case "", config.BalancerRoundRobin:
+		return newRoundRobinBalancer(endpoints), nil
+	case config.BalancerLeastConn:
+		return &leastConnBalancer{endpoints: endpoints}, nil
+	case config.BalancerConsistentHash:
+		return newConsistentHashBalancer(endpoints), nil
+	default:
+		return nil, fmt.Errorf("неизвестная стратегия балансировки: %s", strategy)
+	}
+}
+
+// available возвращает доступные экземпляры, по возможности без exclude
+func available(endpoints []*Endpoint, exclude *Endpoint) []*Endpoint {
+	now := time.Now()
+	result := make([]*Endpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if endpoint != exclude && endpoint.Available(now) {
+			result = append(result, endpoint)
+		}
+	}
+	if len(result) == 0 && exclude != nil && exclude.Available(now) {
+		result = append(result, exclude)
+	}
+	return result
+}
+
+// roundRobinBalancer реализует плавный взвешенный round-robin:
+// экземпляр с весом 10 из суммарных 100 получает каждый десятый запрос
+type roundRobinBalancer struct {
+	mu        sync.Mutex
+	endpoints []*Endpoint
+	current   map[*Endpoint]int
+}
+
+func newRoundRobinBalancer(endpoints []*Endpoint) *roundRobinBalancer {
+	return &roundRobinBalancer{
+		endpoints: endpoints,
+		current:   make(map[*Endpoint]int, len(endpoints)),
+	}
+}
+
+func (b *roundRobinBalancer) Pick(_ string, exclude *Endpoint) *Endpoint {
+	candidates := available(b.endpoints, exclude)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var best *Endpoint
+	total := 0
+	for _, endpoint := range candidates {
+		b.current[endpoint] += endpoint.weight
+		total += endpoint.weight
+		if best == nil || b.current[endpoint] > b.current[best] {
+			best = endpoint
+		}
+	}
+	b.current[best] -= total
+
+	return best
+}
+
+// leastConnBalancer выбирает экземпляр с наименьшим числом активных запросов на единицу веса
+type leastConnBalancer struct {
+	endpoints []*Endpoint
+}
+
+func (b *leastConnBalancer) Pick(_ string, exclude *Endpoint) *Endpoint {
+	candidates := available(b.endpoints, exclude)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// Начинаем со случайного экземпляра, чтобы при равной нагрузке запросы распределялись равномерно
+	offset := rand.IntN(len(candidates))
+	var best *Endpoint
+	var bestLoad float64
+	for i := range candidates {
+		endpoint := candidates[(offset+i)%len(candidates)]
+		load := float64(endpoint.active.Load()) / float64(endpoint.weight)
+		if best == nil || load < bestLoad {
+			best = endpoint
+			bestLoad = load
+		}
+	}
+
+	return best
+}
+
+// consistentHashBalancer направляет запросы с одним ключом (пользователем) на один экземпляр.
+// Вес определяет количество точек экземпляра на кольце.
+type consistentHashBalancer struct {
+	ring     []uint32
+	owners   map[uint32]*Endpoint
+	fallback *roundRobinBalancer
+}
+
+func newConsistentHashBalancer(endpoints []*Endpoint) *consistentHashBalancer {
+	b := &consistentHashBalancer{
+		owners:   make(map[uint32]*Endpoint),
+		fallback: newRoundRobinBalancer(endpoints),
+	}
+
+	for _, endpoint := range endpoints {
+		for i := 0; i < endpoint.weight*virtualNodesPerWeight; i++ {
+			point := crc32.ChecksumIEEE([]byte(endpoint.url.String() + "#" + strconv.Itoa(i)))
+			if _, exists := b.owners[point]; exists {
+				continue
+			}
+			b.owners[point] = endpoint
+			b.ring = append(b.ring, point)
+		}
+	}
+	sort.Slice(b.ring, func(i, j int) bool { return b.ring[i] < b.ring[j] })
+
+	return b
+}
+
+func (b *consistentHashBalancer) Pick(key string, exclude *Endpoint) *Endpoint {
+	if key == "" || len(b.ring) == 0 {
+		return b.fallback.Pick(key, exclude)
+	}
+
+	now := time.Now()
+	hash := crc32.ChecksumIEEE([]byte(key))
+	start := sort.Search(len(b.ring), func(i int) bool { return b.ring[i] >= hash })
+
+	// Идем по кольцу до первого доступного экземпляра
+	var excluded *Endpoint
+	for i := 0; i < len(b.ring); i++ {
+		endpoint := b.owners[b.ring[(start+i)%len(b.ring)]]
+		if !endpoint.Available(now) {
+			continue
+		}
+		if endpoint == exclude {
+			excluded = endpoint
+			continue
+		}
+		return endpoint
+	}
+
+	return excluded
+}
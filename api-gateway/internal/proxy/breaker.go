@@ -0,0 +1,227 @@
+package proxy
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"api-gateway/internal/config"
+)
+
+// ErrCircuitOpen возвращается, когда выключатель сервиса разомкнут
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState - состояние автоматического выключателя
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+// String возвращает название состояния
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// Outcome - результат запроса для выключателя
+type Outcome int
+
+const (
+	OutcomeSuccess Outcome = iota
+	OutcomeFailure
+	// OutcomeIgnored - запрос прерван клиентом и не влияет на статистику
+	OutcomeIgnored
+)
+
+// breakerBuckets - количество интервалов скользящего окна
+const breakerBuckets = 10
+
+type breakerBucket struct {
+	start     time.Time
+	successes int
+	failures  int
+}
+
+// CircuitBreaker размыкается, когда доля ошибок сервиса в скользящем окне
+// превышает порог, и пропускает пробные запросы после таймаута
+type CircuitBreaker struct {
+	mu  sync.Mutex
+	cfg config.BreakerConfig
+
+	state    BreakerState
+	openedAt time.Time
+
+	buckets        [breakerBuckets]breakerBucket
+	bucketDuration time.Duration
+
+	halfOpenInFlight  int
+	halfOpenSuccesses int
+}
+
+// BreakerSnapshot - состояние выключателя для административного API
+type BreakerSnapshot struct {
+	State     string     `json:"state"`
+	Requests  int        `json:"requests"`
+	Failures  int        `json:"failures"`
+	ErrorRate float64    `json:"error_rate"`
+	OpenedAt  *time.Time `json:"opened_at,omitempty"`
+	RetryAt   *time.Time `json:"retry_at,omitempty"`
+}
+
+// NewCircuitBreaker создает новый замкнутый выключатель
+func NewCircuitBreaker(cfg config.BreakerConfig) *CircuitBreaker {
+	bucketDuration := cfg.Window / breakerBuckets
+	if bucketDuration <= 0 {
+		bucketDuration = time.Second
+	}
+
+	return &CircuitBreaker{
+		cfg:            cfg,
+		bucketDuration: bucketDuration,
+	}
+}
+
+// Allow сообщает, можно ли отправить запрос к сервису.
+// Каждый разрешенный запрос должен завершиться вызовом Record.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+
+	switch b.state {
+	case BreakerOpen:
+		if now.Sub(b.openedAt) < b.cfg.OpenTimeout {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.halfOpenInFlight = 0
+		b.halfOpenSuccesses = 0
+		fallthrough
+	case BreakerHalfOpen:
+		if b.halfOpenInFlight >= b.halfOpenLimit() {
+			return false
+		}
+		b.halfOpenInFlight++
+		return true
+	default:
+		return true
+	}
+}
+
+// Record учитывает результат запроса
+func (b *CircuitBreaker) Record(outcome Outcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+
+	if b.state == BreakerHalfOpen {
+		if b.halfOpenInFlight > 0 {
+			b.halfOpenInFlight--
+		}
+		switch outcome {
+		case OutcomeFailure:
+			b.open(now)
+		case OutcomeSuccess:
+			b.halfOpenSuccesses++
+			if b.halfOpenSuccesses >= b.halfOpenLimit() {
+				b.close()
+			}
+		}
+		return
+	}
+
+	if outcome == OutcomeIgnored || b.state == BreakerOpen {
+		return
+	}
+
+	bucket := b.currentBucket(now)
+	if outcome == OutcomeFailure {
+		bucket.failures++
+	} else {
+		bucket.successes++
+	}
+
+	requests, failures := b.totals(now)
+	if requests >= b.cfg.MinRequests && float64(failures)/float64(requests) >= b.cfg.ErrorRate {
+		b.open(now)
+	}
+}
+
+// Snapshot возвращает текущее состояние выключателя
+func (b *CircuitBreaker) Snapshot() BreakerSnapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	requests, failures := b.totals(now)
+
+	snapshot := BreakerSnapshot{
+		State:    b.state.String(),
+		Requests: requests,
+		Failures: failures,
+	}
+	if requests > 0 {
+		snapshot.ErrorRate = float64(failures) / float64(requests)
+	}
+	if b.state != BreakerClosed {
+		openedAt := b.openedAt
+		retryAt := b.openedAt.Add(b.cfg.OpenTimeout)
+		snapshot.OpenedAt = &openedAt
+		snapshot.RetryAt = &retryAt
+	}
+
+	return snapshot
+}
+
+func (b *CircuitBreaker) open(now time.Time) {
+	b.state = BreakerOpen
+	b.openedAt = now
+	b.halfOpenInFlight = 0
+	b.halfOpenSuccesses = 0
+}
+
+func (b *CircuitBreaker) close() {
+	b.state = BreakerClosed
+	b.buckets = [breakerBuckets]breakerBucket{}
+}
+
+func (b *CircuitBreaker) halfOpenLimit() int {
+	if b.cfg.HalfOpenRequests < 1 {
+		return 1
+	}
+	return b.cfg.HalfOpenRequests
+}
+
+// currentBucket возвращает интервал окна для текущего момента, сбрасывая устаревший
+func (b *CircuitBreaker) currentBucket(now time.Time) *breakerBucket {
+	start := now.Truncate(b.bucketDuration)
+	bucket := &b.buckets[(start.UnixNano()/int64(b.bucketDuration))%breakerBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = breakerBucket{start: start}
+	}
+	return bucket
+}
+
+// totals суммирует запросы и ошибки за скользящее окно
+func (b *CircuitBreaker) totals(now time.Time) (int, int) {
+	windowStart := now.Add(-b.bucketDuration * breakerBuckets)
+	requests, failures := 0, 0
+	for _, bucket := range b.buckets {
+		if bucket.start.After(windowStart) {
+			requests += bucket.successes + bucket.failures
+			failures += bucket.failures
+		}
+	}
+	return requests, failures
+}
@@ -0,0 +1,154 @@
+package proxy
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxCacheEntries - максимальное количество ответов в кеше
+	maxCacheEntries = 10000
+	// maxCachedBodySize - максимальный размер тела кешируемого ответа
+	maxCachedBodySize = 1 << 20
+
+	// HeaderCache - заголовок, которым помечается ответ из кеша шлюза
+	HeaderCache = "X-Cache"
+)
+
+// cachedResponse - сохраненный ответ сервиса
+type cachedResponse struct {
+	status    int
+	header    http.Header
+	body      []byte
+	expiresAt time.Time
+}
+
+// ResponseCache хранит успешные ответы на GET-запросы в памяти
+type ResponseCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+}
+
+// NewResponseCache создает новый экземпляр ResponseCache
+func NewResponseCache() *ResponseCache {
+	return &ResponseCache{
+		entries: make(map[string]*cachedResponse),
+	}
+}
+
+// Serve отдает ответ из кеша, если он есть и не устарел
+func (rc *ResponseCache) Serve(c *gin.Context, key string) bool {
+	rc.mu.Lock()
+	entry, ok := rc.entries[key]
+	if ok && time.Now().After(entry.expiresAt) {
+		delete(rc.entries, key)
+		ok = false
+	}
+	rc.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	for name, values := range entry.header {
+		c.Writer.Header()[name] = values
+	}
+	c.Header(HeaderCache, "HIT")
+	c.Data(entry.status, entry.header.Get("Content-Type"), entry.body)
+	c.Abort()
+
+	return true
+}
+
+// Capture подменяет writer запроса, чтобы после обработки сохранить ответ в кеш
+func (rc *ResponseCache) Capture(c *gin.Context, key string, ttl time.Duration) func() {
+	w := &cacheWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+	c.Writer = w
+	c.Header(HeaderCache, "MISS")
+
+	return func() {
+		if w.Status() != http.StatusOK || w.overflow || !cacheable(w.Header()) {
+			return
+		}
+
+		header := w.Header().Clone()
+		header.Del(HeaderCache)
+		header.Del("Date")
+		header.Del("Set-Cookie")
+
+		rc.store(key, &cachedResponse{
+			status:    w.Status(),
+			header:    header,
+			body:      w.body.Bytes(),
+			expiresAt: time.Now().Add(ttl),
+		})
+	}
+}
+
+// store сохраняет ответ, при переполнении освобождая место
+func (rc *ResponseCache) store(key string, entry *cachedResponse) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if len(rc.entries) >= maxCacheEntries {
+		now := time.Now()
+		for k, e := range rc.entries {
+			if now.After(e.expiresAt) {
+				delete(rc.entries, k)
+			}
+		}
+		// Если устаревших ответов нет, удаляем произвольный
+		for k := range rc.entries {
+			if len(rc.entries) < maxCacheEntries {
+				break
+			}
+			delete(rc.entries, k)
+		}
+	}
+
+	rc.entries[key] = entry
+}
+
+// cacheable проверяет, разрешает ли сервис кешировать ответ
+func cacheable(header http.Header) bool {
+	cacheControl := strings.ToLower(header.Get("Cache-Control"))
+	return !strings.Contains(cacheControl, "no-store") &&
+		!strings.Contains(cacheControl, "private") &&
+		header.Get("Set-Cookie") == ""
+}
+
+// cacheWriter перехватывает ответ сервиса
+type cacheWriter struct {
+	gin.ResponseWriter
+	body     *bytes.Buffer
+	overflow bool
+}
+
+// Write записывает данные в буфер и в исходный ResponseWriter
+func (w *cacheWriter) Write(b []byte) (int, error) {
+	w.buffer(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString записывает строку в буфер и в исходный ResponseWriter
+func (w *cacheWriter) WriteString(s string) (int, error) {
+	w.buffer([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *cacheWriter) buffer(b []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(b) > maxCachedBodySize {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(b)
+}
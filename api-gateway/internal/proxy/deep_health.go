@@ -0,0 +1,99 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"platform/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeepHealth - сводное состояние шлюза и экземпляров всех сервисов
+type DeepHealth struct {
+	Status    string                    `json:"status"`
+	Checks    map[string]health.Result  `json:"checks"`
+	Upstreams map[string]UpstreamHealth `json:"upstreams"`
+}
+
+// UpstreamHealth - состояние экземпляров одного сервиса
+type UpstreamHealth struct {
+	Status    string                   `json:"status"`
+	Endpoints map[string]health.Result `json:"endpoints"`
+}
+
+// DeepHealth проверяет зависимости шлюза и опрашивает /readyz всех экземпляров всех сервисов.
+// Состояние fail - не прошла проверка шлюза или у сервиса нет ни одного готового экземпляра;
+// degraded - часть экземпляров не готова.
+func (p *ServiceProxy) DeepHealth(ctx context.Context, checker *health.Checker) DeepHealth {
+	var report health.Report
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		report = checker.Run(ctx)
+	}()
+
+	result := DeepHealth{
+		Upstreams: make(map[string]UpstreamHealth, len(p.upstreams)),
+	}
+
+	var mu sync.Mutex
+	for name, upstream := range p.upstreams {
+		wg.Add(1)
+		go func(name string, upstream *Upstream) {
+			defer wg.Done()
+
+			endpoints := health.RunChecks(ctx, p.cfg.Proxy.HealthCheck.Timeout, upstream.HealthChecks())
+
+			ready := 0
+			for _, endpoint := range endpoints {
+				if endpoint.Status == health.StatusOK {
+					ready++
+				}
+			}
+
+			status := health.StatusOK
+			switch {
+			case ready == 0:
+				status = health.StatusFail
+			case ready < len(endpoints):
+				status = health.StatusDegraded
+			}
+
+			mu.Lock()
+			result.Upstreams[name] = UpstreamHealth{Status: status, Endpoints: endpoints}
+			mu.Unlock()
+		}(name, upstream)
+	}
+	wg.Wait()
+
+	result.Status = report.Status
+	result.Checks = report.Checks
+	for _, upstream := range result.Upstreams {
+		if upstream.Status == health.StatusFail {
+			result.Status = health.StatusFail
+		}
+		if upstream.Status == health.StatusDegraded && result.Status == health.StatusOK {
+			result.Status = health.StatusDegraded
+		}
+	}
+
+	return result
+}
+
+// SetupHealthRoutes настраивает маршруты /livez, /readyz и сводное состояние /health/deep
+func (p *ServiceProxy) SetupHealthRoutes(router *gin.Engine, checker *health.Checker) {
+	checker.Register(router)
+
+	router.GET("/health/deep", func(c *gin.Context) {
+		result := p.DeepHealth(c.Request.Context(), checker)
+
+		status := http.StatusOK
+		if result.Status == health.StatusFail {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, result)
+	})
+}
@@ -0,0 +1,163 @@
+package proxy
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"api-gateway/internal/config"
+)
+
+// Endpoint - экземпляр сервиса
+type Endpoint struct {
+	url     *url.URL
+	weight  int
+	outlier config.OutlierConfig
+
+	// active - количество запросов, выполняющихся в данный момент
+	active atomic.Int64
+
+	mu sync.Mutex
+	// healthy - результат активных проверок
+	healthy            bool
+	healthStreak       int
+	consecutiveFailure int
+	ejectedUntil       time.Time
+}
+
+// EndpointSnapshot - состояние экземпляра для административного API
+type EndpointSnapshot struct {
+	URL          string     `json:"url"`
+	Weight       int        `json:"weight"`
+	Healthy      bool       `json:"healthy"`
+	Active       int64      `json:"active_requests"`
+	EjectedUntil *time.Time `json:"ejected_until,omitempty"`
+}
+
+// NewEndpoint создает экземпляр сервиса. До первой проверки экземпляр считается здоровым.
+// Адрес должен быть абсолютным http или https URL; путь, если есть, добавляется перед путем запроса.
+func NewEndpoint(cfg config.EndpointConfig, outlier config.OutlierConfig) (*Endpoint, error) {
+	target, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при разборе URL %s: %w", cfg.URL, err)
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return nil, fmt.Errorf("URL экземпляра %s: нужна схема http или https", cfg.URL)
+	}
+	if target.Host == "" || target.Opaque != "" {
+		return nil, fmt.Errorf("URL экземпляра %s: не указан хост", cfg.URL)
+	}
+	if target.User != nil || target.RawQuery != "" || target.Fragment != "" {
+		return nil, fmt.Errorf("URL экземпляра %s: допустимы только схема, хост и путь", cfg.URL)
+	}
+	// Путь экземпляра соединяется с путем запроса, который всегда начинается с /
+	target.Path = strings.TrimSuffix(target.Path, "/")
+	target.RawPath = strings.TrimSuffix(target.RawPath, "/")
+
+	weight := cfg.Weight
+	if weight < 1 {
+		weight = 1
+	}
+
+	return &Endpoint{
+		url:     target,
+		weight:  weight,
+		outlier: outlier,
+		healthy: true,
+	}, nil
+}
+
+// URL возвращает адрес экземпляра
+func (e *Endpoint) URL() *url.URL {
+	return e.url
+}
+
+// Available сообщает, можно ли направлять запросы на экземпляр
+func (e *Endpoint) Available(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.healthy && !now.Before(e.ejectedUntil)
+}
+
+// acquire учитывает начало запроса к экземпляру
+func (e *Endpoint) acquire() {
+	e.active.Add(1)
+}
+
+// release учитывает завершение запроса к экземпляру
+func (e *Endpoint) release() {
+	e.active.Add(-1)
+}
+
+// RecordResult учитывает результат запроса для пассивного исключения экземпляра
+func (e *Endpoint) RecordResult(outcome Outcome) {
+	if outcome == OutcomeIgnored || e.outlier.ConsecutiveFailures < 1 {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if outcome == OutcomeSuccess {
+		e.consecutiveFailure = 0
+		return
+	}
+
+	e.consecutiveFailure++
+	if e.consecutiveFailure >= e.outlier.ConsecutiveFailures {
+		e.consecutiveFailure = 0
+		e.ejectedUntil = time.Now().Add(e.outlier.EjectionTime)
+		slog.Warn("экземпляр исключен из балансировки", slog.String("endpoint", e.url.String()), slog.Time("ejected_until", e.ejectedUntil))
+	}
+}
+
+// RecordHealthCheck учитывает результат активной проверки
+func (e *Endpoint) RecordHealthCheck(ok bool, healthyThreshold, unhealthyThreshold int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// healthStreak положителен для серии успешных проверок и отрицателен для серии неудачных
+	if ok {
+		if e.healthStreak < 0 {
+			e.healthStreak = 0
+		}
+		e.healthStreak++
+		if !e.healthy && e.healthStreak >= healthyThreshold {
+			e.healthy = true
+			slog.Info("экземпляр снова доступен", slog.String("endpoint", e.url.String()))
+		}
+		return
+	}
+
+	if e.healthStreak > 0 {
+		e.healthStreak = 0
+	}
+	e.healthStreak--
+	if e.healthy && -e.healthStreak >= unhealthyThreshold {
+		e.healthy = false
+		slog.Warn("экземпляр не прошел проверки и выведен из балансировки", slog.String("endpoint", e.url.String()))
+	}
+}
+
+// Snapshot возвращает текущее состояние экземпляра
+func (e *Endpoint) Snapshot() EndpointSnapshot {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	snapshot := EndpointSnapshot{
+		URL:     e.url.String(),
+		Weight:  e.weight,
+		Healthy: e.healthy,
+		Active:  e.active.Load(),
+	}
+	if time.Now().Before(e.ejectedUntil) {
+		ejectedUntil := e.ejectedUntil
+		snapshot.EjectedUntil = &ejectedUntil
+	}
+
+	return snapshot
+}
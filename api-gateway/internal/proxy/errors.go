@@ -0,0 +1,23 @@
+package proxy
+
+import (
+	"net/http"
+
+	"platform/problem"
+)
+
+// Коды ошибок шлюза при обращении к сервисам
+var (
+	errBadGateway = problem.Define("BAD_GATEWAY", http.StatusBadGateway, problem.Messages{
+		"ru": "Ошибка при обращении к сервису",
+		"en": "Upstream service error",
+	})
+	errNoEndpoints = problem.Define("NO_ENDPOINTS", http.StatusServiceUnavailable, problem.Messages{
+		"ru": "Нет доступных экземпляров сервиса",
+		"en": "No upstream instances available",
+	})
+	errUpstreamTimeout = problem.Define("UPSTREAM_TIMEOUT", http.StatusGatewayTimeout, problem.Messages{
+		"ru": "Сервис не ответил вовремя",
+		"en": "Upstream service timed out",
+	})
+)
@@ -0,0 +1,89 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"api-gateway/internal/config"
+	"platform/health"
+)
+
+// HealthChecker периодически проверяет экземпляры сервисов по HTTP
+type HealthChecker struct {
+	cfg       config.HealthCheckConfig
+	client    *http.Client
+	endpoints []*Endpoint
+}
+
+// NewHealthChecker создает новый экземпляр HealthChecker
+func NewHealthChecker(cfg config.HealthCheckConfig, transport http.RoundTripper, endpoints []*Endpoint) *HealthChecker {
+	return &HealthChecker{
+		cfg:       cfg,
+		client:    &http.Client{Transport: transport, Timeout: cfg.Timeout},
+		endpoints: endpoints,
+	}
+}
+
+// Run выполняет проверки до отмены контекста
+func (h *HealthChecker) Run(ctx context.Context) {
+	if h.cfg.Interval <= 0 || h.cfg.Path == "" {
+		return
+	}
+
+	ticker := time.NewTicker(h.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		h.checkAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAll проверяет все экземпляры параллельно
+func (h *HealthChecker) checkAll(ctx context.Context) {
+	done := make(chan struct{}, len(h.endpoints))
+	for _, endpoint := range h.endpoints {
+		go func(endpoint *Endpoint) {
+			endpoint.RecordHealthCheck(h.check(ctx, endpoint), h.cfg.HealthyThreshold, h.cfg.UnhealthyThreshold)
+			done <- struct{}{}
+		}(endpoint)
+	}
+	for range h.endpoints {
+		<-done
+	}
+}
+
+// check выполняет одну проверку экземпляра
+func (h *HealthChecker) check(ctx context.Context, endpoint *Endpoint) bool {
+	target := endpoint.URL().JoinPath(h.cfg.Path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+// Checks возвращает проверки готовности экземпляров для сводного состояния /health/deep
+func (h *HealthChecker) Checks() map[string]health.Check {
+	checks := make(map[string]health.Check, len(h.endpoints))
+	for _, endpoint := range h.endpoints {
+		checks[endpoint.URL().String()] = health.HTTP(h.client, endpoint.URL().JoinPath(h.cfg.Path).String())
+	}
+	return checks
+}
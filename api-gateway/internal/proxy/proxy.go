@@ -2,69 +2,219 @@ package proxy
 
 import (
 	"api-gateway/internal/config"
+	"api-gateway/internal/routes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
-	"net/http/httputil"
-	"net/url"
+	"platform/auth"
+	"platform/logging"
+	"platform/problem"
+	"platform/tracing"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ServiceProxy представляет собой прокси для сервисов
 type ServiceProxy struct {
-	cfg *config.Config
+	cfg    *config.Config
+	routes *routes.Store
+	roles  *auth.Middleware
+
+	// upstreams и services - прокси и настройки сервисов по имени
+	upstreams map[string]*Upstream
+	services  map[string]config.ServiceConfig
+
+	limiter *RateLimiter
+	cache   *ResponseCache
 }
 
-// NewServiceProxy создает новый экземпляр ServiceProxy
-func NewServiceProxy(cfg *config.Config) *ServiceProxy {
-	return &ServiceProxy{
-		cfg: cfg,
+// NewServiceProxy создает новый экземпляр ServiceProxy и загружает таблицу маршрутов
+func NewServiceProxy(cfg *config.Config, roles *auth.Middleware) (*ServiceProxy, error) {
+	// Все сервисы используют общий пул соединений; каждая попытка запроса к сервису
+	// получает клиентский спан трассировки
+	transport := tracing.Transport(NewTransport(cfg.Proxy.Transport))
+
+	p := &ServiceProxy{
+		cfg:       cfg,
+		roles:     roles,
+		services:  cfg.Services.ByName(),
+		upstreams: make(map[string]*Upstream),
+		limiter:   NewRateLimiter(),
+		cache:     NewResponseCache(),
 	}
-}
 
-// ProxyRequest перенаправляет запрос к соответствующему сервису
-func (p *ServiceProxy) ProxyRequest(c *gin.Context, serviceURL string) {
-	// Проверяем и корректируем URL сервиса
-	if !strings.HasPrefix(serviceURL, "http://") && !strings.HasPrefix(serviceURL, "https://") {
-		serviceURL = "http://" + serviceURL
+	names := make([]string, 0, len(p.services))
+	for name, service := range p.services {
+		upstream, err := NewUpstream(name, service, cfg.Proxy, transport)
+		if err != nil {
+			return nil, err
+		}
+		p.upstreams[name] = upstream
+		names = append(names, name)
 	}
 
-	remote, err := url.Parse(serviceURL)
+	var err error
+	p.routes, err = routes.NewStore(cfg.Routes.File, names)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "ошибка при разборе URL сервиса"})
+		return nil, err
+	}
+	slog.Info("таблица маршрутов загружена", slog.String("file", cfg.Routes.File), slog.Int("routes", len(p.routes.Table().Routes)))
+
+	return p, nil
+}
+
+// RunHealthChecks выполняет активные проверки экземпляров всех сервисов до отмены контекста
+func (p *ServiceProxy) RunHealthChecks(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, upstream := range p.upstreams {
+		wg.Add(1)
+		go func(upstream *Upstream) {
+			defer wg.Done()
+			upstream.RunHealthChecks(ctx)
+		}(upstream)
+	}
+	wg.Wait()
+}
+
+// WatchRoutes перезагружает таблицу маршрутов по SIGHUP и при изменении файла до отмены контекста
+func (p *ServiceProxy) WatchRoutes(ctx context.Context) {
+	p.routes.Watch(ctx, p.cfg.Routes.ReloadInterval)
+}
+
+// ProxyRequest находит маршрут для запроса и перенаправляет запрос к сервису
+func (p *ServiceProxy) ProxyRequest(c *gin.Context) {
+	// Таблица берется один раз: перезагрузка не влияет на выполняющийся запрос
+	match, err := p.routes.Table().Match(c.Request.Method, c.Request.URL.Path)
+	if errors.Is(err, routes.ErrMethodNotAllowed) {
+		problem.Abort(c, problem.MethodNotAllowed)
+		return
+	}
+	if match == nil {
+		problem.Abort(c, problem.NotFound)
 		return
 	}
 
-	// Создаем реверс-прокси
-	proxy := httputil.NewSingleHostReverseProxy(remote)
+	route := match.Route
+	logging.SetRoute(c, route.Name)
+	trace.SpanFromContext(c.Request.Context()).SetAttributes(
+		attribute.String("gateway.route", route.Name),
+		attribute.String("gateway.upstream", route.Upstream),
+	)
+	upstream := p.upstreams[route.Upstream]
+	service := p.services[route.Upstream]
 
-	// Настраиваем директор для модификации запроса
-	originalDirector := proxy.Director
-	proxy.Director = func(req *http.Request) {
-		originalDirector(req)
+	if route.Auth.Policy == routes.AuthAuthenticated && !p.roles.Authorize(c, route.Auth.Roles...) {
+		return
+	}
 
-		// Сохраняем оригинальный путь
-		req.URL.Path = c.Request.URL.Path
-		req.URL.RawQuery = c.Request.URL.RawQuery
+	if route.RateLimit != nil {
+		key := c.ClientIP()
+		if route.RateLimit.Key == routes.RateLimitByUser {
+			key = fmt.Sprint(c.Value(auth.ContextUserID))
+		}
 
-		// Копируем заголовки из исходного запроса
-		for k, v := range c.Request.Header {
-			req.Header[k] = v
+		allowed, retryAfter := p.limiter.Allow(route.Name+"|"+key, route.RateLimit.Requests, route.RateLimit.Period)
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			problem.AbortWith(c, problem.New(c.Request, problem.RateLimited).With("route", route.Name))
+			return
 		}
 	}
 
-	// Настраиваем обработчик ошибок
-	proxy.ErrorHandler = func(rw http.ResponseWriter, req *http.Request, err error) {
-		c.JSON(http.StatusBadGateway, gin.H{
-			"error": "ошибка при обращении к сервису",
-		})
+	if route.Cache != nil && c.Request.Method == http.MethodGet {
+		key := route.Name + " " + match.Path + "?" + c.Request.URL.RawQuery
+		if route.Cache.Scope == routes.CacheScopeUser {
+			key += " " + fmt.Sprint(c.Value(auth.ContextUserID))
+		}
+		if p.cache.Serve(c, key) {
+			return
+		}
+		defer p.cache.Capture(c, key, route.Cache.TTL)()
+	}
+
+	timeout := route.Timeout
+	if timeout == 0 {
+		timeout = service.Timeout
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	if service.Balancer == config.BalancerConsistentHash {
+		ctx = WithBalanceKey(ctx, balanceKey(c))
+	}
+
+	req := c.Request.WithContext(ctx)
+	if match.Path != req.URL.Path {
+		rewritten := *req.URL
+		rewritten.Path = match.Path
+		rewritten.RawPath = ""
+		req.URL = &rewritten
 	}
 
-	// Убираем заголовок Content-Length, чтобы не конфликтовал при передаче
-	c.Request.Header.Del("Content-Length")
+	upstream.ServeHTTP(c.Writer, req)
+}
+
+// balanceKey возвращает ключ консистентного хеширования: ID пользователя
+// или, для анонимных запросов, IP клиента
+func balanceKey(c *gin.Context) string {
+	if userID, ok := auth.UserID(c); ok {
+		return userID.String()
+	}
+	if userID := userIDFromToken(c.GetHeader("Authorization")); userID != "" {
+		return userID
+	}
+	return c.ClientIP()
+}
+
+// userIDFromToken извлекает ID пользователя из JWT без проверки подписи.
+// Значение используется только для выбора экземпляра: токен проверяет сам сервис.
+func userIDFromToken(authHeader string) string {
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
 
-	// Проксируем запрос к целевому сервису
-	proxy.ServeHTTP(c.Writer, c.Request)
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+
+	var claims struct {
+		UserID string `json:"user_id"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+
+	return claims.UserID
+}
+
+// Breakers возвращает состояние выключателей всех сервисов
+func (p *ServiceProxy) Breakers() map[string]BreakerSnapshot {
+	snapshots := make(map[string]BreakerSnapshot)
+	for name, upstream := range p.upstreams {
+		snapshots[name] = upstream.Breaker().Snapshot()
+	}
+	return snapshots
+}
+
+// Endpoints возвращает состояние экземпляров всех сервисов
+func (p *ServiceProxy) Endpoints() map[string][]EndpointSnapshot {
+	snapshots := make(map[string][]EndpointSnapshot)
+	for name, upstream := range p.upstreams {
+		snapshots[name] = upstream.Endpoints()
+	}
+	return snapshots
 }
 
 // SetupRoutes настраивает маршруты для API-шлюза
@@ -76,21 +226,24 @@ func (p *ServiceProxy) SetupRoutes(router *gin.Engine) {
 		})
 	})
 
-	// Настраиваем маршруты для auth сервиса
-	authGroup := router.Group("/api/v1/auth")
-	authGroup.Any("/*path", func(c *gin.Context) {
-		p.ProxyRequest(c, p.cfg.Services.AuthService.URL)
-	})
+	// Все остальные запросы маршрутизируются по таблице из файла маршрутов
+	router.NoRoute(p.ProxyRequest)
+}
 
-	// Настраиваем маршруты для edu сервиса
-	eduGroup := router.Group("/api/v1/edu")
-	eduGroup.Any("/*path", func(c *gin.Context) {
-		p.ProxyRequest(c, p.cfg.Services.EduService.URL)
+// SetupAdminRoutes настраивает служебные маршруты шлюза
+func (p *ServiceProxy) SetupAdminRoutes(router *gin.Engine, handlers ...gin.HandlerFunc) {
+	admin := router.Group("/admin", handlers...)
+	admin.GET("/breakers", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"breakers": p.Breakers(),
+		})
 	})
-
-	// Настраиваем маршруты для game сервиса
-	gameGroup := router.Group("/api/v1/game")
-	gameGroup.Any("/*path", func(c *gin.Context) {
-		p.ProxyRequest(c, p.cfg.Services.GameService.URL)
+	admin.GET("/upstreams", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"upstreams": p.Endpoints(),
+		})
+	})
+	admin.GET("/routes", func(c *gin.Context) {
+		c.JSON(http.StatusOK, p.routes.Table().View())
 	})
 }
@@ -0,0 +1,68 @@
+package proxy
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiterSweepInterval - период удаления ключей без запросов в окне
+const rateLimiterSweepInterval = time.Minute
+
+// RateLimiter ограничивает количество запросов по ключу в скользящем окне
+type RateLimiter struct {
+	mu        sync.Mutex
+	requests  map[string][]time.Time
+	periods   map[string]time.Duration
+	lastSweep time.Time
+}
+
+// NewRateLimiter создает новый экземпляр RateLimiter
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		requests:  make(map[string][]time.Time),
+		periods:   make(map[string]time.Duration),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow учитывает запрос и сообщает, укладывается ли он в лимит.
+// Если лимит исчерпан, возвращает время до освобождения места в окне.
+func (rl *RateLimiter) Allow(key string, limit int, period time.Duration) (bool, time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(rl.lastSweep) > rateLimiterSweepInterval {
+		rl.sweep(now)
+	}
+
+	// Очищаем устаревшие отметки
+	timestamps := rl.requests[key]
+	valid := timestamps[:0]
+	for _, ts := range timestamps {
+		if now.Sub(ts) < period {
+			valid = append(valid, ts)
+		}
+	}
+
+	if len(valid) >= limit {
+		rl.requests[key] = valid
+		return false, period - now.Sub(valid[0])
+	}
+
+	rl.requests[key] = append(valid, now)
+	rl.periods[key] = period
+
+	return true, 0
+}
+
+// sweep удаляет ключи, у которых не осталось запросов в окне
+func (rl *RateLimiter) sweep(now time.Time) {
+	for key, timestamps := range rl.requests {
+		if len(timestamps) == 0 || now.Sub(timestamps[len(timestamps)-1]) >= rl.periods[key] {
+			delete(rl.requests, key)
+			delete(rl.periods, key)
+		}
+	}
+	rl.lastSweep = now
+}
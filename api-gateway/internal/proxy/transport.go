@@ -0,0 +1,28 @@
+package proxy
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"api-gateway/internal/config"
+)
+
+// NewTransport создает общий HTTP-транспорт с пулом соединений для всех сервисов
+func NewTransport(cfg config.TransportConfig) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   cfg.DialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+}
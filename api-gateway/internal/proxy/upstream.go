@@ -0,0 +1,333 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"sync"
+	"time"
+
+	"api-gateway/internal/config"
+	"platform/health"
+	"platform/problem"
+)
+
+// maxRetryBodySize - максимальный размер тела запроса, который буферизуется для повторов.
+// Запросы с телом большего размера отправляются один раз.
+const maxRetryBodySize = 1 << 20
+
+// Методы, запросы с которыми можно безопасно повторять
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+// Статусы ответа, при которых запрос повторяется
+var retryableStatuses = map[int]bool{
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// ErrNoEndpoints возвращается, когда у сервиса нет доступных экземпляров
+var ErrNoEndpoints = errors.New("no available endpoints")
+
+// balanceKeyCtx - ключ контекста с ключом консистентного хеширования запроса
+type balanceKeyCtx struct{}
+
+// WithBalanceKey добавляет в контекст ключ, по которому запрос привязывается к экземпляру
+func WithBalanceKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, balanceKeyCtx{}, key)
+}
+
+// Upstream - долгоживущий прокси к сервису с несколькими экземплярами и общим выключателем
+type Upstream struct {
+	name      string
+	endpoints []*Endpoint
+	balancer  Balancer
+	transport http.RoundTripper
+	retry     config.RetryConfig
+	breaker   *CircuitBreaker
+	health    *HealthChecker
+	proxy     *httputil.ReverseProxy
+}
+
+// NewUpstream создает прокси к сервису
+func NewUpstream(name string, service config.ServiceConfig, cfg config.ProxyConfig, transport http.RoundTripper) (*Upstream, error) {
+	if len(service.Endpoints) == 0 {
+		return nil, fmt.Errorf("для сервиса %s не указано ни одного экземпляра", name)
+	}
+
+	endpoints := make([]*Endpoint, 0, len(service.Endpoints))
+	for _, endpointCfg := range service.Endpoints {
+		endpoint, err := NewEndpoint(endpointCfg, cfg.Outlier)
+		if err != nil {
+			return nil, fmt.Errorf("сервис %s: %w", name, err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	balancer, err := NewBalancer(service.Balancer, endpoints)
+	if err != nil {
+		return nil, fmt.Errorf("сервис %s: %w", name, err)
+	}
+
+	u := &Upstream{
+		name:      name,
+		endpoints: endpoints,
+		balancer:  balancer,
+		transport: transport,
+		retry:     cfg.Retry,
+		breaker:   NewCircuitBreaker(cfg.Breaker),
+		health:    NewHealthChecker(cfg.HealthCheck, transport, endpoints),
+	}
+
+	u.proxy = &httputil.ReverseProxy{
+		// Адрес экземпляра подставляется в RoundTrip отдельно для каждой попытки.
+		// Сервисы обрабатывают полные пути с префиксами /api/v1/{service}.
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.Out.Host = ""
+			r.SetXForwarded()
+		},
+		Transport:    u,
+		ErrorHandler: u.handleError,
+	}
+
+	return u, nil
+}
+
+// Name возвращает название сервиса
+func (u *Upstream) Name() string {
+	return u.name
+}
+
+// Breaker возвращает выключатель сервиса
+func (u *Upstream) Breaker() *CircuitBreaker {
+	return u.breaker
+}
+
+// Endpoints возвращает состояние экземпляров сервиса
+func (u *Upstream) Endpoints() []EndpointSnapshot {
+	snapshots := make([]EndpointSnapshot, 0, len(u.endpoints))
+	for _, endpoint := range u.endpoints {
+		snapshots = append(snapshots, endpoint.Snapshot())
+	}
+	return snapshots
+}
+
+// HealthChecks возвращает проверки готовности всех экземпляров сервиса
+func (u *Upstream) HealthChecks() map[string]health.Check {
+	return u.health.Checks()
+}
+
+// RunHealthChecks выполняет активные проверки экземпляров до отмены контекста
+func (u *Upstream) RunHealthChecks(ctx context.Context) {
+	u.health.Run(ctx)
+}
+
+// ServeHTTP проксирует запрос к сервису
+func (u *Upstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.proxy.ServeHTTP(w, r)
+}
+
+// RoundTrip отправляет запрос к экземпляру сервиса с учетом выключателя и повторов.
+// Повторная попытка по возможности направляется на другой экземпляр.
+func (u *Upstream) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTripper не должен изменять запрос вызывающего, поэтому тело
+	// буферизуется и пересоздается для повторов на копии запроса
+	req = req.Clone(req.Context())
+
+	maxAttempts := 1
+	if idempotentMethods[req.Method] && u.retry.MaxAttempts > 1 {
+		maxAttempts = u.retry.MaxAttempts
+	}
+
+	if maxAttempts > 1 && req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		if !bufferBody(req) {
+			maxAttempts = 1
+		}
+	}
+
+	key, _ := req.Context().Value(balanceKeyCtx{}).(string)
+	var previous *Endpoint
+
+	for attempt := 1; ; attempt++ {
+		if !u.breaker.Allow() {
+			return nil, ErrCircuitOpen
+		}
+
+		endpoint := u.balancer.Pick(key, previous)
+		if endpoint == nil {
+			u.breaker.Record(OutcomeFailure)
+			return nil, ErrNoEndpoints
+		}
+		previous = endpoint
+
+		resp, err := u.send(req, endpoint)
+		outcome := outcomeOf(req.Context(), resp, err)
+		u.breaker.Record(outcome)
+		endpoint.RecordResult(outcome)
+
+		if attempt >= maxAttempts || !shouldRetry(req.Context(), resp, err) {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := u.wait(req.Context(), attempt); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// send отправляет одну попытку запроса на экземпляр.
+// Экземпляр считается занятым, пока тело ответа не будет прочитано.
+func (u *Upstream) send(req *http.Request, endpoint *Endpoint) (*http.Response, error) {
+	target := endpoint.URL()
+	out := req.Clone(req.Context())
+	out.URL.Scheme = target.Scheme
+	out.URL.Host = target.Host
+	if target.Path != "" {
+		if req.URL.RawPath != "" {
+			out.URL.RawPath = target.EscapedPath() + req.URL.RawPath
+		}
+		out.URL.Path = target.Path + req.URL.Path
+	}
+
+	endpoint.acquire()
+	resp, err := u.transport.RoundTrip(out)
+	if err != nil {
+		endpoint.release()
+		return nil, err
+	}
+
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: endpoint.release}
+	return resp, nil
+}
+
+// releasingBody освобождает экземпляр при закрытии тела ответа
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+// Close закрывает тело ответа
+func (b *releasingBody) Close() error {
+	b.once.Do(b.release)
+	return b.ReadCloser.Close()
+}
+
+// wait выдерживает паузу перед повтором: экспоненциальная задержка с полным джиттером
+func (u *Upstream) wait(ctx context.Context, attempt int) error {
+	backoff := u.retry.BaseDelay << (attempt - 1)
+	if backoff <= 0 || backoff > u.retry.MaxDelay {
+		backoff = u.retry.MaxDelay
+	}
+	if backoff <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(rand.N(backoff) + 1)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// handleError отвечает клиенту, если сервис недоступен
+func (u *Upstream) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	def := errBadGateway
+
+	switch {
+	case errors.Is(err, ErrNoEndpoints):
+		def = errNoEndpoints
+	case errors.Is(err, ErrCircuitOpen):
+		def = problem.ServiceUnavailable
+		if retryAt := u.breaker.Snapshot().RetryAt; retryAt != nil {
+			seconds := int(time.Until(*retryAt).Seconds()) + 1
+			w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		def = errUpstreamTimeout
+	case errors.Is(err, context.Canceled):
+		// Клиент закрыл соединение, отвечать некому
+		return
+	}
+
+	slog.ErrorContext(r.Context(), "ошибка при обращении к сервису",
+		slog.String("upstream", u.name), slog.String("code", def.Code), slog.Any("error", err))
+
+	problem.Write(w, r, problem.New(r, def).With("upstream", u.name))
+}
+
+// bufferBody читает тело запроса в память, чтобы его можно было отправить повторно.
+// Возвращает false, если тело слишком большое для повторов.
+func bufferBody(req *http.Request) bool {
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRetryBodySize+1))
+	if err != nil || len(body) > maxRetryBodySize {
+		// Возвращаем прочитанную часть, чтобы запрос ушел целиком
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+		return false
+	}
+
+	req.Body.Close()
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Body, _ = req.GetBody()
+	return true
+}
+
+// outcomeOf определяет результат попытки для выключателя
+func outcomeOf(ctx context.Context, resp *http.Response, err error) Outcome {
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return OutcomeIgnored
+		}
+		return OutcomeFailure
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return OutcomeFailure
+	}
+	return OutcomeSuccess
+}
+
+// shouldRetry определяет, нужно ли повторить попытку
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return retryableStatuses[resp.StatusCode]
+}
@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"api-gateway/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyRepository хранит ключи идемпотентности и сохраненные ответы в PostgreSQL
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+// NewIdempotencyRepository создает новый экземпляр IdempotencyRepository
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Acquire пытается захватить ключ для обработки запроса.
+// Ключ захватывается, если он новый, если срок хранения прежней записи истек
+// или если прежняя обработка того же запроса была прервана и ее блокировка устарела.
+func (r *IdempotencyRepository) Acquire(ctx context.Context, record *models.IdempotencyRecord) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (
+			user_id, idempotency_key, request_method, request_path, request_hash,
+			status, locked_until, expires_at, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, NOW()
+		)
+		ON CONFLICT (user_id, idempotency_key) DO UPDATE
+		SET request_method = EXCLUDED.request_method,
+			request_path = EXCLUDED.request_path,
+			request_hash = EXCLUDED.request_hash,
+			status = EXCLUDED.status,
+			response_status = NULL,
+			response_headers = NULL,
+			response_body = NULL,
+			locked_until = EXCLUDED.locked_until,
+			expires_at = EXCLUDED.expires_at,
+			created_at = NOW()
+		WHERE idempotency_keys.expires_at < NOW()
+			OR (idempotency_keys.status = 'in_progress'
+				AND idempotency_keys.locked_until < NOW()
+				AND idempotency_keys.request_hash = EXCLUDED.request_hash)
+		RETURNING user_id
+	`
+
+	var userID uuid.UUID
+	err := r.db.QueryRowContext(ctx, query,
+		record.UserID, record.Key, record.Method, record.Path, record.RequestHash,
+		models.IdempotencyStatusInProgress, record.LockedUntil, record.ExpiresAt,
+	).Scan(&userID)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Get возвращает запись по пользователю и ключу или nil, если записи нет
+func (r *IdempotencyRepository) Get(ctx context.Context, userID uuid.UUID, key string) (*models.IdempotencyRecord, error) {
+	query := `
+		SELECT user_id, idempotency_key, request_method, request_path, request_hash,
+			   status, response_status, response_headers, response_body,
+			   locked_until, expires_at, created_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND idempotency_key = $2
+	`
+
+	record := &models.IdempotencyRecord{}
+	var responseStatus sql.NullInt64
+	var headersJSON []byte
+	err := r.db.QueryRowContext(ctx, query, userID, key).Scan(
+		&record.UserID, &record.Key, &record.Method, &record.Path, &record.RequestHash,
+		&record.Status, &responseStatus, &headersJSON, &record.ResponseBody,
+		&record.LockedUntil, &record.ExpiresAt, &record.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record.ResponseStatus = int(responseStatus.Int64)
+	if headersJSON != nil {
+		if err := json.Unmarshal(headersJSON, &record.ResponseHeaders); err != nil {
+			return nil, err
+		}
+	}
+
+	return record, nil
+}
+
+// Complete сохраняет ответ и снимает блокировку с ключа
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	headersJSON, err := json.Marshal(record.ResponseHeaders)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE idempotency_keys
+		SET status = $1, response_status = $2, response_headers = $3, response_body = $4
+		WHERE user_id = $5 AND idempotency_key = $6 AND status = 'in_progress'
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		models.IdempotencyStatusCompleted, record.ResponseStatus, headersJSON, record.ResponseBody,
+		record.UserID, record.Key,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Release удаляет незавершенную запись, чтобы клиент мог повторить запрос с тем же ключом
+func (r *IdempotencyRepository) Release(ctx context.Context, userID uuid.UUID, key string) error {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE user_id = $1 AND idempotency_key = $2 AND status = 'in_progress'
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID, key)
+	return err
+}
+
+// DeleteExpired удаляет записи с истекшим сроком хранения
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	query := `DELETE FROM idempotency_keys WHERE expires_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,240 @@
+package routes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Политики авторизации маршрута
+const (
+	// AuthPublic - шлюз не проверяет токен, проверку выполняет сам сервис
+	AuthPublic = "public"
+	// AuthAuthenticated - шлюз пропускает только запросы с действительным токеном
+	AuthAuthenticated = "authenticated"
+)
+
+// Ключи ограничения частоты запросов
+const (
+	RateLimitByIP   = "ip"
+	RateLimitByUser = "user"
+)
+
+// Области кеширования ответов
+const (
+	// CacheScopePublic - один ответ для всех пользователей
+	CacheScopePublic = "public"
+	// CacheScopeUser - отдельный ответ для каждого пользователя
+	CacheScopeUser = "user"
+)
+
+// File - содержимое файла с таблицей маршрутов
+type File struct {
+	Routes []Route `yaml:"routes"`
+}
+
+// Route описывает маршрут API-шлюза
+type Route struct {
+	Name string `yaml:"name"`
+	// Prefix - префикс пути, например /api/v1/edu/
+	Prefix string `yaml:"prefix,omitempty"`
+	// Pattern - шаблон пути, например /api/v1/edu/courses/:id или /static/*path
+	Pattern string `yaml:"pattern,omitempty"`
+	// Methods - разрешенные методы, пустой список разрешает все
+	Methods []string `yaml:"methods,omitempty"`
+	// Upstream - сервис, к которому проксируется запрос: auth, edu или game
+	Upstream string `yaml:"upstream"`
+	// Rewrite - замена префикса или шаблон нового пути с параметрами шаблона
+	Rewrite   string        `yaml:"rewrite,omitempty"`
+	Auth      AuthPolicy    `yaml:"auth,omitempty"`
+	RateLimit *RateLimit    `yaml:"rate_limit,omitempty"`
+	Timeout   time.Duration `yaml:"timeout,omitempty"`
+	Cache     *CachePolicy  `yaml:"cache,omitempty"`
+	segments  []patternToken
+}
+
+// AuthPolicy описывает проверку доступа на шлюзе
+type AuthPolicy struct {
+	Policy string `yaml:"policy,omitempty" json:"policy"`
+	// Roles - роли, хотя бы одна из которых нужна для доступа
+	Roles []string `yaml:"roles,omitempty" json:"roles,omitempty"`
+}
+
+// RateLimit ограничивает количество запросов за период
+type RateLimit struct {
+	Requests int           `yaml:"requests"`
+	Period   time.Duration `yaml:"period"`
+	// Key - по чему считаются запросы: ip или user
+	Key string `yaml:"key,omitempty"`
+}
+
+// CachePolicy описывает кеширование успешных ответов на GET-запросы
+type CachePolicy struct {
+	TTL   time.Duration `yaml:"ttl"`
+	Scope string        `yaml:"scope,omitempty"`
+}
+
+// Parse разбирает и проверяет таблицу маршрутов. JSON является подмножеством YAML,
+// поэтому оба формата разбираются одинаково.
+func Parse(data []byte, upstreams []string) ([]Route, error) {
+	var file File
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("ошибка разбора файла маршрутов: %w", err)
+	}
+
+	if len(file.Routes) == 0 {
+		return nil, errors.New("в файле маршрутов нет ни одного маршрута")
+	}
+
+	known := make(map[string]bool, len(upstreams))
+	for _, name := range upstreams {
+		known[name] = true
+	}
+
+	names := make(map[string]bool, len(file.Routes))
+	var errs []error
+	for i := range file.Routes {
+		route := &file.Routes[i]
+		if err := route.normalize(known); err != nil {
+			errs = append(errs, fmt.Errorf("маршрут #%d (%s): %w", i+1, route.Name, err))
+			continue
+		}
+		if names[route.Name] {
+			errs = append(errs, fmt.Errorf("маршрут #%d: повторяющееся имя %s", i+1, route.Name))
+		}
+		names[route.Name] = true
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return file.Routes, nil
+}
+
+// normalize заполняет значения по умолчанию и проверяет маршрут
+func (r *Route) normalize(upstreams map[string]bool) error {
+	if r.Name == "" {
+		return errors.New("не указано имя")
+	}
+
+	switch {
+	case r.Prefix != "" && r.Pattern != "":
+		return errors.New("нужно указать prefix или pattern, но не оба")
+	case r.Prefix != "":
+		if !strings.HasPrefix(r.Prefix, "/") {
+			return fmt.Errorf("prefix должен начинаться с /: %s", r.Prefix)
+		}
+	case r.Pattern != "":
+		segments, err := compilePattern(r.Pattern)
+		if err != nil {
+			return err
+		}
+		r.segments = segments
+		if r.Rewrite != "" {
+			if err := checkTemplate(r.Rewrite, segments); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.New("не указан prefix или pattern")
+	}
+
+	if r.Rewrite != "" && !strings.HasPrefix(r.Rewrite, "/") {
+		return fmt.Errorf("rewrite должен начинаться с /: %s", r.Rewrite)
+	}
+
+	for i, method := range r.Methods {
+		method = strings.ToUpper(method)
+		if !validMethods[method] {
+			return fmt.Errorf("неизвестный метод %s", method)
+		}
+		r.Methods[i] = method
+	}
+
+	if !upstreams[r.Upstream] {
+		return fmt.Errorf("неизвестный сервис %q", r.Upstream)
+	}
+
+	if r.Auth.Policy == "" {
+		r.Auth.Policy = AuthPublic
+		if len(r.Auth.Roles) > 0 {
+			r.Auth.Policy = AuthAuthenticated
+		}
+	}
+	switch r.Auth.Policy {
+	case AuthPublic:
+		if len(r.Auth.Roles) > 0 {
+			return errors.New("для публичного маршрута нельзя указать роли")
+		}
+	case AuthAuthenticated:
+	default:
+		return fmt.Errorf("неизвестная политика авторизации %q", r.Auth.Policy)
+	}
+
+	if r.Timeout < 0 {
+		return errors.New("timeout не может быть отрицательным")
+	}
+
+	if r.RateLimit != nil {
+		if r.RateLimit.Requests < 1 || r.RateLimit.Period <= 0 {
+			return errors.New("rate_limit должен содержать положительные requests и period")
+		}
+		if r.RateLimit.Key == "" {
+			r.RateLimit.Key = RateLimitByIP
+		}
+		if r.RateLimit.Key != RateLimitByIP && r.RateLimit.Key != RateLimitByUser {
+			return fmt.Errorf("неизвестный ключ rate_limit %q", r.RateLimit.Key)
+		}
+		if r.RateLimit.Key == RateLimitByUser && r.Auth.Policy != AuthAuthenticated {
+			return errors.New("rate_limit по пользователю требует политику authenticated")
+		}
+	}
+
+	if r.Cache != nil {
+		if r.Cache.TTL <= 0 {
+			return errors.New("cache.ttl должен быть положительным")
+		}
+		if r.Cache.Scope == "" {
+			r.Cache.Scope = CacheScopePublic
+		}
+		if r.Cache.Scope != CacheScopePublic && r.Cache.Scope != CacheScopeUser {
+			return fmt.Errorf("неизвестная область кеширования %q", r.Cache.Scope)
+		}
+		if r.Cache.Scope == CacheScopeUser && r.Auth.Policy != AuthAuthenticated {
+			return errors.New("кеширование для пользователя требует политику authenticated")
+		}
+	}
+
+	return nil
+}
+
+// AllowsMethod сообщает, разрешен ли метод для маршрута
+func (r *Route) AllowsMethod(method string) bool {
+	if len(r.Methods) == 0 {
+		return true
+	}
+	for _, allowed := range r.Methods {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+var validMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
@@ -0,0 +1,108 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Store хранит действующую таблицу маршрутов и перезагружает ее из файла.
+// Запрос получает таблицу один раз в начале обработки, поэтому перезагрузка
+// не влияет на уже выполняющиеся запросы.
+type Store struct {
+	path      string
+	upstreams []string
+
+	current atomic.Pointer[Table]
+
+	// mu упорядочивает перезагрузки
+	mu   sync.Mutex
+	hash [sha256.Size]byte
+}
+
+// NewStore загружает таблицу маршрутов из файла. Ошибка в файле при запуске фатальна.
+func NewStore(path string, upstreams []string) (*Store, error) {
+	s := &Store{
+		path:      path,
+		upstreams: upstreams,
+	}
+
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Table возвращает действующую таблицу маршрутов
+func (s *Store) Table() *Table {
+	return s.current.Load()
+}
+
+// Reload перечитывает файл маршрутов. Если файл не изменился, таблица не заменяется.
+// При ошибке в файле остается прежняя таблица.
+func (s *Store) Reload() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return false, fmt.Errorf("ошибка чтения файла маршрутов: %w", err)
+	}
+
+	hash := sha256.Sum256(data)
+	if s.current.Load() != nil && bytes.Equal(hash[:], s.hash[:]) {
+		return false, nil
+	}
+
+	routes, err := Parse(data, s.upstreams)
+	if err != nil {
+		return false, fmt.Errorf("некорректный файл маршрутов %s: %w", s.path, err)
+	}
+
+	s.current.Store(NewTable(routes, s.path))
+	s.hash = hash
+
+	return true, nil
+}
+
+// Watch перезагружает таблицу по сигналу SIGHUP и при изменении файла до отмены контекста
+func (s *Store) Watch(ctx context.Context, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			slog.Info("получен SIGHUP, перезагружаем маршруты", slog.String("file", s.path))
+		case <-tick:
+		}
+
+		reloaded, err := s.Reload()
+		if err != nil {
+			slog.Error("маршруты не перезагружены, действует прежняя таблица", slog.Any("error", err))
+			continue
+		}
+		if reloaded {
+			slog.Info("маршруты перезагружены", slog.String("file", s.path), slog.Int("routes", len(s.Table().Routes)))
+		}
+	}
+}
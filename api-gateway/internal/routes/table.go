@@ -0,0 +1,255 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// patternToken - сегмент шаблона пути
+type patternToken struct {
+	literal string
+	// param - имя параметра для сегментов :name и *name
+	param string
+	// wildcard - сегмент *name, совпадающий с остатком пути
+	wildcard bool
+}
+
+// compilePattern разбирает шаблон вида /api/v1/edu/courses/:id/*rest
+func compilePattern(pattern string) ([]patternToken, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf("pattern должен начинаться с /: %s", pattern)
+	}
+
+	parts := strings.Split(strings.Trim(pattern, "/"), "/")
+	tokens := make([]patternToken, 0, len(parts))
+	params := make(map[string]bool)
+	for i, part := range parts {
+		switch {
+		case strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*"):
+			name := part[1:]
+			if name == "" {
+				return nil, fmt.Errorf("у параметра шаблона нет имени: %s", pattern)
+			}
+			if params[name] {
+				return nil, fmt.Errorf("повторяющийся параметр %s в шаблоне %s", name, pattern)
+			}
+			params[name] = true
+			wildcard := part[0] == '*'
+			if wildcard && i != len(parts)-1 {
+				return nil, fmt.Errorf("параметр *%s должен быть последним сегментом шаблона", name)
+			}
+			tokens = append(tokens, patternToken{param: name, wildcard: wildcard})
+		default:
+			tokens = append(tokens, patternToken{literal: part})
+		}
+	}
+
+	return tokens, nil
+}
+
+// checkTemplate проверяет, что шаблон переписывания использует только параметры шаблона пути
+func checkTemplate(template string, tokens []patternToken) error {
+	params := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		if token.param != "" {
+			params[token.param] = true
+		}
+	}
+
+	for _, part := range strings.Split(template, "/") {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			if !params[part[1:]] {
+				return fmt.Errorf("rewrite использует неизвестный параметр %s", part)
+			}
+		}
+	}
+
+	return nil
+}
+
+// matchPattern сопоставляет путь с шаблоном и возвращает значения параметров
+func matchPattern(tokens []patternToken, path string) (map[string]string, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	params := make(map[string]string)
+
+	for i, token := range tokens {
+		if token.wildcard {
+			params[token.param] = strings.Join(parts[i:], "/")
+			return params, true
+		}
+		if i >= len(parts) {
+			return nil, false
+		}
+		switch {
+		case token.param != "":
+			if parts[i] == "" {
+				return nil, false
+			}
+			params[token.param] = parts[i]
+		case token.literal != parts[i]:
+			return nil, false
+		}
+	}
+
+	if len(parts) != len(tokens) {
+		return nil, false
+	}
+
+	return params, true
+}
+
+// Match - найденный маршрут для запроса
+type Match struct {
+	Route *Route
+	// Path - путь запроса к сервису после переписывания
+	Path string
+}
+
+// ErrMethodNotAllowed возвращается, если путь найден, но метод для него не разрешен
+var ErrMethodNotAllowed = errors.New("method not allowed")
+
+// Table - проверенная таблица маршрутов. Маршруты проверяются в порядке из файла,
+// побеждает первое совпадение.
+type Table struct {
+	Routes   []Route
+	Source   string
+	LoadedAt time.Time
+}
+
+// NewTable создает таблицу из проверенных маршрутов
+func NewTable(routes []Route, source string) *Table {
+	return &Table{
+		Routes:   routes,
+		Source:   source,
+		LoadedAt: time.Now(),
+	}
+}
+
+// Match находит маршрут для запроса. Возвращает nil, если маршрут не найден,
+// и ErrMethodNotAllowed, если путь подходит только маршрутам с другими методами.
+func (t *Table) Match(method, path string) (*Match, error) {
+	pathMatched := false
+
+	for i := range t.Routes {
+		route := &t.Routes[i]
+
+		var rewritten string
+		if route.Prefix != "" {
+			if !strings.HasPrefix(path, route.Prefix) {
+				continue
+			}
+			rewritten = path
+			if route.Rewrite != "" {
+				rewritten = route.Rewrite + strings.TrimPrefix(path, route.Prefix)
+			}
+		} else {
+			params, ok := matchPattern(route.segments, path)
+			if !ok {
+				continue
+			}
+			rewritten = path
+			if route.Rewrite != "" {
+				rewritten = expandTemplate(route.Rewrite, params)
+			}
+		}
+
+		if !route.AllowsMethod(method) {
+			pathMatched = true
+			continue
+		}
+
+		return &Match{Route: route, Path: rewritten}, nil
+	}
+
+	if pathMatched {
+		return nil, ErrMethodNotAllowed
+	}
+	return nil, nil
+}
+
+// expandTemplate подставляет параметры шаблона пути в шаблон переписывания
+func expandTemplate(template string, params map[string]string) string {
+	parts := strings.Split(template, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			parts[i] = params[part[1:]]
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// RouteView - маршрут в административном API
+type RouteView struct {
+	Name      string         `json:"name"`
+	Prefix    string         `json:"prefix,omitempty"`
+	Pattern   string         `json:"pattern,omitempty"`
+	Methods   []string       `json:"methods,omitempty"`
+	Upstream  string         `json:"upstream"`
+	Rewrite   string         `json:"rewrite,omitempty"`
+	Auth      AuthPolicy     `json:"auth"`
+	RateLimit *RateLimitView `json:"rate_limit,omitempty"`
+	Timeout   string         `json:"timeout,omitempty"`
+	Cache     *CacheView     `json:"cache,omitempty"`
+}
+
+// RateLimitView - ограничение частоты запросов в административном API
+type RateLimitView struct {
+	Requests int    `json:"requests"`
+	Period   string `json:"period"`
+	Key      string `json:"key"`
+}
+
+// CacheView - политика кеширования в административном API
+type CacheView struct {
+	TTL   string `json:"ttl"`
+	Scope string `json:"scope"`
+}
+
+// TableView - таблица маршрутов в административном API
+type TableView struct {
+	Source   string      `json:"source"`
+	LoadedAt time.Time   `json:"loaded_at"`
+	Routes   []RouteView `json:"routes"`
+}
+
+// View возвращает представление таблицы для административного API
+func (t *Table) View() TableView {
+	view := TableView{
+		Source:   t.Source,
+		LoadedAt: t.LoadedAt,
+		Routes:   make([]RouteView, 0, len(t.Routes)),
+	}
+
+	for _, route := range t.Routes {
+		routeView := RouteView{
+			Name:     route.Name,
+			Prefix:   route.Prefix,
+			Pattern:  route.Pattern,
+			Methods:  route.Methods,
+			Upstream: route.Upstream,
+			Rewrite:  route.Rewrite,
+			Auth:     route.Auth,
+		}
+		if route.Timeout > 0 {
+			routeView.Timeout = route.Timeout.String()
+		}
+		if route.RateLimit != nil {
+			routeView.RateLimit = &RateLimitView{
+				Requests: route.RateLimit.Requests,
+				Period:   route.RateLimit.Period.String(),
+				Key:      route.RateLimit.Key,
+			}
+		}
+		if route.Cache != nil {
+			routeView.Cache = &CacheView{
+				TTL:   route.Cache.TTL.String(),
+				Scope: route.Cache.Scope,
+			}
+		}
+		view.Routes = append(view.Routes, routeView)
+	}
+
+	return view
+}
@@ -2,25 +2,50 @@ package main
 
 import (
 	"auth-service/internal/app"
+	"context"
+	"errors"
+	"io/fs"
 	"log"
+	"log/slog"
+	"os"
+	"platform/lifecycle"
+	"platform/logging"
+	"platform/tracing"
 
 	"github.com/joho/godotenv"
 )
 
 func init() {
-	if err := godotenv.Load(); err != nil {
+	// .env is optional: settings may also come from the config file, the environment or flags
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		log.Fatalf("can not read .env file: %v", err)
 	}
 }
 
 func main() {
-	a, err := app.New()
+	logger := logging.Setup("auth")
+
+	// The exporter is selected by OTEL_TRACES_EXPORTER
+	shutdownTracing, err := tracing.Setup(context.Background(), "auth")
 	if err != nil {
-		log.Fatalf("error creating an application instance: %s\n", err.Error())
+		logger.Error("tracing setup error", slog.Any("error", err))
+		os.Exit(1)
 	}
+	defer shutdownTracing(context.Background())
+
+	a, err := app.New(logger)
+	if err != nil {
+		logger.Error("error creating an application instance", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	// Shut down gracefully on SIGTERM or SIGINT
+	ctx, stop := lifecycle.SignalContext(context.Background())
+	defer stop()
 
-	err = a.Run()
+	err = a.Run(ctx)
 	if err != nil {
-		log.Fatalf("application startup error: %s\n", err.Error())
+		logger.Error("application startup error", slog.Any("error", err))
+		os.Exit(1)
 	}
 }
@@ -1,8 +1,13 @@
 package app
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"time"
 
@@ -14,16 +19,31 @@ import (
 	"auth-service/internal/services"
 	grpcserver "auth-service/internal/transport/grpc"
 	"auth-service/internal/transport/http/handler"
+	"platform/auth/authpb"
+	"platform/health"
+	"platform/lifecycle"
+	"platform/logging"
+	"platform/metrics"
+	"platform/problem"
+	"platform/tracing"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"google.golang.org/grpc"
 )
 
+// healthCheckInterval is how often the grpc.health.v1 status is refreshed
+const healthCheckInterval = 10 * time.Second
+
 type App struct {
 	cfg        *config.Config
-	httpServer *gin.Engine
+	logger     *slog.Logger
+	router     *gin.Engine
+	httpServer *http.Server
 	grpcServer *grpc.Server
+	db         *sql.DB
+	checker    *health.Checker
+	workers    *lifecycle.Workers
 }
 
 // @title Auth Service API
@@ -34,8 +54,8 @@ type App struct {
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
-func New() (*App, error) {
-	a := new(App)
+func New(logger *slog.Logger) (*App, error) {
+	a := &App{logger: logger, workers: lifecycle.NewWorkers()}
 	var err error
 
 	a.cfg, err = config.New()
@@ -45,7 +65,8 @@ func New() (*App, error) {
 
 	corsConfig := cors.Config{
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", logging.HeaderRequestID},
+		ExposeHeaders:    []string{logging.HeaderRequestID},
 		AllowCredentials: true,
 		AllowAllOrigins:  true,
 		MaxAge:           12 * time.Hour,
@@ -53,18 +74,31 @@ func New() (*App, error) {
 
 	gin.SetMode(os.Getenv("GIN_MODE"))
 
-	a.httpServer = gin.Default()
+	a.router = gin.New()
 
-	a.httpServer.Use(
+	a.router.Use(
 		cors.New(corsConfig),
-		gin.Recovery(),
+		tracing.Middleware(),
+		logging.Middleware(logger),
+		logging.Recovery(logger),
 	)
+	metrics.Setup(a.router)
+	problem.Setup(a.router)
+
+	// Health check for the gateway
+	a.router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status": "ok",
+		})
+	})
 
 	// Connect to database
 	db, err := database.NewPostgresDB(a.cfg.Database.URL)
 	if err != nil {
 		return nil, fmt.Errorf("database connection error: %w", err)
 	}
+	a.db = db
+	metrics.RegisterDB(db, "auth")
 
 	// Initialize repositories
 	userRepo := repositories.NewUserRepository(db)
@@ -87,33 +121,82 @@ func New() (*App, error) {
 	)
 
 	// Initialize gRPC server
-	a.grpcServer = grpcserver.NewServer(authService)
+	a.grpcServer = grpcserver.NewServer(authService, grpc.ChainUnaryInterceptor(
+		tracing.UnaryServerInterceptor(),
+		logging.UnaryServerInterceptor(logger),
+		metrics.UnaryServerInterceptor(),
+	))
+
+	// Liveness and readiness: /livez, /readyz and grpc.health.v1
+	a.checker = health.NewChecker(health.DefaultTimeout)
+	a.checker.Add("postgres", health.Postgres(db))
+	if a.cfg.SMTP.HealthCheck != config.SMTPHealthCheckOff {
+		a.checker.Add("smtp", emailService.Ping)
+	}
+	a.checker.Register(a.router)
+	grpcHealth := a.checker.RegisterGRPC(a.grpcServer, authpb.AuthService_ServiceDesc.ServiceName)
+	a.workers.Go(func(ctx context.Context) {
+		grpcHealth.Watch(ctx, healthCheckInterval)
+	})
 
 	// Initialize HTTP handlers
-	handler.NewHandler(a.httpServer, authService, a.cfg)
+	handler.NewHandler(a.router, authService, a.cfg)
+
+	a.httpServer = lifecycle.NewHTTPServer(a.cfg.Server.Port, a.router)
 
 	return a, nil
 }
 
-func (a *App) Run() error {
-	// Start gRPC server
+// Run starts the HTTP and gRPC servers and shuts them down once ctx is cancelled
+func (a *App) Run(ctx context.Context) error {
 	lis, err := net.Listen("tcp", ":"+a.cfg.Server.GRPCPort)
 	if err != nil {
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 
-	go func() {
-		fmt.Printf("Starting gRPC server on port %s\n", a.cfg.Server.GRPCPort)
+	serveGRPC := func() error {
+		a.logger.Info("starting gRPC server", slog.String("port", a.cfg.Server.GRPCPort))
 		if err := a.grpcServer.Serve(lis); err != nil {
-			fmt.Printf("gRPC server error: %s\n", err)
+			return fmt.Errorf("gRPC server error: %w", err)
 		}
-	}()
+		return nil
+	}
+	serveHTTP := func() error {
+		a.logger.Info("starting HTTP server", slog.String("port", a.cfg.Server.Port))
+		if err := lifecycle.ServeHTTP(a.httpServer); err != nil {
+			return fmt.Errorf("http server error: %w", err)
+		}
+		return nil
+	}
 
-	// Start HTTP server
-	fmt.Printf("Starting HTTP server on port %s\n", a.cfg.Server.Port)
-	if err := a.httpServer.Run(":" + a.cfg.Server.Port); err != nil {
-		return fmt.Errorf("http server error: %w", err)
+	return lifecycle.Run(ctx, a.cfg.Shutdown.Timeout, a.Stop, serveHTTP, serveGRPC)
+}
+
+// Stop marks the service not ready, waits DrainDelay for load balancers to notice,
+// drains in-flight HTTP requests and gRPC calls, stops background workers
+// and closes the database pool
+func (a *App) Stop(ctx context.Context) error {
+	a.logger.Info("shutting down", slog.Duration("drain_delay", a.cfg.Shutdown.DrainDelay))
+
+	a.checker.Drain()
+	a.httpServer.SetKeepAlivesEnabled(false)
+	lifecycle.Wait(ctx, a.cfg.Shutdown.DrainDelay)
+
+	var errs []error
+	if err := a.httpServer.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("http server shutdown: %w", err))
+	}
+	lifecycle.StopGRPC(ctx, a.grpcServer)
+	if err := a.workers.Stop(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("background workers shutdown: %w", err))
+	}
+	if err := a.db.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("database close: %w", err))
 	}
 
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	a.logger.Info("shutdown complete")
 	return nil
 }
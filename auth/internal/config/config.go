@@ -2,100 +2,95 @@ package config
 
 import (
 	"os"
-	"strconv"
 	"time"
+
+	"platform/config"
 )
 
 type Config struct {
-	Server    ServerConfig
-	Database  DatabaseConfig
-	Token     TokenConfig
-	SMTP      SMTPConfig
-	OAuth     OAuthConfig
-	RateLimit RateLimitConfig
-	Security  SecurityConfig
+	Server       ServerConfig       `yaml:"server"`
+	Database     DatabaseConfig     `yaml:"database"`
+	Token        TokenConfig        `yaml:"token"`
+	Verification VerificationConfig `yaml:"verification"`
+	SMTP         SMTPConfig         `yaml:"smtp"`
+	OAuth        OAuthConfig        `yaml:"oauth"`
+	RateLimit    RateLimitConfig    `yaml:"rate_limit"`
+	Security     SecurityConfig     `yaml:"security"`
+	Shutdown     ShutdownConfig     `yaml:"shutdown"`
 }
 
 type ServerConfig struct {
-	Port     string
-	GRPCPort string
+	Port     string `yaml:"port" env:"SERVER_PORT" default:"8080" validate:"required"`
+	GRPCPort string `yaml:"grpc_port" env:"GRPC_PORT" default:"9090" validate:"required"`
+}
+
+type ShutdownConfig struct {
+	// Timeout is the total time allowed for shutdown, including DrainDelay
+	Timeout time.Duration `yaml:"timeout" env:"SHUTDOWN_TIMEOUT" default:"20s" validate:"gt=0"`
+	// DrainDelay is how long readiness reports not-ready before the servers stop accepting connections
+	DrainDelay time.Duration `yaml:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY" default:"5s" validate:"gte=0,ltefield=Timeout"`
 }
 
 type DatabaseConfig struct {
-	URL string
+	URL string `yaml:"url" env:"DB_URL" validate:"required"`
 }
 
 type TokenConfig struct {
-	Secret     string
-	AccessTTL  time.Duration
-	RefreshTTL time.Duration
-	PepperStr  string
+	// Secret signs access tokens
+	Secret     string        `yaml:"secret" env:"JWT_SECRET" secret:"true" validate:"required,min=32"`
+	AccessTTL  time.Duration `yaml:"access_ttl" env:"ACCESS_TOKEN_TTL" default:"1h" validate:"gt=0"`
+	RefreshTTL time.Duration `yaml:"refresh_ttl" env:"REFRESH_TOKEN_TTL" default:"168h" validate:"gtfield=AccessTTL"`
+	// PepperStr is embedded in access tokens and checked on validation
+	PepperStr string `yaml:"pepper" env:"PEPPER_STRING" secret:"true" validate:"required"`
+}
+
+type VerificationConfig struct {
+	// CodeTTL is how long an email verification or password reset code stays valid
+	CodeTTL time.Duration `yaml:"code_ttl" env:"VERIFICATION_CODE_TTL" default:"5m" validate:"gt=0"`
 }
 
 type SMTPConfig struct {
-	Host      string
-	Port      int
-	Username  string
-	Password  string
-	FromEmail string
+	Host      string `yaml:"host" env:"SMTP_HOST" default:"smtp.gmail.com" validate:"required"`
+	Port      int    `yaml:"port" env:"SMTP_PORT" default:"587" validate:"gt=0,lte=65535"`
+	Username  string `yaml:"username" env:"SMTP_USERNAME"`
+	Password  string `yaml:"password" env:"SMTP_PASSWORD" secret:"true"`
+	FromEmail string `yaml:"from_email" env:"SMTP_FROM_EMAIL"`
+	// HealthCheck selects how readiness checks the SMTP server: tcp, smtp or off
+	HealthCheck string `yaml:"health_check" env:"SMTP_HEALTH_CHECK" default:"tcp" validate:"oneof=tcp smtp off"`
 }
 
+// SMTP health check modes
+const (
+	// SMTPHealthCheckTCP only checks that the SMTP port accepts connections
+	SMTPHealthCheckTCP = "tcp"
+	// SMTPHealthCheckSMTP also waits for the greeting and exchanges EHLO and QUIT
+	SMTPHealthCheckSMTP = "smtp"
+	// SMTPHealthCheckOff excludes SMTP from readiness
+	SMTPHealthCheckOff = "off"
+)
+
 type OAuthConfig struct {
-	GoogleClientID     string
-	GoogleClientSecret string
+	GoogleClientID     string `yaml:"google_client_id" env:"GOOGLE_CLIENT_ID"`
+	GoogleClientSecret string `yaml:"google_client_secret" env:"GOOGLE_SECRET" secret:"true"`
 }
 
+// RateLimitConfig limits requests per client IP to the public auth endpoints
 type RateLimitConfig struct {
-	Requests int
-	Period   time.Duration
+	Requests int           `yaml:"requests" env:"RATE_LIMIT_REQUESTS" default:"50" validate:"gt=0"`
+	Period   time.Duration `yaml:"period" env:"RATE_LIMIT_PERIOD" default:"1m" validate:"gt=0"`
 }
 
 type SecurityConfig struct {
-	PasswordPepper string `env:"PASSWORD_PEPPER" envDefault:"your-default-pepper-key-replace-in-production"`
+	// PasswordPepper is mixed into password hashes. Changing it invalidates all stored passwords.
+	PasswordPepper string `yaml:"password_pepper" env:"PASSWORD_PEPPER" secret:"true" validate:"required,min=16"`
 }
 
+// New loads the configuration from defaults, the optional YAML file, environment variables
+// and command line flags. Missing secrets and invalid values are reported together.
 func New() (*Config, error) {
-	return &Config{
-		Server: ServerConfig{
-			Port:     getEnvOrDefault("SERVER_PORT", "8080"),
-			GRPCPort: getEnvOrDefault("GRPC_PORT", "9090"),
-		},
-		Database: DatabaseConfig{
-			URL: os.Getenv("DB_URL"),
-		},
-		Token: TokenConfig{
-			Secret:     os.Getenv("JWT_SECRET"),
-			AccessTTL:  time.Hour,
-			RefreshTTL: time.Hour * 168, // 7 days
-			PepperStr:  os.Getenv("PEPPER_STRING"),
-		},
-		SMTP: SMTPConfig{
-			Host: getEnvOrDefault("SMTP_HOST", "smtp.gmail.com"),
-			Port: func(port string) int {
-				smtpPort, _ := strconv.Atoi(getEnvOrDefault("SMTP_PORT", "587"))
-				return smtpPort
-			}(getEnvOrDefault("SMTP_PORT", "587")),
-			Username:  os.Getenv("SMTP_USERNAME"),
-			Password:  os.Getenv("SMTP_PASSWORD"),
-			FromEmail: os.Getenv("SMTP_FROM_EMAIL"),
-		},
-		OAuth: OAuthConfig{
-			GoogleClientID:     os.Getenv("GOOGLE_CLIENT_ID"),
-			GoogleClientSecret: os.Getenv("GOOGLE_SECRET"),
-		},
-		RateLimit: RateLimitConfig{
-			Requests: 50,
-			Period:   time.Minute,
-		},
-		Security: SecurityConfig{
-			PasswordPepper: getEnvOrDefault("PASSWORD_PEPPER", "your-default-pepper-key-replace-in-production"),
-		},
-	}, nil
-}
-
-func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+	cfg := &Config{}
+	if err := config.Load(cfg, os.Args[1:]); err != nil {
+		return nil, err
 	}
-	return defaultValue
+	return cfg, nil
 }
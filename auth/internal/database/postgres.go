@@ -3,12 +3,13 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"platform/tracing"
 
 	_ "github.com/lib/pq"
 )
 
 func NewPostgresDB(url string) (*sql.DB, error) {
-	db, err := sql.Open("postgres", url)
+	db, err := tracing.OpenDB("postgres", url)
 	if err != nil {
 		return nil, fmt.Errorf("error connecting to database: %w", err)
 	}
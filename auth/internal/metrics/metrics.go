@@ -0,0 +1,30 @@
+// Package metrics contains business metrics of the auth service.
+// HTTP, gRPC and database metrics are collected by platform/metrics.
+package metrics
+
+import (
+	platform "platform/metrics"
+)
+
+const subsystem = "auth"
+
+// Values of the result label
+const (
+	ResultSuccess = "success"
+	ResultFailed  = "failed"
+	ResultSent    = "sent"
+)
+
+var (
+	// Registrations counts created user accounts
+	Registrations = platform.NewCounter(subsystem, "registrations_total",
+		"Number of created user accounts.")
+
+	// Logins counts login attempts by result. A login succeeds when the emailed code is accepted.
+	Logins = platform.NewCounterVec(subsystem, "logins_total",
+		"Number of login attempts by result.", "result")
+
+	// VerificationEmails counts verification emails by code type and delivery result
+	VerificationEmails = platform.NewCounterVec(subsystem, "verification_emails_total",
+		"Number of verification emails by code type and result.", "type", "result")
+)
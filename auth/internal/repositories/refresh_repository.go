@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -17,8 +18,8 @@ func NewRefreshRepository(db *sql.DB) *RefreshRepository {
 	return &RefreshRepository{db: db}
 }
 
-func (r *RefreshRepository) Create(session *models.RefreshSession) error {
-	_, err := r.db.Exec(`
+func (r *RefreshRepository) Create(ctx context.Context, session *models.RefreshSession) error {
+	_, err := r.db.ExecContext(ctx, `
 		INSERT INTO refresh_sessions (id, user_id, refresh_token, expires_at, created_at)
 		VALUES ($1, $2, $3, $4, $5)
 	`, session.ID, session.UserID, session.RefreshToken, session.ExpiresAt, session.CreatedAt)
@@ -30,9 +31,9 @@ func (r *RefreshRepository) Create(session *models.RefreshSession) error {
 	return nil
 }
 
-func (r *RefreshRepository) GetByToken(token string) (*models.RefreshSession, error) {
+func (r *RefreshRepository) GetByToken(ctx context.Context, token string) (*models.RefreshSession, error) {
 	var session models.RefreshSession
-	err := r.db.QueryRow(`
+	err := r.db.QueryRowContext(ctx, `
 		SELECT id, user_id, refresh_token, expires_at, created_at
 		FROM refresh_sessions
 		WHERE refresh_token = $1
@@ -54,8 +55,8 @@ func (r *RefreshRepository) GetByToken(token string) (*models.RefreshSession, er
 	return &session, nil
 }
 
-func (r *RefreshRepository) Update(sessionID uuid.UUID, newToken string, expiresAt int64) error {
-	result, err := r.db.Exec(`
+func (r *RefreshRepository) Update(ctx context.Context, sessionID uuid.UUID, newToken string, expiresAt int64) error {
+	result, err := r.db.ExecContext(ctx, `
 		UPDATE refresh_sessions 
 		SET refresh_token = $1, expires_at = $2
 		WHERE id = $3
@@ -77,8 +78,8 @@ func (r *RefreshRepository) Update(sessionID uuid.UUID, newToken string, expires
 	return nil
 }
 
-func (r *RefreshRepository) Delete(sessionID uuid.UUID) error {
-	_, err := r.db.Exec(`
+func (r *RefreshRepository) Delete(ctx context.Context, sessionID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
 		DELETE FROM refresh_sessions WHERE id = $1
 	`, sessionID)
 
@@ -89,8 +90,8 @@ func (r *RefreshRepository) Delete(sessionID uuid.UUID) error {
 	return nil
 }
 
-func (r *RefreshRepository) DeleteExpired(currentTime int64) error {
-	_, err := r.db.Exec(`
+func (r *RefreshRepository) DeleteExpired(ctx context.Context, currentTime int64) error {
+	_, err := r.db.ExecContext(ctx, `
 		DELETE FROM refresh_sessions WHERE expires_at < $1
 	`, currentTime)
 
@@ -101,8 +102,8 @@ func (r *RefreshRepository) DeleteExpired(currentTime int64) error {
 	return nil
 }
 
-func (r *RefreshRepository) DeleteUserSessions(userID uuid.UUID) error {
-	_, err := r.db.Exec(`
+func (r *RefreshRepository) DeleteUserSessions(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
 		DELETE FROM refresh_sessions WHERE user_id = $1
 	`, userID)
 
@@ -113,8 +114,8 @@ func (r *RefreshRepository) DeleteUserSessions(userID uuid.UUID) error {
 	return nil
 }
 
-func (r *RefreshRepository) DeleteAllUserSessions(userID uuid.UUID) error {
+func (r *RefreshRepository) DeleteAllUserSessions(ctx context.Context, userID uuid.UUID) error {
 	query := `DELETE FROM refresh_sessions WHERE user_id = $1`
-	_, err := r.db.Exec(query, userID)
+	_, err := r.db.ExecContext(ctx, query, userID)
 	return err
 }
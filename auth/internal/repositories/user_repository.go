@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -17,8 +18,8 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) Create(user *models.User) error {
-	_, err := r.db.Exec(`
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	_, err := r.db.ExecContext(ctx, `
 		INSERT INTO users (id, email, password_hash, role, created_at, password_changed_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
 	`, user.ID, user.Email, user.PasswordHash, user.Role, user.CreatedAt, user.CreatedAt)
@@ -30,9 +31,9 @@ func (r *UserRepository) Create(user *models.User) error {
 	return nil
 }
 
-func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	var user models.User
-	err := r.db.QueryRow(`
+	err := r.db.QueryRowContext(ctx, `
 		SELECT id, email, password_hash, role, confirmed, google_id, created_at, password_changed_at
 		FROM users WHERE id = $1
 	`, id).Scan(
@@ -56,9 +57,9 @@ func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	err := r.db.QueryRow(`
+	err := r.db.QueryRowContext(ctx, `
 		SELECT id, email, password_hash, role, confirmed, google_id, created_at, password_changed_at
 		FROM users WHERE email = $1
 	`, email).Scan(
@@ -82,9 +83,9 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *UserRepository) CheckEmailExists(email string) (bool, error) {
+func (r *UserRepository) CheckEmailExists(ctx context.Context, email string) (bool, error) {
 	var exists bool
-	err := r.db.QueryRow(`
+	err := r.db.QueryRowContext(ctx, `
 		SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)
 	`, email).Scan(&exists)
 
@@ -95,8 +96,8 @@ func (r *UserRepository) CheckEmailExists(email string) (bool, error) {
 	return exists, nil
 }
 
-func (r *UserRepository) UpdateConfirmation(userID uuid.UUID) error {
-	_, err := r.db.Exec(`
+func (r *UserRepository) UpdateConfirmation(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
 		UPDATE users SET confirmed = true WHERE id = $1
 	`, userID)
 
@@ -107,8 +108,8 @@ func (r *UserRepository) UpdateConfirmation(userID uuid.UUID) error {
 	return nil
 }
 
-func (r *UserRepository) UpdateGoogleID(userID uuid.UUID, googleID string) error {
-	_, err := r.db.Exec(`
+func (r *UserRepository) UpdateGoogleID(ctx context.Context, userID uuid.UUID, googleID string) error {
+	_, err := r.db.ExecContext(ctx, `
 		UPDATE users SET google_id = $1 WHERE id = $2
 	`, googleID, userID)
 
@@ -119,14 +120,14 @@ func (r *UserRepository) UpdateGoogleID(userID uuid.UUID, googleID string) error
 	return nil
 }
 
-func (r *UserRepository) UpdatePassword(userID uuid.UUID, hashedPassword string) error {
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, hashedPassword string) error {
 	query := `UPDATE users SET password_hash = $1, password_changed_at = NOW() WHERE id = $2`
-	_, err := r.db.Exec(query, hashedPassword, userID)
+	_, err := r.db.ExecContext(ctx, query, hashedPassword, userID)
 	return err
 }
 
-func (r *UserRepository) Update(user *models.User) error {
-	_, err := r.db.Exec(`
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	_, err := r.db.ExecContext(ctx, `
 		UPDATE users 
 		SET password_hash = $1, 
 			password_changed_at = $2
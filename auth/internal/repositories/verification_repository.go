@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"auth-service/internal/models"
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -17,8 +18,8 @@ func NewVerificationRepository(db *sql.DB) *VerificationRepository {
 	return &VerificationRepository{db: db}
 }
 
-func (r *VerificationRepository) Create(verification *models.VerificationCode) error {
-	_, err := r.db.Exec(`
+func (r *VerificationRepository) Create(ctx context.Context, verification *models.VerificationCode) error {
+	_, err := r.db.ExecContext(ctx, `
 		INSERT INTO verification_codes (id, user_id, email, code, type, expires_at, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`, verification.ID, verification.UserID, verification.Email, verification.Code,
@@ -31,7 +32,7 @@ func (r *VerificationRepository) Create(verification *models.VerificationCode) e
 	return nil
 }
 
-func (r *VerificationRepository) GetActiveCode(email string, verificationType models.VerificationType) (*models.VerificationCode, error) {
+func (r *VerificationRepository) GetActiveCode(ctx context.Context, email string, verificationType models.VerificationType) (*models.VerificationCode, error) {
 	var code models.VerificationCode
 	query := `
 		SELECT id, user_id, email, code, type, used, expires_at, created_at 
@@ -43,7 +44,7 @@ func (r *VerificationRepository) GetActiveCode(email string, verificationType mo
 		ORDER BY created_at DESC 
 		LIMIT 1
 	`
-	err := r.db.QueryRow(query, email, verificationType).Scan(
+	err := r.db.QueryRowContext(ctx, query, email, verificationType).Scan(
 		&code.ID,
 		&code.UserID,
 		&code.Email,
@@ -62,8 +63,8 @@ func (r *VerificationRepository) GetActiveCode(email string, verificationType mo
 	return &code, nil
 }
 
-func (r *VerificationRepository) DeleteExpiredCodes() error {
-	_, err := r.db.Exec(`
+func (r *VerificationRepository) DeleteExpiredCodes(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
 		DELETE FROM verification_codes
 		WHERE expires_at <= $1
 	`, time.Now())
@@ -75,8 +76,8 @@ func (r *VerificationRepository) DeleteExpiredCodes() error {
 	return nil
 }
 
-func (r *VerificationRepository) MarkAsUsed(id uuid.UUID) error {
+func (r *VerificationRepository) MarkAsUsed(ctx context.Context, id uuid.UUID) error {
 	query := `UPDATE verification_codes SET used = TRUE WHERE id = $1`
-	_, err := r.db.Exec(query, id)
+	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
@@ -1,11 +1,13 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
 
 	"auth-service/internal/config"
+	"auth-service/internal/metrics"
 	"auth-service/internal/models"
 	"auth-service/internal/repositories"
 	"auth-service/internal/security/jwt"
@@ -60,14 +62,14 @@ func NewAuthService(
 	}
 }
 
-func (s *AuthService) ValidateToken(tokenString string) (*models.TokenClaims, error) {
+func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*models.TokenClaims, error) {
 	claims, err := s.tokenManager.ParseAccessToken(tokenString)
 	if err != nil {
 		return nil, err
 	}
 
 	// Получаем актуальные данные пользователя из БД
-	user, err := s.userRepo.GetByID(claims.UserID)
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting user: %w", err)
 	}
@@ -80,15 +82,15 @@ func (s *AuthService) ValidateToken(tokenString string) (*models.TokenClaims, er
 	return claims, nil
 }
 
-func (s *AuthService) Register(input *models.UserCreate) (*models.User, error) {
-	exists, err := s.userRepo.CheckEmailExists(input.Email)
+func (s *AuthService) Register(ctx context.Context, input *models.UserCreate) (*models.User, error) {
+	exists, err := s.userRepo.CheckEmailExists(ctx, input.Email)
 	if err != nil {
 		return nil, fmt.Errorf("error checking email existence: %w", err)
 	}
 
 	if exists {
 		// Проверяем, подтверждена ли почта существующего пользователя
-		existingUser, err := s.userRepo.GetByEmail(input.Email)
+		existingUser, err := s.userRepo.GetByEmail(ctx, input.Email)
 		if err != nil {
 			return nil, fmt.Errorf("error getting user by email: %w", err)
 		}
@@ -103,12 +105,12 @@ func (s *AuthService) Register(input *models.UserCreate) (*models.User, error) {
 			existingUser.PasswordHash = hashedPassword
 			existingUser.PasswordChangedAt = time.Now()
 
-			if err := s.userRepo.Update(existingUser); err != nil {
+			if err := s.userRepo.Update(ctx, existingUser); err != nil {
 				return nil, fmt.Errorf("error updating user: %w", err)
 			}
 
 			// Отправляем новый код подтверждения
-			if err := s.sendVerificationCode(existingUser.ID, existingUser.Email, models.VerificationTypeRegistration); err != nil {
+			if err := s.sendVerificationCode(ctx, existingUser.ID, existingUser.Email, models.VerificationTypeRegistration); err != nil {
 				return nil, fmt.Errorf("error sending verification code: %w", err)
 			}
 
@@ -134,28 +136,31 @@ func (s *AuthService) Register(input *models.UserCreate) (*models.User, error) {
 		PasswordChangedAt: now,
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, fmt.Errorf("error creating user: %w", err)
 	}
+	metrics.Registrations.Inc()
 
 	// Отправляем код подтверждения
-	if err := s.sendVerificationCode(user.ID, user.Email, models.VerificationTypeRegistration); err != nil {
+	if err := s.sendVerificationCode(ctx, user.ID, user.Email, models.VerificationTypeRegistration); err != nil {
 		return nil, fmt.Errorf("error sending verification code: %w", err)
 	}
 
 	return user, nil
 }
 
-func (s *AuthService) Login(input *models.UserLogin) (*models.TokenPair, error) {
-	user, err := s.userRepo.GetByEmail(input.Email)
+func (s *AuthService) Login(ctx context.Context, input *models.UserLogin) (*models.TokenPair, error) {
+	user, err := s.userRepo.GetByEmail(ctx, input.Email)
 	if err != nil {
 		return nil, fmt.Errorf("error getting user: %w", err)
 	}
 	if user == nil {
+		metrics.Logins.WithLabelValues(metrics.ResultFailed).Inc()
 		return nil, ErrUserNotFound
 	}
 
 	if err := s.passwordHasher.Compare(input.Password, user.PasswordHash); err != nil {
+		metrics.Logins.WithLabelValues(metrics.ResultFailed).Inc()
 		return nil, ErrInvalidPassword
 	}
 
@@ -164,23 +169,30 @@ func (s *AuthService) Login(input *models.UserLogin) (*models.TokenPair, error)
 	}
 
 	// Отправляем код подтверждения для входа
-	if err := s.sendVerificationCode(user.ID, user.Email, models.VerificationTypeLogin); err != nil {
+	if err := s.sendVerificationCode(ctx, user.ID, user.Email, models.VerificationTypeLogin); err != nil {
 		return nil, fmt.Errorf("error sending verification code: %w", err)
 	}
 
 	return nil, ErrVerificationSent
 }
 
-func (s *AuthService) VerifyEmail(email, code string) (*models.TokenPair, error) {
-	return s.verifyCode(email, code, models.VerificationTypeRegistration)
+func (s *AuthService) VerifyEmail(ctx context.Context, email, code string) (*models.TokenPair, error) {
+	return s.verifyCode(ctx, email, code, models.VerificationTypeRegistration)
 }
 
-func (s *AuthService) VerifyLogin(email, code string) (*models.TokenPair, error) {
-	return s.verifyCode(email, code, models.VerificationTypeLogin)
+func (s *AuthService) VerifyLogin(ctx context.Context, email, code string) (*models.TokenPair, error) {
+	tokens, err := s.verifyCode(ctx, email, code, models.VerificationTypeLogin)
+	switch {
+	case err == nil:
+		metrics.Logins.WithLabelValues(metrics.ResultSuccess).Inc()
+	case errors.Is(err, ErrInvalidCode), errors.Is(err, ErrCodeExpired):
+		metrics.Logins.WithLabelValues(metrics.ResultFailed).Inc()
+	}
+	return tokens, err
 }
 
-func (s *AuthService) verifyCode(email, code string, verificationType models.VerificationType) (*models.TokenPair, error) {
-	verificationCode, err := s.verificationRepo.GetActiveCode(email, verificationType)
+func (s *AuthService) verifyCode(ctx context.Context, email, code string, verificationType models.VerificationType) (*models.TokenPair, error) {
+	verificationCode, err := s.verificationRepo.GetActiveCode(ctx, email, verificationType)
 	if err != nil {
 		return nil, fmt.Errorf("error getting verification code: %w", err)
 	}
@@ -193,18 +205,18 @@ func (s *AuthService) verifyCode(email, code string, verificationType models.Ver
 	}
 
 	// Помечаем код как использованный
-	if err := s.verificationRepo.MarkAsUsed(verificationCode.ID); err != nil {
+	if err := s.verificationRepo.MarkAsUsed(ctx, verificationCode.ID); err != nil {
 		return nil, fmt.Errorf("error marking code as used: %w", err)
 	}
 
-	user, err := s.userRepo.GetByID(verificationCode.UserID)
+	user, err := s.userRepo.GetByID(ctx, verificationCode.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting user: %w", err)
 	}
 
 	// Если это подтверждение регистрации, подтверждаем email
 	if verificationType == models.VerificationTypeRegistration {
-		if err := s.userRepo.UpdateConfirmation(user.ID); err != nil {
+		if err := s.userRepo.UpdateConfirmation(ctx, user.ID); err != nil {
 			return nil, fmt.Errorf("error confirming email: %w", err)
 		}
 	}
@@ -229,7 +241,7 @@ func (s *AuthService) verifyCode(email, code string, verificationType models.Ver
 		CreatedAt:    time.Now().Unix(),
 	}
 
-	if err := s.refreshRepo.Create(session); err != nil {
+	if err := s.refreshRepo.Create(ctx, session); err != nil {
 		return nil, fmt.Errorf("error saving refresh session: %w", err)
 	}
 
@@ -239,7 +251,7 @@ func (s *AuthService) verifyCode(email, code string, verificationType models.Ver
 	}, nil
 }
 
-func (s *AuthService) sendVerificationCode(userID uuid.UUID, email string, verificationType models.VerificationType) error {
+func (s *AuthService) sendVerificationCode(ctx context.Context, userID uuid.UUID, email string, verificationType models.VerificationType) error {
 	code, err := utils.GenerateVerificationCode()
 	if err != nil {
 		return fmt.Errorf("error generating verification code: %w", err)
@@ -252,25 +264,27 @@ func (s *AuthService) sendVerificationCode(userID uuid.UUID, email string, verif
 		Code:      code,
 		Type:      verificationType,
 		Used:      false,
-		ExpiresAt: time.Now().Add(5 * time.Minute),
+		ExpiresAt: time.Now().Add(s.cfg.Verification.CodeTTL),
 		CreatedAt: time.Now(),
 	}
 
-	if err := s.verificationRepo.Create(verificationCode); err != nil {
+	if err := s.verificationRepo.Create(ctx, verificationCode); err != nil {
 		return fmt.Errorf("error saving verification code: %w", err)
 	}
 
 	if err := s.emailService.SendVerificationCode(email, code, verificationType); err != nil {
+		metrics.VerificationEmails.WithLabelValues(string(verificationType), metrics.ResultFailed).Inc()
 		return fmt.Errorf("error sending verification email: %w", err)
 	}
+	metrics.VerificationEmails.WithLabelValues(string(verificationType), metrics.ResultSent).Inc()
 
 	return nil
 }
 
 // RefreshTokens обновляет пару токенов с помощью refresh token
-func (s *AuthService) RefreshTokens(refreshToken string) (*models.TokenPair, error) {
+func (s *AuthService) RefreshTokens(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
 	// Проверяем refresh token в базе данных
-	session, err := s.refreshRepo.GetByToken(refreshToken)
+	session, err := s.refreshRepo.GetByToken(ctx, refreshToken)
 	if err != nil {
 		return nil, ErrInvalidRefreshToken
 	}
@@ -278,12 +292,12 @@ func (s *AuthService) RefreshTokens(refreshToken string) (*models.TokenPair, err
 	// Проверяем срок действия refresh token
 	if time.Now().Unix() > session.ExpiresAt {
 		// Удаляем просроченный токен
-		_ = s.refreshRepo.Delete(session.ID)
+		_ = s.refreshRepo.Delete(ctx, session.ID)
 		return nil, ErrRefreshTokenExpired
 	}
 
 	// Получаем пользователя
-	user, err := s.userRepo.GetByID(session.UserID)
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
 	if err != nil {
 		return nil, ErrUserNotFound
 	}
@@ -298,7 +312,7 @@ func (s *AuthService) RefreshTokens(refreshToken string) (*models.TokenPair, err
 	expiresAt := time.Now().Add(s.cfg.Token.RefreshTTL).Unix()
 
 	// Обновляем refresh token в базе данных
-	err = s.refreshRepo.Update(session.ID, newRefreshToken, expiresAt)
+	err = s.refreshRepo.Update(ctx, session.ID, newRefreshToken, expiresAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update refresh token: %w", err)
 	}
@@ -313,8 +327,8 @@ func (s *AuthService) createAccessToken(user *models.User) (string, error) {
 	return s.tokenManager.GenerateAccessToken(user.ID, user.Role, user.PasswordChangedAt)
 }
 
-func (s *AuthService) InitiatePasswordReset(email string) error {
-	user, err := s.userRepo.GetByEmail(email)
+func (s *AuthService) InitiatePasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		return fmt.Errorf("error getting user: %w", err)
 	}
@@ -323,15 +337,15 @@ func (s *AuthService) InitiatePasswordReset(email string) error {
 	}
 
 	// Отправляем код подтверждения для сброса пароля
-	if err := s.sendVerificationCode(user.ID, user.Email, models.VerificationTypePassword); err != nil {
+	if err := s.sendVerificationCode(ctx, user.ID, user.Email, models.VerificationTypePassword); err != nil {
 		return fmt.Errorf("error sending verification code: %w", err)
 	}
 
 	return nil
 }
 
-func (s *AuthService) ResetPassword(email, code, newPassword string) error {
-	verificationCode, err := s.verificationRepo.GetActiveCode(email, models.VerificationTypePassword)
+func (s *AuthService) ResetPassword(ctx context.Context, email, code, newPassword string) error {
+	verificationCode, err := s.verificationRepo.GetActiveCode(ctx, email, models.VerificationTypePassword)
 	if err != nil {
 		return fmt.Errorf("error getting verification code: %w", err)
 	}
@@ -344,7 +358,7 @@ func (s *AuthService) ResetPassword(email, code, newPassword string) error {
 	}
 
 	// Помечаем код как использованный
-	if err := s.verificationRepo.MarkAsUsed(verificationCode.ID); err != nil {
+	if err := s.verificationRepo.MarkAsUsed(ctx, verificationCode.ID); err != nil {
 		return fmt.Errorf("error marking code as used: %w", err)
 	}
 
@@ -355,12 +369,12 @@ func (s *AuthService) ResetPassword(email, code, newPassword string) error {
 	}
 
 	// Обновляем пароль пользователя
-	if err := s.userRepo.UpdatePassword(verificationCode.UserID, hashedPassword); err != nil {
+	if err := s.userRepo.UpdatePassword(ctx, verificationCode.UserID, hashedPassword); err != nil {
 		return fmt.Errorf("error updating password: %w", err)
 	}
 
 	// Удаляем все активные сессии пользователя
-	if err := s.refreshRepo.DeleteAllUserSessions(verificationCode.UserID); err != nil {
+	if err := s.refreshRepo.DeleteAllUserSessions(ctx, verificationCode.UserID); err != nil {
 		return fmt.Errorf("error deleting user sessions: %w", err)
 	}
 
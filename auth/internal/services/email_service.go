@@ -3,8 +3,11 @@ package services
 import (
 	"auth-service/internal/config"
 	"auth-service/internal/models"
+	"context"
 	"fmt"
+	"net"
 	"net/smtp"
+	"strconv"
 )
 
 const emailTemplate = `<!DOCTYPE html>
@@ -104,3 +107,32 @@ func (s *EmailService) SendVerificationCode(to string, code string, verification
 	addr := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
 	return smtp.SendMail(addr, auth, s.config.FromEmail, []string{to}, []byte(message))
 }
+
+// Ping checks that the SMTP server is reachable according to the configured health check mode
+func (s *EmailService) Ping(ctx context.Context) error {
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if s.config.HealthCheck != config.SMTPHealthCheckSMTP {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		return fmt.Errorf("smtp greeting: %w", err)
+	}
+	if err := client.Hello("localhost"); err != nil {
+		return fmt.Errorf("smtp ehlo: %w", err)
+	}
+	return client.Quit()
+}
@@ -3,10 +3,11 @@ package grpc
 import (
 	"auth-service/internal/services"
 	"context"
+	"platform/auth/authpb"
 )
 
 type AuthServer struct {
-	UnimplementedAuthServiceServer
+	authpb.UnimplementedAuthServiceServer
 	authService *services.AuthService
 }
 
@@ -16,20 +17,21 @@ func NewAuthServer(authService *services.AuthService) *AuthServer {
 	}
 }
 
-func (s *AuthServer) CheckAccess(ctx context.Context, req *CheckAccessRequest) (*CheckAccessResponse, error) {
-	claims, err := s.authService.ValidateToken(req.Token)
+func (s *AuthServer) CheckAccess(ctx context.Context, req *authpb.CheckAccessRequest) (*authpb.CheckAccessResponse, error) {
+	claims, err := s.authService.ValidateToken(ctx, req.Token)
 	if err != nil {
-		return &CheckAccessResponse{
+		return &authpb.CheckAccessResponse{
 			Allowed: false,
 			UserId:  "",
 			Error:   err.Error(),
 		}, nil
 	}
 
+	userRole := string(claims.Role)
+
 	// Check if user has any of the required roles
 	if len(req.RequiredRoles) > 0 {
 		hasRequiredRole := false
-		userRole := string(claims.Role)
 
 		for _, requiredRole := range req.RequiredRoles {
 			if userRole == requiredRole {
@@ -39,17 +41,19 @@ func (s *AuthServer) CheckAccess(ctx context.Context, req *CheckAccessRequest) (
 		}
 
 		if !hasRequiredRole {
-			return &CheckAccessResponse{
+			return &authpb.CheckAccessResponse{
 				Allowed: false,
 				UserId:  claims.UserID.String(),
+				Role:    userRole,
 				Error:   "insufficient permissions",
 			}, nil
 		}
 	}
 
-	return &CheckAccessResponse{
+	return &authpb.CheckAccessResponse{
 		Allowed: true,
 		UserId:  claims.UserID.String(),
+		Role:    userRole,
 		Error:   "",
 	}, nil
 }
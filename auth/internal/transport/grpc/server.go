@@ -2,12 +2,13 @@ package grpc
 
 import (
 	"auth-service/internal/services"
+	"platform/auth/authpb"
 
 	"google.golang.org/grpc"
 )
 
-func NewServer(authService *services.AuthService) *grpc.Server {
-	grpcServer := grpc.NewServer()
-	RegisterAuthServiceServer(grpcServer, NewAuthServer(authService))
+func NewServer(authService *services.AuthService, opts ...grpc.ServerOption) *grpc.Server {
+	grpcServer := grpc.NewServer(opts...)
+	authpb.RegisterAuthServiceServer(grpcServer, NewAuthServer(authService))
 	return grpcServer
 }
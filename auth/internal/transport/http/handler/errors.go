@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+
+	"auth-service/internal/services"
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes returned by the auth service
+var (
+	errInvalidEmail = problem.Define("INVALID_EMAIL", http.StatusBadRequest, problem.Messages{
+		"ru": "Некорректный формат email",
+		"en": "Invalid email format",
+	})
+	errWeakPassword = problem.Define("WEAK_PASSWORD", http.StatusBadRequest, problem.Messages{
+		"ru": "Пароль должен содержать не менее 8 символов, цифры и буквы в разных регистрах",
+		"en": "Password must be at least 8 characters and contain numbers and letters in different cases",
+	})
+	errEmailExists = problem.Define("EMAIL_EXISTS", http.StatusConflict, problem.Messages{
+		"ru": "Пользователь с таким email уже существует",
+		"en": "Email already exists",
+	})
+	errInvalidCredentials = problem.Define("INVALID_CREDENTIALS", http.StatusUnauthorized, problem.Messages{
+		"ru": "Неверный email или пароль",
+		"en": "Invalid credentials",
+	})
+	errEmailNotConfirmed = problem.Define("EMAIL_NOT_CONFIRMED", http.StatusUnauthorized, problem.Messages{
+		"ru": "Email не подтвержден",
+		"en": "Email not confirmed",
+	})
+	errUserNotFound = problem.Define("USER_NOT_FOUND", http.StatusNotFound, problem.Messages{
+		"ru": "Пользователь не найден",
+		"en": "User not found",
+	})
+	errInvalidCode = problem.Define("INVALID_CODE", http.StatusBadRequest, problem.Messages{
+		"ru": "Неверный код подтверждения",
+		"en": "Invalid verification code",
+	})
+	errCodeExpired = problem.Define("CODE_EXPIRED", http.StatusBadRequest, problem.Messages{
+		"ru": "Срок действия кода подтверждения истек",
+		"en": "Verification code expired",
+	})
+	errInvalidRefreshToken = problem.Define("INVALID_REFRESH_TOKEN", http.StatusUnauthorized, problem.Messages{
+		"ru": "Недействительный refresh token",
+		"en": "Invalid refresh token",
+	})
+	errRefreshTokenExpired = problem.Define("REFRESH_TOKEN_EXPIRED", http.StatusUnauthorized, problem.Messages{
+		"ru": "Срок действия refresh token истек",
+		"en": "Refresh token expired",
+	})
+	errNotImplemented = problem.Define("NOT_IMPLEMENTED", http.StatusNotImplemented, problem.Messages{
+		"ru": "Функционал не реализован",
+		"en": "Not implemented",
+	})
+)
+
+// errorMapping maps service errors to response codes.
+// Errors missing from the table are returned as INTERNAL_ERROR and logged.
+var errorMapping = problem.Mapping{
+	problem.Map(errEmailExists, services.ErrEmailExists),
+	problem.Map(errInvalidCredentials, services.ErrInvalidPassword),
+	problem.Map(errEmailNotConfirmed, services.ErrEmailNotConfirmed),
+	problem.Map(errUserNotFound, services.ErrUserNotFound),
+	problem.Map(errInvalidCode, services.ErrInvalidCode),
+	problem.Map(errCodeExpired, services.ErrCodeExpired),
+	problem.Map(errInvalidRefreshToken, services.ErrInvalidRefreshToken),
+	problem.Map(errRefreshTokenExpired, services.ErrRefreshTokenExpired),
+	problem.Map(problem.TokenInvalid, services.ErrInvalidToken, services.ErrTokenExpired, services.ErrTokenInvalidatedByPasswordChange),
+}
+
+// abort responds with an application/problem+json error and stops the handler chain
+func abort(c *gin.Context, err error) {
+	errorMapping.Abort(c, err)
+}
@@ -1,7 +1,7 @@
 package handler
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 
 	"auth-service/internal/config"
@@ -9,6 +9,7 @@ import (
 	"auth-service/internal/services"
 	"auth-service/internal/transport/http/middleware"
 	"auth-service/internal/utils"
+	"platform/problem"
 
 	_ "auth-service/docs" // импорт сгенерированной документации
 
@@ -62,28 +63,23 @@ func (h *Handler) register(c *gin.Context) {
 	var input models.UserCreate
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abort(c, problem.Validation(err))
 		return
 	}
 
 	if !utils.IsValidEmail(input.Email) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid email format"})
+		abort(c, errInvalidEmail)
 		return
 	}
 
 	if !utils.IsValidPassword(input.Password) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "password must be at least 8 characters and contain numbers and letters in different cases"})
+		abort(c, errWeakPassword)
 		return
 	}
 
-	_, err := h.authService.Register(&input)
+	_, err := h.authService.Register(c.Request.Context(), &input)
 	if err != nil {
-		if err == services.ErrEmailExists {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		fmt.Println(err)
+		abort(c, err)
 		return
 	}
 
@@ -105,21 +101,20 @@ func (h *Handler) login(c *gin.Context) {
 	var input models.UserLogin
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abort(c, problem.Validation(err))
 		return
 	}
 
-	_, err := h.authService.Login(&input)
+	_, err := h.authService.Login(c.Request.Context(), &input)
 	if err != nil {
-		switch err {
-		case services.ErrVerificationSent:
+		switch {
+		case errors.Is(err, services.ErrVerificationSent):
 			c.JSON(http.StatusOK, gin.H{"message": "verification code sent to your email"})
-		case services.ErrUserNotFound, services.ErrInvalidPassword:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
-		case services.ErrEmailNotConfirmed:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "email not confirmed"})
+		case errors.Is(err, services.ErrUserNotFound):
+			// Same response as for a wrong password, so emails cannot be enumerated
+			abort(c, errInvalidCredentials)
 		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			abort(c, err)
 		}
 		return
 	}
@@ -139,20 +134,13 @@ func (h *Handler) verifyEmail(c *gin.Context) {
 	var input models.VerificationRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abort(c, problem.Validation(err))
 		return
 	}
 
-	tokens, err := h.authService.VerifyEmail(input.Email, input.Code)
+	tokens, err := h.authService.VerifyEmail(c.Request.Context(), input.Email, input.Code)
 	if err != nil {
-		switch err {
-		case services.ErrInvalidCode:
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid verification code"})
-		case services.ErrCodeExpired:
-			c.JSON(http.StatusBadRequest, gin.H{"error": "verification code expired"})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+		abort(c, err)
 		return
 	}
 
@@ -173,20 +161,13 @@ func (h *Handler) verifyLogin(c *gin.Context) {
 	var input models.VerificationRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abort(c, problem.Validation(err))
 		return
 	}
 
-	tokens, err := h.authService.VerifyLogin(input.Email, input.Code)
+	tokens, err := h.authService.VerifyLogin(c.Request.Context(), input.Email, input.Code)
 	if err != nil {
-		switch err {
-		case services.ErrInvalidCode:
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid verification code"})
-		case services.ErrCodeExpired:
-			c.JSON(http.StatusBadRequest, gin.H{"error": "verification code expired"})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+		abort(c, err)
 		return
 	}
 
@@ -203,7 +184,7 @@ func (h *Handler) verifyLogin(c *gin.Context) {
 // @Router /oauth/google [get]
 func (h *Handler) googleOAuth(c *gin.Context) {
 	// TODO: Implement Google OAuth
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "not implemented"})
+	abort(c, errNotImplemented)
 }
 
 // @Summary Обновление токена доступа
@@ -223,20 +204,13 @@ func (h *Handler) refresh(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "refresh token is required"})
+		abort(c, problem.Validation(err))
 		return
 	}
 
-	tokens, err := h.authService.RefreshTokens(input.RefreshToken)
+	tokens, err := h.authService.RefreshTokens(c.Request.Context(), input.RefreshToken)
 	if err != nil {
-		switch err {
-		case services.ErrInvalidRefreshToken:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
-		case services.ErrRefreshTokenExpired:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "refresh token expired"})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+		abort(c, err)
 		return
 	}
 
@@ -258,17 +232,12 @@ func (h *Handler) requestPasswordReset(c *gin.Context) {
 	var input models.PasswordResetRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abort(c, problem.Validation(err))
 		return
 	}
 
-	if err := h.authService.InitiatePasswordReset(input.Email); err != nil {
-		switch err {
-		case services.ErrUserNotFound:
-			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+	if err := h.authService.InitiatePasswordReset(c.Request.Context(), input.Email); err != nil {
+		abort(c, err)
 		return
 	}
 
@@ -289,24 +258,17 @@ func (h *Handler) confirmPasswordReset(c *gin.Context) {
 	var input models.PasswordResetConfirm
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abort(c, problem.Validation(err))
 		return
 	}
 
 	if !utils.IsValidPassword(input.NewPassword) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "password must be at least 8 characters and contain numbers and letters in different cases"})
+		abort(c, errWeakPassword)
 		return
 	}
 
-	if err := h.authService.ResetPassword(input.Email, input.Code, input.NewPassword); err != nil {
-		switch err {
-		case services.ErrInvalidCode:
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid verification code"})
-		case services.ErrCodeExpired:
-			c.JSON(http.StatusBadRequest, gin.H{"error": "verification code expired"})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
-		}
+	if err := h.authService.ResetPassword(c.Request.Context(), input.Email, input.Code, input.NewPassword); err != nil {
+		abort(c, err)
 		return
 	}
 
@@ -1,11 +1,11 @@
 package middleware
 
 import (
-	"net/http"
 	"sync"
 	"time"
 
 	"auth-service/internal/config"
+	"platform/problem"
 
 	"github.com/gin-gonic/gin"
 )
@@ -47,11 +47,7 @@ func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
 
 		// Проверяем количество запросов
 		if len(rl.tokens[clientIP]) >= rl.maxTokens {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":   "rate limit exceeded",
-				"details": "please try again later",
-			})
-			c.Abort()
+			problem.AbortWith(c, problem.New(c.Request, problem.RateLimited))
 			return
 		}
 
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
 	"course2/internal/app"
 	"log"
+	"log/slog"
+	"os"
+	"platform/lifecycle"
+	"platform/logging"
+	"platform/tracing"
 
 	"github.com/joho/godotenv"
 )
@@ -14,13 +20,29 @@ func init() {
 }
 
 func main() {
-	a, err := app.New()
+	logger := logging.Setup("edu")
+
+	// Настраиваем трассировку, экспортер задается переменной OTEL_TRACES_EXPORTER
+	shutdownTracing, err := tracing.Setup(context.Background(), "edu")
 	if err != nil {
-		log.Fatalf("Ошибка создания экземпляра приложения: %s\n", err.Error())
+		logger.Error("ошибка настройки трассировки", slog.Any("error", err))
+		os.Exit(1)
 	}
+	defer shutdownTracing(context.Background())
+
+	a, err := app.New(logger)
+	if err != nil {
+		logger.Error("ошибка создания экземпляра приложения", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	// Останавливаем приложение по SIGTERM или SIGINT
+	ctx, stop := lifecycle.SignalContext(context.Background())
+	defer stop()
 
-	err = a.Run()
+	err = a.Run(ctx)
 	if err != nil {
-		log.Fatalf("Ошибка запуска приложения: %s\n", err.Error())
+		logger.Error("ошибка запуска приложения", slog.Any("error", err))
+		os.Exit(1)
 	}
 }
@@ -15,6 +15,52 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/certificates/{serial}/revoke": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отозвать сертификат. Проверка по серийному номеру будет показывать причину и дату отзыва.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Отозвать сертификат",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Серийный номер",
+                        "name": "serial",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RevokeCertificateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Certificate"
+                        }
+                    }
+                }
+            }
+        },
         "/admin/courses": {
             "post": {
                 "security": [
@@ -22,7 +68,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Создать новый курс",
+                "description": "Создать курс от имени администратора и сразу отправить его на проверку",
                 "consumes": [
                     "application/json"
                 ],
@@ -61,7 +107,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получить список курсов, ожидающих модерации",
+                "description": "Получить курсы в статусе pending_review, сначала давно ожидающие",
                 "consumes": [
                     "application/json"
                 ],
@@ -71,17 +117,23 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "Список ожидающих модерации курсов",
+                "summary": "Очередь модерации",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Номер страницы",
-                        "name": "page",
+                        "type": "string",
+                        "description": "me - назначенные мне, unassigned - без проверяющего, либо ID проверяющего",
+                        "name": "reviewer",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Количество элементов на странице",
+                        "description": "Количество элементов на странице, не больше 100",
                         "name": "limit",
                         "in": "query"
                     }
@@ -90,10 +142,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Course"
-                            }
+                            "$ref": "#/definitions/pagination.Page-models_Course"
                         }
                     }
                 }
@@ -184,7 +233,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Одобрить курс для публикации",
+                "description": "Опубликовать курс из очереди модерации. Автор получает уведомление.",
                 "consumes": [
                     "application/json"
                 ],
@@ -202,6 +251,14 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Заметка проверяющего",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ApproveCourseRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -217,14 +274,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/courses/{id}/reject": {
+        "/admin/courses/{id}/archive": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Отклонить курс с указанием причины",
+                "description": "Перевести опубликованный или отклоненный курс в архив. Автор получает уведомление с причиной.",
                 "consumes": [
                     "application/json"
                 ],
@@ -234,7 +291,7 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "Отклонить курс",
+                "summary": "Снять курс с публикации",
                 "parameters": [
                     {
                         "type": "string",
@@ -244,15 +301,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Причина отклонения",
-                        "name": "reason",
+                        "description": "Причина",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handler.ArchiveCourseRequest"
                         }
                     }
                 ],
@@ -269,35 +323,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories": {
-            "get": {
-                "description": "Получить список всех категорий курсов",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "categories"
-                ],
-                "summary": "Список категорий",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Category"
-                            }
-                        }
+        "/admin/courses/{id}/assign": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     }
-                }
-            }
-        },
-        "/courses": {
-            "get": {
-                "description": "Получить список всех курсов",
+                ],
+                "description": "Назначить курс из очереди модерации проверяющему",
                 "consumes": [
                     "application/json"
                 ],
@@ -305,39 +338,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "courses"
+                    "admin"
                 ],
-                "summary": "Список курсов",
+                "summary": "Назначить проверяющего",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Номер страницы",
-                        "name": "page",
-                        "in": "query"
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Количество элементов на странице",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Проверяющий",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.AssignReviewerRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Course"
-                            }
+                            "$ref": "#/definitions/models.Course"
                         }
                     }
                 }
             }
         },
-        "/courses/category/{categoryId}": {
+        "/admin/courses/{id}/events": {
             "get": {
-                "description": "Получить список курсов в указанной категории",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить события модерации курса с причинами и заметками проверяющих",
                 "consumes": [
                     "application/json"
                 ],
@@ -345,26 +383,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "courses"
+                    "admin"
                 ],
-                "summary": "Список курсов по категории",
+                "summary": "История модерации курса",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID категории",
-                        "name": "categoryId",
+                        "description": "ID курса",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Номер страницы",
-                        "name": "page",
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Количество элементов на странице",
+                        "description": "Количество элементов на странице, не больше 100",
                         "name": "limit",
                         "in": "query"
                     }
@@ -373,18 +411,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Course"
-                            }
+                            "$ref": "#/definitions/pagination.Page-models_ModerationEvent"
                         }
                     }
                 }
             }
         },
-        "/courses/{id}": {
-            "get": {
-                "description": "Получить информацию о курсе по ID",
+        "/admin/courses/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отклонить курс с указанием причины. Автор получает уведомление с причиной.",
                 "consumes": [
                     "application/json"
                 ],
@@ -392,9 +432,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "courses"
+                    "admin"
                 ],
-                "summary": "Получить курс",
+                "summary": "Отклонить курс",
                 "parameters": [
                     {
                         "type": "string",
@@ -402,26 +442,38 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Причина отклонения",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RejectCourseRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Course"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/profile": {
+        "/admin/reviews/reported": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получить профиль текущего пользователя",
+                "description": "Получить видимые отзывы с жалобами, сначала с наибольшим числом жалоб",
                 "consumes": [
                     "application/json"
                 ],
@@ -429,25 +481,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "profile"
+                    "admin"
+                ],
+                "summary": "Отзывы с жалобами",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество элементов на странице, не больше 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Получить профиль",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserProfile"
+                            "$ref": "#/definitions/pagination.Page-models_Review"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/reviews/{id}/hide": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Обновить профиль текущего пользователя",
+                "description": "Скрыть отзыв из списка и рейтинга курса. Автор отзыва получает уведомление с причиной.",
                 "consumes": [
                     "application/json"
                 ],
@@ -455,17 +523,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "profile"
+                    "admin"
                 ],
-                "summary": "Обновить профиль",
+                "summary": "Скрыть отзыв",
                 "parameters": [
                     {
-                        "description": "Данные профиля",
-                        "name": "profile",
+                        "type": "string",
+                        "description": "ID отзыва",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UserProfile"
+                            "$ref": "#/definitions/handler.HideReviewRequest"
                         }
                     }
                 ],
@@ -473,20 +548,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserProfile"
+                            "$ref": "#/definitions/models.Review"
                         }
                     }
                 }
             }
         },
-        "/profile/courses": {
+        "/admin/reviews/{id}/reports": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получить список купленных курсов текущего пользователя",
+                "description": "Получить жалобы на отзыв, сначала новые",
                 "consumes": [
                     "application/json"
                 ],
@@ -494,30 +569,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "profile"
+                    "admin"
+                ],
+                "summary": "Жалобы на отзыв",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID отзыва",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество элементов на странице, не больше 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Получить купленные курсы",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Course"
-                            }
+                            "$ref": "#/definitions/pagination.Page-models_ReviewReport"
                         }
                     }
                 }
             }
         },
-        "/profile/xp": {
-            "get": {
+        "/admin/reviews/{id}/unhide": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получить общее количество XP текущего пользователя",
+                "description": "Вернуть скрытый отзыв в список и рейтинг курса. Жалобы на отзыв снимаются.",
                 "consumes": [
                     "application/json"
                 ],
@@ -525,30 +618,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "profile"
+                    "admin"
+                ],
+                "summary": "Вернуть отзыв",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID отзыва",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Получить общий XP",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/models.Review"
                         }
                     }
                 }
             }
         },
-        "/progress/courses/{courseId}": {
+        "/author/courses": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получить прогресс пользователя по конкретному курсу",
+                "description": "Получить курсы автора во всех статусах",
                 "consumes": [
                     "application/json"
                 ],
@@ -556,36 +655,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "progress"
+                    "author"
                 ],
-                "summary": "Получить прогресс по курсу",
+                "summary": "Мои курсы",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID курса",
-                        "name": "courseId",
-                        "in": "path",
-                        "required": true
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество элементов на странице, не больше 100",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CourseProgress"
+                            "$ref": "#/definitions/pagination.Page-models_Course"
                         }
                     }
                 }
-            }
-        },
-        "/progress/lessons/{lessonId}/test": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Отправить ответы на тест и получить результат",
+                "description": "Создать курс в статусе draft. Автором курса становится текущий пользователь.",
                 "consumes": [
                     "application/json"
                 ],
@@ -593,49 +695,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "progress"
+                    "author"
                 ],
-                "summary": "Отправить ответы на тест",
+                "summary": "Создать черновик курса",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID урока",
-                        "name": "lessonId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Ответы на вопросы",
-                        "name": "answers",
+                        "description": "Данные курса",
+                        "name": "course",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/handler.CourseRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Course"
                         }
                     }
                 }
             }
         },
-        "/progress/lessons/{lessonId}/view": {
-            "post": {
+        "/author/courses/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Отметить урок как просмотренный и начислить XP",
+                "description": "Получить курс автора в любом статусе",
                 "consumes": [
                     "application/json"
                 ],
@@ -643,14 +734,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "progress"
+                    "author"
                 ],
-                "summary": "Отметить урок как просмотренный",
+                "summary": "Получить свой курс",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID урока",
-                        "name": "lessonId",
+                        "description": "ID курса",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -659,21 +750,18 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Course"
                         }
                     }
                 }
-            }
-        },
-        "/student/courses/purchase": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Покупка курса пользователем",
+                "description": "Изменить черновик или отклоненный курс. Администратор может изменить курс в любом статусе.",
                 "consumes": [
                     "application/json"
                 ],
@@ -681,17 +769,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "courses"
+                    "author"
                 ],
-                "summary": "Купить курс",
+                "summary": "Изменить курс",
                 "parameters": [
                     {
-                        "description": "Данные для покупки курса",
-                        "name": "request",
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные курса",
+                        "name": "course",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.PurchaseCourseRequest"
+                            "$ref": "#/definitions/handler.CourseRequest"
                         }
                     }
                 ],
@@ -699,16 +794,52 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Course"
                         }
                     }
                 }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удалить черновик или отклоненный курс",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Удалить курс",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
             }
         },
-        "/student/courses/{courseId}/lessons": {
+        "/author/courses/{id}/events": {
             "get": {
-                "description": "Получить список уроков для конкретного курса",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить события модерации своего курса: отправки на проверку, решения и причины отклонения",
                 "consumes": [
                     "application/json"
                 ],
@@ -716,39 +847,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "courses"
+                    "author"
                 ],
-                "summary": "Получить уроки курса",
+                "summary": "История модерации",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "ID курса",
-                        "name": "courseId",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество элементов на странице, не больше 100",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Lesson"
-                            }
+                            "$ref": "#/definitions/pagination.Page-models_ModerationEvent"
                         }
                     }
                 }
             }
         },
-        "/student/courses/{courseId}/structure": {
+        "/author/courses/{id}/lessons": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получить полную структуру курса с уроками и прогрессом для купленного курса",
+                "description": "Получить уроки своего курса",
                 "consumes": [
                     "application/json"
                 ],
@@ -756,14 +896,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "courses"
+                    "author"
                 ],
-                "summary": "Получить структуру курса",
+                "summary": "Уроки курса",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "ID курса",
-                        "name": "courseId",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -772,15 +912,67 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.CourseStructure"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Lesson"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Добавить урок в курс",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Добавить урок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные урока",
+                        "name": "lesson",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.LessonRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Lesson"
                         }
                     }
                 }
             }
         },
-        "/student/lessons/{lessonId}": {
-            "get": {
-                "description": "Получить содержимое конкретного урока",
+        "/author/courses/{id}/lessons/{lessonId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Изменить урок курса",
                 "consumes": [
                     "application/json"
                 ],
@@ -788,16 +980,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "lessons"
+                    "author"
                 ],
-                "summary": "Получить урок",
+                "summary": "Изменить урок",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "ID урока",
                         "name": "lessonId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Данные урока",
+                        "name": "lesson",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.LessonRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -808,16 +1016,55 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удалить урок курса вместе с его тестом",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Удалить урок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID урока",
+                        "name": "lessonId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
             }
         },
-        "/student/lessons/{lessonId}/test": {
-            "get": {
+        "/author/courses/{id}/lessons/{lessonId}/prerequisites": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получить тест, привязанный к уроку (если есть)",
+                "description": "Задать уроки, которые нужно пройти до этого урока. Список заменяет прежний, пустой список снимает требования.\nТребования действуют, если у курса порядок прохождения prerequisites.",
                 "consumes": [
                     "application/json"
                 ],
@@ -825,102 +1072,3099 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "lessons"
+                    "author"
                 ],
-                "summary": "Получить тест урока",
+                "summary": "Предварительные уроки",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "ID урока",
                         "name": "lessonId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "ID предварительных уроков",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PrerequisitesRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TestResponse"
+                            "$ref": "#/definitions/handler.PrerequisitesRequest"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.PurchaseCourseRequest": {
-            "type": "object",
-            "required": [
-                "course_id"
-            ],
-            "properties": {
-                "course_id": {
-                    "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
-                }
-            }
         },
-        "models.Category": {
-            "type": "object",
-            "properties": {
-                "created_at": {
+        "/author/courses/{id}/lessons/{lessonId}/test": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить тест урока вместе с правильными ответами",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Тест урока",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID урока",
+                        "name": "lessonId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TestContent"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Изменить проходной балл теста урока",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Изменить тест",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID урока",
+                        "name": "lessonId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные теста",
+                        "name": "test",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.TestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Test"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Добавить тест к уроку. У урока может быть только один тест.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Добавить тест",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID урока",
+                        "name": "lessonId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные теста",
+                        "name": "test",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.TestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Test"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удалить тест урока вместе с вопросами",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Удалить тест",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID урока",
+                        "name": "lessonId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/author/courses/{id}/lessons/{lessonId}/test/analysis": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Показатели вопросов по проверенным попыткам всех студентов: индекс трудности (средняя доля баллов),\nиндекс различения (разница между 27% лучших и 27% худших попыток) и частота выбора вариантов\nдля вопросов с выбором ответа. Попытки, отклоненные по времени, не учитываются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Анализ вопросов теста",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID урока",
+                        "name": "lessonId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ItemAnalysis"
+                        }
+                    }
+                }
+            }
+        },
+        "/author/courses/{id}/lessons/{lessonId}/test/questions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Добавить вопрос в тест урока",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Добавить вопрос",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID урока",
+                        "name": "lessonId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные вопроса",
+                        "name": "question",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.QuestionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Question"
+                        }
+                    }
+                }
+            }
+        },
+        "/author/courses/{id}/lessons/{lessonId}/test/questions/{questionId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Изменить вопрос теста урока",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Изменить вопрос",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID урока",
+                        "name": "lessonId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID вопроса",
+                        "name": "questionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные вопроса",
+                        "name": "question",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.QuestionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Question"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удалить вопрос из теста урока",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Удалить вопрос",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID урока",
+                        "name": "lessonId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID вопроса",
+                        "name": "questionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/author/courses/{id}/submit": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Перевести черновик или отклоненный курс в статус pending_review. В курсе должен быть хотя бы один урок.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Отправить курс на проверку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Course"
+                        }
+                    }
+                }
+            }
+        },
+        "/author/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить уведомления о решениях модерации, сначала новые",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Уведомления",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Только непрочитанные",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество элементов на странице, не больше 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-models_Notification"
+                        }
+                    }
+                }
+            }
+        },
+        "/author/notifications/{id}/read": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отметить уведомление прочитанным",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author"
+                ],
+                "summary": "Прочитать уведомление",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID уведомления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "description": "Получить категории курсов по алфавиту",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Список категорий",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество элементов на странице, не больше 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-models_Category"
+                        }
+                    }
+                }
+            }
+        },
+        "/certificates/{serial}": {
+            "get": {
+                "description": "Проверить подлинность сертификата по серийному номеру. Отозванный сертификат возвращается с valid=false.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "certificates"
+                ],
+                "summary": "Проверить сертификат",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "EDU-7K3M-QX9P-2D4F",
+                        "description": "Серийный номер",
+                        "name": "serial",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CertificateVerification"
+                        }
+                    }
+                }
+            }
+        },
+        "/certificates/{serial}/pdf": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Скачать PDF сертификата. Доступно владельцу сертификата и администратору.",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "certificates"
+                ],
+                "summary": "Скачать сертификат",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Серийный номер",
+                        "name": "serial",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/courses": {
+            "get": {
+                "description": "Поиск по опубликованным курсам с фильтрами, сортировкой и фасетами для боковой панели.\nФасеты возвращаются только на первой странице (без cursor). Курсор действителен только для той же сортировки.\nПараметры category_id и level можно повторять. Без sort курсы с запросом q сортируются по релевантности, без запроса - по популярности.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "courses"
+                ],
+                "summary": "Каталог курсов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поиск по названию и описанию",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Уровень",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Минимальная цена",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Максимальная цена",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только бесплатные",
+                        "name": "free",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Минимальный рейтинг",
+                        "name": "min_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальная длительность",
+                        "name": "min_duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальная длительность",
+                        "name": "max_duration",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "relevance",
+                            "popular",
+                            "rating",
+                            "price_asc",
+                            "price_desc",
+                            "newest"
+                        ],
+                        "type": "string",
+                        "description": "Сортировка",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество элементов на странице, не больше 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CatalogPage"
+                        }
+                    }
+                }
+            }
+        },
+        "/courses/category/{categoryId}": {
+            "get": {
+                "description": "Получить опубликованные курсы в указанной категории, сначала новые",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "courses"
+                ],
+                "summary": "Список курсов по категории",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID категории",
+                        "name": "categoryId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество элементов на странице, не больше 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-models_Course"
+                        }
+                    }
+                }
+            }
+        },
+        "/courses/{id}": {
+            "get": {
+                "description": "Получить информацию о курсе по ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "courses"
+                ],
+                "summary": "Получить курс",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Course"
+                        }
+                    }
+                }
+            }
+        },
+        "/courses/{id}/certificate": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить сертификат о прохождении курса. Если курс завершен, а сертификат еще не выдан, он выдается при запросе.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "certificates"
+                ],
+                "summary": "Сертификат курса",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Certificate"
+                        }
+                    }
+                }
+            }
+        },
+        "/courses/{id}/rating": {
+            "get": {
+                "description": "Получить средний рейтинг курса и число отзывов с каждой оценкой от 1 до 5",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Рейтинг курса",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RatingSummary"
+                        }
+                    }
+                }
+            }
+        },
+        "/courses/{id}/reviews": {
+            "get": {
+                "description": "Получить отзывы об опубликованном курсе. Скрытые администратором отзывы не показываются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Отзывы о курсе",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "newest",
+                            "helpful"
+                        ],
+                        "type": "string",
+                        "description": "Сортировка",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество элементов на странице, не больше 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-models_Review"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Оставить отзыв о купленном курсе. На один курс можно оставить один отзыв.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Оставить отзыв",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Оценка и текст",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Review"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/fake/sessions/{sessionId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Есть только при PAYMENT_FAKE_CHECKOUT=true. Показывает заказ сессии текущего студента\nи адреса, которые завершают оплату.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Страница оплаты (для разработки)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID платежной сессии",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.FakeCheckoutResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/fake/sessions/{sessionId}/{outcome}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Есть только при PAYMENT_FAKE_CHECKOUT=true. Создает подписанное уведомление с результатом outcome\nдля заказа текущего студента и обрабатывает его так же, как уведомление от провайдера.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Завершить оплату (для разработки)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID платежной сессии",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "paid",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Результат оплаты",
+                        "name": "outcome",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Order"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/webhook/{provider}": {
+            "post": {
+                "description": "Принимает подписанное уведомление провайдера о платеже. Подпись проверяется (INVALID_SIGNATURE),\nоплата выдает курс, возврат отзывает его. Повторная доставка того же события ничего не меняет.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Уведомление платежного провайдера",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "fake",
+                        "description": "Имя провайдера",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить профиль текущего пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Получить профиль",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserProfile"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновить профиль текущего пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Обновить профиль",
+                "parameters": [
+                    {
+                        "description": "Данные профиля",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserProfile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserProfile"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/certificates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить сертификаты текущего пользователя, сначала новые",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Мои сертификаты",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество элементов на странице, не больше 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-models_Certificate"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/courses": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить купленные курсы текущего пользователя, сначала новые",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Получить купленные курсы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество элементов на странице, не больше 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-models_PurchasedCourse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить уведомления студента, например о завершении курса, сначала новые",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Уведомления",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Только непрочитанные",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество элементов на странице, не больше 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-models_Notification"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/notifications/{id}/read": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отметить уведомление прочитанным",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Прочитать уведомление",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID уведомления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/profile/xp": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить общее количество XP текущего пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profile"
+                ],
+                "summary": "Получить общий XP",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/courses/{courseId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить прогресс пользователя по конкретному курсу",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "progress"
+                ],
+                "summary": "Получить прогресс по курсу",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "courseId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CourseProgress"
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/lessons/{lessonId}/test": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отправить ответы на тест и получить результат. Тест можно сдать после покупки курса и просмотра урока\n(LESSON_NOT_VIEWED), если урок не заблокирован (LESSON_LOCKED). Если урок был последним\nнепройденным уроком курса, в ответе есть course_completion с бонусом за завершение курса.\nОтветы проверяются по вопросам попытки, начатой при открытии теста; номера вариантов - в порядке,\nпоказанном в попытке. Без незавершенной попытки или при несовпадении attempt_id - ATTEMPT_NOT_OPEN.\nФормат ответов версии 2: {\"version\": 2, \"attempt_id\": \"...\", \"answers\": {\"\u003cid вопроса\u003e\": {...}}}, поля ответа зависят от типа вопроса.\nТело без version считается версией 1: {\"\u003cid вопроса\u003e\": \u003cномер варианта\u003e}.\nВ ответе results - баллы и пояснение по каждому вопросу, score - результат этой попытки,\ntest_score - результат урока по правилу подсчета теста (scoring_policy). Ответы после истечения\nвремени отклоняются с ATTEMPT_EXPIRED или проверяются с late=true - по настройке late_submission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "progress"
+                ],
+                "summary": "Отправить ответы на тест",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID урока",
+                        "name": "lessonId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ответы на вопросы",
+                        "name": "answers",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/grading.Submission"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/lessons/{lessonId}/test/attempts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Завершенные попытки студента, начиная с последней: результат, баллы, время прохождения и отметка\nоб опоздании. Попытка, отклоненная по времени, имеет результат 0.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "progress"
+                ],
+                "summary": "История попыток теста",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID урока",
+                        "name": "lessonId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество элементов на странице, не больше 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pagination.Page-models_AttemptSummary"
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/lessons/{lessonId}/test/attempts/{attemptId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Вопросы завершенной попытки в порядке попытки с ответами студента, баллами и пояснениями.\nПравильные ответы (question.key) есть, если их разрешает review_policy теста: after_submit - всегда,\nafter_pass - после прохождения теста. Номера вариантов - в порядке, показанном в попытке.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "progress"
+                ],
+                "summary": "Разбор попытки теста",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID урока",
+                        "name": "lessonId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID попытки",
+                        "name": "attemptId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AttemptReview"
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/lessons/{lessonId}/view": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отметить урок как просмотренный и начислить XP. Заблокированный урок отметить нельзя (LESSON_LOCKED).\nЕсли урок без теста был последним непройденным уроком курса, в ответе есть course_completion\nс бонусом за завершение курса.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "progress"
+                ],
+                "summary": "Отметить урок как просмотренный",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID урока",
+                        "name": "lessonId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Изменить оценку и текст своего отзыва",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Изменить отзыв",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID отзыва",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Оценка и текст",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Review"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удалить свой отзыв",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Удалить отзыв",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID отзыва",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/reviews/{id}/helpful": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отметить чужой отзыв полезным. Повторная отметка ничего не меняет.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Отметить отзыв полезным",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID отзыва",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Снять свою отметку «полезный отзыв»",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Снять отметку «полезный»",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID отзыва",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/reviews/{id}/report": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Пожаловаться на чужой отзыв. Отзывы с жалобами проверяют администраторы.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Пожаловаться на отзыв",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID отзыва",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина жалобы",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ReportReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/student/courses/purchase": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Оформить заказ курса. Для платного курса создается платежная сессия: студент оплачивает курс\nна странице checkout_url, а курс выдается, когда провайдер подтвердит оплату уведомлением.\nСтатус заказа - GET /student/orders/{orderId}. Повторный запрос возвращает заказ, ожидающий оплаты.\nБесплатный курс выдается сразу, заказ получает статус paid.\nЗаказать можно только опубликованный курс, иначе COURSE_NOT_FOUND.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "courses"
+                ],
+                "summary": "Купить курс",
+                "parameters": [
+                    {
+                        "description": "Данные для покупки курса",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PurchaseCourseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Order"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/courses/{courseId}/lessons": {
+            "get": {
+                "description": "Получить список уроков для конкретного курса. Если у курса задан порядок прохождения,\nу заблокированных уроков locked=true, причина и уроки, которые нужно пройти, а содержимое не отдается.\nКупившему курс уроки доступны и после снятия курса с публикации.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "courses"
+                ],
+                "summary": "Получить уроки курса",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "courseId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Lesson"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/student/courses/{courseId}/structure": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить полную структуру курса с уроками и прогрессом для купленного курса.\nДля каждого урока указано, заблокирован ли он (locked), почему (lock_reason) и какие уроки нужно пройти (blocked_by).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "courses"
+                ],
+                "summary": "Получить структуру курса",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID курса",
+                        "name": "courseId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CourseStructure"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/lessons/{lessonId}": {
+            "get": {
+                "description": "Получить содержимое конкретного урока. Авторизованному пользователю урок доступен после покупки курса,\nесли урок не заблокирован порядком прохождения (LESSON_LOCKED). Гостю доступны уроки без требований.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lessons"
+                ],
+                "summary": "Получить урок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID урока",
+                        "name": "lessonId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Lesson"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/lessons/{lessonId}/test": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получить тест, привязанный к уроку (если есть). Тест заблокированного урока недоступен (LESSON_LOCKED).\nТест открывается после просмотра урока, иначе LESSON_NOT_VIEWED.\nОткрытие теста начинает попытку: вопросы выбираются из пула и перемешиваются по настройкам теста.\nПока попытка не отправлена, повторные запросы возвращают те же вопросы в том же порядке.\nЕсли попытки закончились или действует пауза между попытками, вопросов и attempt_id нет,\nа attempts показывает оставшиеся попытки и next_attempt_at. Незавершенная попытка с истекшим\nвременем засчитывается с результатом 0.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lessons"
+                ],
+                "summary": "Получить тест урока",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID урока",
+                        "name": "lessonId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TestResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/student/orders/{orderId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заказ текущего студента: created, pending (ждет оплаты на checkout_url), paid, failed или refunded",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "courses"
+                ],
+                "summary": "Статус заказа",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID заказа",
+                        "name": "orderId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Order"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "grading.Submission": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.Answer"
+                    }
+                },
+                "attempt_id": {
+                    "description": "AttemptID - попытка, на вопросы которой даны ответы. Если не указана, ответы относятся\nк текущей незавершенной попытке.",
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "handler.ApproveCourseRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "description": "Note - внутренняя заметка проверяющего, автор ее не видит",
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ArchiveCourseRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Материал устарел"
+                }
+            }
+        },
+        "handler.AssignReviewerRequest": {
+            "type": "object",
+            "properties": {
+                "reviewer_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "handler.CourseRequest": {
+            "type": "object",
+            "required": [
+                "category_id",
+                "title"
+            ],
+            "properties": {
+                "category_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Курс для начинающих"
+                },
+                "duration": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 120
+                },
+                "level": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "beginner"
+                },
+                "price": {
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 990
+                },
+                "progression": {
+                    "description": "Progression - порядок прохождения уроков. Если не указан, при создании - free, при изменении - прежний.",
+                    "type": "string",
+                    "enum": [
+                        "free",
+                        "sequential",
+                        "prerequisites"
+                    ],
+                    "example": "sequential"
+                },
+                "thumbnail": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Основы Go"
+                }
+            }
+        },
+        "handler.FakeCheckoutResponse": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "$ref": "#/definitions/models.Order"
+                },
+                "outcomes": {
+                    "description": "Outcomes - адреса, POST на которые завершает оплату с этим результатом",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.HideReviewRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Нарушение правил сообщества"
+                }
+            }
+        },
+        "handler.LessonRequest": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "order_num": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "requires_test": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Переменные и типы"
+                }
+            }
+        },
+        "handler.PrerequisitesRequest": {
+            "type": "object",
+            "properties": {
+                "lesson_ids": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.PurchaseCourseRequest": {
+            "type": "object",
+            "required": [
+                "course_id"
+            ],
+            "properties": {
+                "course_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "handler.QuestionRequest": {
+            "type": "object",
+            "required": [
+                "matches",
+                "options",
+                "question_text",
+                "tags"
+            ],
+            "properties": {
+                "correct_answer": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                },
+                "explanation": {
+                    "type": "string",
+                    "maxLength": 5000,
+                    "example": "Литералы с точкой имеют тип float64"
+                },
+                "key": {
+                    "$ref": "#/definitions/models.AnswerKey"
+                },
+                "matches": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "points": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 1
+                },
+                "question_text": {
+                    "type": "string",
+                    "example": "Какой тип у литерала 1.5?"
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "single_choice",
+                        "multiple_choice",
+                        "true_false",
+                        "numeric",
+                        "short_text",
+                        "ordering",
+                        "matching"
+                    ],
+                    "example": "single_choice"
+                }
+            }
+        },
+        "handler.RejectCourseRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "note": {
+                    "description": "Note - внутренняя заметка проверяющего, автор ее не видит",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason - причина отклонения, ее получает автор",
+                    "type": "string",
+                    "example": "Добавьте тесты к урокам"
+                }
+            }
+        },
+        "handler.ReportReviewRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Оскорбления"
+                }
+            }
+        },
+        "handler.ReviewRequest": {
+            "type": "object",
+            "required": [
+                "rating"
+            ],
+            "properties": {
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1,
+                    "example": 5
+                },
+                "text": {
+                    "type": "string",
+                    "maxLength": 5000,
+                    "example": "Понятные объяснения и хорошие задачи"
+                }
+            }
+        },
+        "handler.RevokeCertificateRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Нарушение правил прохождения тестов"
+                }
+            }
+        },
+        "handler.TestRequest": {
+            "type": "object",
+            "properties": {
+                "cooldown_seconds": {
+                    "type": "integer",
+                    "maximum": 604800,
+                    "minimum": 0,
+                    "example": 600
+                },
+                "late_submission": {
+                    "type": "string",
+                    "enum": [
+                        "reject",
+                        "grade"
+                    ],
+                    "example": "reject"
+                },
+                "max_attempts": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 3
+                },
+                "passing_score": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 70
+                },
+                "question_count": {
+                    "type": "integer",
+                    "maximum": 200,
+                    "minimum": 0,
+                    "example": 10
+                },
+                "review_policy": {
+                    "type": "string",
+                    "enum": [
+                        "never",
+                        "after_submit",
+                        "after_pass"
+                    ],
+                    "example": "after_pass"
+                },
+                "scoring_policy": {
+                    "type": "string",
+                    "enum": [
+                        "best",
+                        "last",
+                        "average"
+                    ],
+                    "example": "best"
+                },
+                "shuffle_options": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "shuffle_questions": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "tag_quotas": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/models.TagQuota"
+                    }
+                },
+                "time_limit_seconds": {
+                    "type": "integer",
+                    "maximum": 86400,
+                    "minimum": 0,
+                    "example": 1800
+                }
+            }
+        },
+        "models.Answer": {
+            "type": "object",
+            "properties": {
+                "bool": {
+                    "type": "boolean"
+                },
+                "choices": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "number": {
+                    "type": "number"
+                },
+                "order": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "pairs": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AnswerKey": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "description": "Accepted - принимаемые варианты ответа (short_text)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "bool": {
+                    "description": "Bool - правильный ответ true_false",
+                    "type": "boolean"
+                },
+                "case_sensitive": {
+                    "type": "boolean"
+                },
+                "choices": {
+                    "description": "Choices - номера правильных вариантов, начиная с 0 (single_choice, multiple_choice)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "number": {
+                    "description": "Number и Tolerance - правильное число и допустимое отклонение (numeric)",
+                    "type": "number"
+                },
+                "order": {
+                    "description": "Order - номера Options в правильном порядке (ordering)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "pairs": {
+                    "description": "Pairs - Pairs[i] - номер варианта из Matches для Options[i] (matching)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "tolerance": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.AttemptReview": {
+            "type": "object",
+            "properties": {
+                "answers_revealed": {
+                    "type": "boolean"
+                },
+                "attempt": {
+                    "$ref": "#/definitions/models.AttemptSummary"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewQuestion"
+                    }
+                }
+            }
+        },
+        "models.AttemptStatus": {
+            "type": "object",
+            "properties": {
+                "attempts_left": {
+                    "description": "AttemptsLeft - сколько еще попыток можно начать, nil - без ограничения",
+                    "type": "integer"
+                },
+                "attempts_used": {
+                    "description": "AttemptsUsed - начатые попытки, включая незавершенную",
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "description": "NextAttemptAt - когда можно начать следующую попытку, если сейчас действует пауза",
+                    "type": "string"
+                },
+                "score": {
+                    "description": "Score - результат по правилу подсчета теста, nil - нет завершенных попыток",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AttemptSummary": {
+            "type": "object",
+            "properties": {
+                "duration_seconds": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "description": "ExpiresAt - когда истекает время на попытку, nil - без ограничения",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "late": {
+                    "description": "Late - попытка завершена после истечения времени",
+                    "type": "boolean"
+                },
+                "max_points": {
+                    "type": "integer"
+                },
+                "points": {
+                    "type": "number"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "submitted_at": {
+                    "type": "string"
+                },
+                "test_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CatalogFacets": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FacetCount"
+                    }
+                },
+                "durations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RangeCount"
+                    }
+                },
+                "levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FacetCount"
+                    }
+                },
+                "price": {
+                    "$ref": "#/definitions/models.PriceFacet"
+                },
+                "ratings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RangeCount"
+                    }
+                }
+            }
+        },
+        "models.CatalogPage": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "$ref": "#/definitions/models.CatalogFacets"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Course"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor - курсор следующей страницы, отсутствует на последней странице",
+                    "type": "string"
+                },
+                "total_estimate": {
+                    "description": "TotalEstimate - число элементов во всем списке, см. EstimateTotal",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Certificate": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "course_id": {
+                    "type": "string"
+                },
+                "course_title": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "revoke_reason": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "serial": {
+                    "type": "string"
+                },
+                "student_name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CertificateVerification": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "course_title": {
+                    "type": "string"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "revoke_reason": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "serial": {
+                    "type": "string"
+                },
+                "student_name": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid - сертификат выдан платформой и не отозван",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Course": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "progression": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "reviewer_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "students_count": {
+                    "type": "integer"
+                },
+                "thumbnail": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CourseProgress": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "completed_lessons": {
+                    "type": "integer"
+                },
+                "course_id": {
                     "type": "string"
                 },
-                "description": {
+                "percentage": {
+                    "type": "number"
+                },
+                "total_lessons": {
+                    "type": "integer"
+                },
+                "xp_earned": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CourseStructure": {
+            "type": "object",
+            "properties": {
+                "completed_lessons": {
+                    "type": "integer"
+                },
+                "course": {
+                    "description": "Основная информация о курсе",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Course"
+                        }
+                    ]
+                },
+                "lessons": {
+                    "description": "Список уроков курса",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Lesson"
+                    }
+                },
+                "progress": {
+                    "type": "number"
+                },
+                "total_lessons": {
+                    "description": "Статистика прогресса",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FacetCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ItemAnalysis": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Attempts - число проверенных попыток, по которым считался анализ: последние из TotalAttempts,\nно не больше 2000",
+                    "type": "integer"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuestionAnalysis"
+                    }
+                },
+                "test_id": {
+                    "type": "string"
+                },
+                "total_attempts": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Lesson": {
+            "type": "object",
+            "properties": {
+                "blocked_by": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "completed": {
+                    "description": "Поля для отображения прогресса",
+                    "type": "boolean"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "course_id": {
+                    "type": "string"
+                },
+                "created_at": {
                     "type": "string"
                 },
+                "has_test": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "lock_reason": {
+                    "type": "string"
+                },
+                "locked": {
+                    "description": "Поля для отображения доступа: заблокированный урок нельзя открыть, пока не пройдены уроки из BlockedBy",
+                    "type": "boolean"
+                },
+                "order_num": {
+                    "type": "integer"
+                },
+                "passed_test": {
+                    "type": "boolean"
+                },
+                "prerequisites": {
+                    "description": "Prerequisites - уроки, которые нужно пройти до этого урока",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "requires_test": {
+                    "type": "boolean"
+                },
+                "test_score": {
+                    "type": "integer"
+                },
+                "title": {
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "viewed_at": {
+                    "type": "string"
                 }
             }
         },
-        "models.Course": {
+        "models.ModerationEvent": {
             "type": "object",
             "properties": {
-                "category_id": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "course_id": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "created_by": {
+                "from_status": {
                     "type": "string"
                 },
-                "description": {
+                "id": {
                     "type": "string"
                 },
-                "duration": {
+                "note": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Notification": {
+            "type": "object",
+            "properties": {
+                "course_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "read_at": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OptionAnalysis": {
+            "type": "object",
+            "properties": {
+                "chosen": {
+                    "type": "integer"
+                },
+                "correct": {
+                    "type": "boolean"
+                },
+                "index": {
                     "type": "integer"
                 },
+                "share": {
+                    "description": "Share - доля ответивших на вопрос, выбравших вариант",
+                    "type": "number",
+                    "example": 0.15
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Order": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 149000
+                },
+                "checkout_url": {
+                    "description": "CheckoutURL - страница оплаты последней платежной сессии, пока заказ ждет оплаты",
+                    "type": "string"
+                },
+                "course_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
                 "id": {
                     "type": "string"
                 },
-                "level": {
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "updated_at": {
                     "type": "string"
                 },
-                "price": {
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PriceFacet": {
+            "type": "object",
+            "properties": {
+                "free": {
+                    "type": "integer"
+                },
+                "max": {
                     "type": "number"
                 },
-                "rating": {
+                "min": {
                     "type": "number"
                 },
-                "status": {
+                "paid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PurchasedCourse": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "description": "CompletedAt - время прохождения всех уроков курса",
                     "type": "string"
                 },
-                "students_count": {
+                "course_id": {
+                    "type": "string"
+                },
+                "purchased_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Question": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "explanation": {
+                    "description": "Explanation - пояснение, которое показывается после отправки ответов",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Key - правильный ответ, студентам не отдается",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AnswerKey"
+                        }
+                    ]
+                },
+                "matches": {
+                    "description": "Matches - правая колонка сопоставления",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "options": {
+                    "description": "Options - варианты ответа, элементы для упорядочивания или левая колонка сопоставления",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "points": {
+                    "description": "Points - баллы за полностью верный ответ",
                     "type": "integer"
                 },
-                "thumbnail": {
+                "question_text": {
                     "type": "string"
                 },
-                "title": {
+                "tags": {
+                    "description": "Tags - темы вопроса для выборки из пула",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "test_id": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -928,159 +4172,265 @@ const docTemplate = `{
                 }
             }
         },
-        "models.CourseProgress": {
+        "models.QuestionAnalysis": {
             "type": "object",
             "properties": {
-                "completed_at": {
-                    "type": "string"
+                "difficulty": {
+                    "description": "Difficulty - индекс трудности: средняя доля баллов за вопрос, от 0 (никто не ответил верно)\nдо 1 (все ответили верно)",
+                    "type": "number",
+                    "example": 0.72
                 },
-                "completed_lessons": {
-                    "type": "integer"
+                "discrimination": {
+                    "description": "Discrimination - индекс различения: разница средней доли баллов в 27% лучших и 27% худших\nпопыток, от -1 до 1. Отрицательное значение - вопрос чаще решают слабые студенты.",
+                    "type": "number",
+                    "example": 0.41
                 },
-                "course_id": {
-                    "type": "string"
+                "options": {
+                    "description": "Options - как часто выбирался каждый вариант, для вопросов с выбором ответа",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OptionAnalysis"
+                    }
                 },
-                "percentage": {
-                    "type": "number"
+                "question": {
+                    "$ref": "#/definitions/models.Question"
                 },
-                "total_lessons": {
+                "responses": {
+                    "description": "Responses - попытки, в которые попал вопрос; Unanswered - из них без ответа",
                     "type": "integer"
                 },
-                "xp_earned": {
+                "unanswered": {
                     "type": "integer"
                 }
             }
         },
-        "models.CourseStructure": {
+        "models.RangeCount": {
             "type": "object",
             "properties": {
-                "completed_lessons": {
+                "count": {
                     "type": "integer"
                 },
-                "course": {
-                    "description": "Основная информация о курсе",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Course"
-                        }
-                    ]
+                "max": {
+                    "type": "number"
                 },
-                "lessons": {
-                    "description": "Список уроков курса",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Lesson"
+                "min": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.RatingSummary": {
+            "type": "object",
+            "properties": {
+                "course_id": {
+                    "type": "string"
+                },
+                "histogram": {
+                    "description": "Histogram - число отзывов с оценкой от 1 до 5",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
                     }
                 },
-                "progress": {
+                "rating": {
                     "type": "number"
                 },
-                "total_lessons": {
-                    "description": "Статистика прогресса",
+                "reviews_count": {
                     "type": "integer"
                 }
             }
         },
-        "models.Lesson": {
+        "models.Review": {
             "type": "object",
             "properties": {
-                "completed": {
-                    "description": "Поля для отображения прогресса",
-                    "type": "boolean"
-                },
-                "content": {
-                    "type": "string"
-                },
                 "course_id": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "has_test": {
-                    "type": "boolean"
+                "helpful_count": {
+                    "type": "integer"
+                },
+                "hidden_at": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "order_num": {
+                "rating": {
                     "type": "integer"
                 },
-                "passed_test": {
-                    "type": "boolean"
-                },
-                "requires_test": {
-                    "type": "boolean"
-                },
-                "test_score": {
+                "reports_count": {
+                    "description": "ReportsCount - число жалоб, видно только администраторам",
                     "type": "integer"
                 },
-                "title": {
+                "text": {
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
                 },
-                "viewed_at": {
+                "user_id": {
+                    "type": "string"
+                },
+                "user_name": {
                     "type": "string"
                 }
             }
         },
-        "models.Question": {
+        "models.ReviewQuestion": {
             "type": "object",
             "properties": {
-                "correct_answer": {
+                "answer": {
+                    "$ref": "#/definitions/models.Answer"
+                },
+                "correct": {
+                    "type": "boolean"
+                },
+                "max_points": {
                     "type": "integer"
                 },
+                "points": {
+                    "type": "number"
+                },
+                "question": {
+                    "$ref": "#/definitions/models.Question"
+                }
+            }
+        },
+        "models.ReviewReport": {
+            "type": "object",
+            "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "id": {
+                "reason": {
                     "type": "string"
                 },
-                "options": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "question_text": {
+                "review_id": {
                     "type": "string"
                 },
-                "test_id": {
+                "user_id": {
                     "type": "string"
+                }
+            }
+        },
+        "models.TagQuota": {
+            "type": "object",
+            "required": [
+                "tag"
+            ],
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 3
                 },
-                "updated_at": {
-                    "type": "string"
+                "tag": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "основы"
                 }
             }
         },
         "models.Test": {
             "type": "object",
             "properties": {
+                "cooldown_seconds": {
+                    "description": "CooldownSeconds - пауза между попытками; удваивается после каждой неудачной попытки подряд",
+                    "type": "integer"
+                },
                 "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
+                "late_submission": {
+                    "type": "string"
+                },
                 "lesson_id": {
                     "type": "string"
                 },
+                "max_attempts": {
+                    "description": "MaxAttempts - сколько раз можно сдать тест, 0 - без ограничения",
+                    "type": "integer"
+                },
                 "passing_score": {
                     "type": "integer"
                 },
+                "question_count": {
+                    "description": "QuestionCount - сколько вопросов из пула выдается в попытке, 0 - все вопросы",
+                    "type": "integer"
+                },
+                "review_policy": {
+                    "type": "string"
+                },
+                "scoring_policy": {
+                    "type": "string"
+                },
+                "shuffle_options": {
+                    "type": "boolean"
+                },
+                "shuffle_questions": {
+                    "type": "boolean"
+                },
+                "tag_quotas": {
+                    "description": "TagQuotas - сколько из выдаваемых вопросов взять с каждым тегом",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TagQuota"
+                    }
+                },
+                "time_limit_seconds": {
+                    "description": "TimeLimitSeconds - время на попытку, 0 - без ограничения",
+                    "type": "integer"
+                },
                 "updated_at": {
                     "type": "string"
                 }
             }
         },
+        "models.TestContent": {
+            "type": "object",
+            "properties": {
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Question"
+                    }
+                },
+                "test": {
+                    "$ref": "#/definitions/models.Test"
+                }
+            }
+        },
         "models.TestResponse": {
             "type": "object",
             "properties": {
+                "answer_schema": {
+                    "description": "Версия формата ответов, которую ожидает отправка теста",
+                    "type": "integer"
+                },
+                "attempt_id": {
+                    "description": "Текущая попытка: ответы отправляются на ее вопросы. Нет, если новую попытку начать нельзя.",
+                    "type": "string"
+                },
+                "attempts": {
+                    "description": "Оставшиеся попытки, пауза до следующей попытки и результат по правилу подсчета теста",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AttemptStatus"
+                        }
+                    ]
+                },
                 "attempts_count": {
                     "type": "integer"
                 },
+                "expires_at": {
+                    "description": "ExpiresAt - до какого момента принимаются ответы, если у теста есть лимит времени",
+                    "type": "string"
+                },
                 "last_score": {
                     "description": "Информация о прогрессе",
                     "type": "integer"
@@ -1093,12 +4443,15 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "questions": {
-                    "description": "Вопросы теста (без правильных ответов)",
+                    "description": "Вопросы текущей попытки (без правильных ответов), в порядке попытки",
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.Question"
                     }
                 },
+                "started_at": {
+                    "type": "string"
+                },
                 "test": {
                     "description": "Основная информация о тесте",
                     "allOf": [
@@ -1138,6 +4491,177 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "pagination.Page-models_AttemptSummary": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AttemptSummary"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor - курсор следующей страницы, отсутствует на последней странице",
+                    "type": "string"
+                },
+                "total_estimate": {
+                    "description": "TotalEstimate - число элементов во всем списке, см. EstimateTotal",
+                    "type": "integer"
+                }
+            }
+        },
+        "pagination.Page-models_Category": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Category"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor - курсор следующей страницы, отсутствует на последней странице",
+                    "type": "string"
+                },
+                "total_estimate": {
+                    "description": "TotalEstimate - число элементов во всем списке, см. EstimateTotal",
+                    "type": "integer"
+                }
+            }
+        },
+        "pagination.Page-models_Certificate": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Certificate"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor - курсор следующей страницы, отсутствует на последней странице",
+                    "type": "string"
+                },
+                "total_estimate": {
+                    "description": "TotalEstimate - число элементов во всем списке, см. EstimateTotal",
+                    "type": "integer"
+                }
+            }
+        },
+        "pagination.Page-models_Course": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Course"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor - курсор следующей страницы, отсутствует на последней странице",
+                    "type": "string"
+                },
+                "total_estimate": {
+                    "description": "TotalEstimate - число элементов во всем списке, см. EstimateTotal",
+                    "type": "integer"
+                }
+            }
+        },
+        "pagination.Page-models_ModerationEvent": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModerationEvent"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor - курсор следующей страницы, отсутствует на последней странице",
+                    "type": "string"
+                },
+                "total_estimate": {
+                    "description": "TotalEstimate - число элементов во всем списке, см. EstimateTotal",
+                    "type": "integer"
+                }
+            }
+        },
+        "pagination.Page-models_Notification": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Notification"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor - курсор следующей страницы, отсутствует на последней странице",
+                    "type": "string"
+                },
+                "total_estimate": {
+                    "description": "TotalEstimate - число элементов во всем списке, см. EstimateTotal",
+                    "type": "integer"
+                }
+            }
+        },
+        "pagination.Page-models_PurchasedCourse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PurchasedCourse"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor - курсор следующей страницы, отсутствует на последней странице",
+                    "type": "string"
+                },
+                "total_estimate": {
+                    "description": "TotalEstimate - число элементов во всем списке, см. EstimateTotal",
+                    "type": "integer"
+                }
+            }
+        },
+        "pagination.Page-models_Review": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Review"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor - курсор следующей страницы, отсутствует на последней странице",
+                    "type": "string"
+                },
+                "total_estimate": {
+                    "description": "TotalEstimate - число элементов во всем списке, см. EstimateTotal",
+                    "type": "integer"
+                }
+            }
+        },
+        "pagination.Page-models_ReviewReport": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewReport"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor - курсор следующей страницы, отсутствует на последней странице",
+                    "type": "string"
+                },
+                "total_estimate": {
+                    "description": "TotalEstimate - число элементов во всем списке, см. EstimateTotal",
+                    "type": "integer"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -2,17 +2,28 @@ package app
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
 	"time"
 
 	_ "course2/docs" // Импорт для инициализации Swagger документации
 	"course2/internal/config"
 	"course2/internal/database"
+	"course2/internal/payment"
 	"course2/internal/repositories"
 	"course2/internal/services"
 	"course2/internal/transport/http/handler"
-	"course2/internal/transport/http/middleware"
+	"platform/auth"
+	"platform/health"
+	"platform/lifecycle"
+	"platform/logging"
+	"platform/metrics"
+	"platform/problem"
+	"platform/tracing"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -24,8 +35,12 @@ import (
 
 type App struct {
 	cfg        *config.Config
-	httpServer *gin.Engine
+	logger     *slog.Logger
+	router     *gin.Engine
+	httpServer *http.Server
+	db         *sql.DB
 	grpcConn   *grpc.ClientConn
+	checker    *health.Checker
 }
 
 // @title Образовательная платформа API
@@ -51,8 +66,8 @@ type App struct {
 // @tags.description profile "Операции с профилем пользователя"
 // @tags.name progress "Прогресс"
 // @tags.description progress "Отслеживание прогресса обучения"
-func New() (*App, error) {
-	a := &App{}
+func New(logger *slog.Logger) (*App, error) {
+	a := &App{logger: logger}
 
 	// Загрузка конфигурации
 	cfg, err := config.New()
@@ -65,6 +80,11 @@ func New() (*App, error) {
 	a.grpcConn, err = grpc.Dial(
 		a.cfg.Auth.GRPCAddress,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(
+			tracing.UnaryClientInterceptor(),
+			logging.UnaryClientInterceptor(),
+			metrics.UnaryClientInterceptor(),
+		),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка подключения к серверу авторизации: %w", err)
@@ -73,24 +93,43 @@ func New() (*App, error) {
 	// Настройка CORS
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowOrigins = []string{"*"}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", logging.HeaderRequestID}
+	corsConfig.ExposeHeaders = []string{logging.HeaderRequestID}
 	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
 	corsConfig.MaxAge = 12 * time.Hour
 
 	// Настройка Gin
 	gin.SetMode(os.Getenv("GIN_MODE"))
-	a.httpServer = gin.Default()
-	a.httpServer.Use(
+	a.router = gin.New()
+	a.router.Use(
 		cors.New(corsConfig),
-		gin.Recovery(),
-		gin.Logger(),
+		tracing.Middleware(),
+		logging.Middleware(logger),
+		logging.Recovery(logger),
 	)
+	metrics.Setup(a.router)
+	problem.Setup(a.router)
+
+	// Хелсчек для активных проверок API-шлюза
+	a.router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status": "ok",
+		})
+	})
 
 	// Подключение к базе данных
 	db, err := database.NewPostgresDB(a.cfg.Database.URL)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка подключения к базе данных: %w", err)
 	}
+	a.db = db
+	metrics.RegisterDB(db, "edu")
+
+	// Проверки живости и готовности: /livez и /readyz
+	a.checker = health.NewChecker(health.DefaultTimeout)
+	a.checker.Add("postgres", health.Postgres(db))
+	a.checker.Add("auth_grpc", health.GRPCConn(a.grpcConn))
+	a.checker.Register(a.router)
 
 	// Инициализация репозиториев
 	courseRepo := repositories.NewCourseRepository(db)
@@ -101,47 +140,99 @@ func New() (*App, error) {
 	purchaseRepo := repositories.NewPurchaseRepository(db)
 	userRepo := repositories.NewUserRepository(db)
 	categoryRepo := repositories.NewCategoryRepository(db)
+	moderationRepo := repositories.NewModerationRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	certificateRepo := repositories.NewCertificateRepository(db)
+	attemptRepo := repositories.NewAttemptRepository(db)
+	orderRepo := repositories.NewOrderRepository(db)
+
+	// Платежный провайдер. Пока подключен только fake для разработки, настоящий провайдер
+	// реализует payment.Provider и выбирается по Payments.Provider.
+	fakeProvider := payment.NewFake(a.cfg.Payments.WebhookSecret, a.cfg.Payments.FakeCheckoutURL)
+	a.logger.Warn("платежный провайдер fake: оплата не списывает деньги", slog.String("provider", a.cfg.Payments.Provider))
+
+	// Страница оплаты fake регистрируется только по явному флагу
+	var fakeCheckout *payment.Fake
+	if a.cfg.Payments.FakeCheckout {
+		fakeCheckout = fakeProvider
+		a.logger.Warn("включена страница оплаты fake: студенты сами завершают оплату заказов")
+	}
 
 	// Инициализация сервисов
-	courseService := services.NewCourseService(courseRepo, lessonRepo, testRepo, reviewRepo)
+	courseService := services.NewCourseService(courseRepo, lessonRepo, testRepo, purchaseRepo)
 	userService := services.NewUserService(userRepo, purchaseRepo)
-	paymentService := services.NewPaymentService(courseRepo, purchaseRepo)
-	moderationService := services.NewModerationService(courseRepo)
+	paymentService := services.NewPaymentService(courseRepo, purchaseRepo, orderRepo, fakeProvider, a.cfg.Payments.Currency)
+	moderationService := services.NewModerationService(courseRepo, moderationRepo, userRepo)
 	categoryService := services.NewCategoryService(categoryRepo)
+	authoringService := services.NewAuthoringService(courseRepo, lessonRepo, testRepo, attemptRepo, courseService, moderationService)
+	notificationService := services.NewNotificationService(notificationRepo)
+	progressService := services.NewProgressService(courseRepo, lessonRepo, purchaseRepo, progressRepo, a.cfg.Progress.CourseCompletionXP)
+	testService := services.NewTestService(testRepo, attemptRepo)
+	certificateService := services.NewCertificateService(certificateRepo, userRepo, courseRepo, purchaseRepo, a.cfg.Certificates.VerifyURL)
+	progressService.OnCourseCompleted(certificateService.CourseCompleted)
+	progressService.OnCourseCompleted(notificationService.CourseCompleted)
+	reviewService := services.NewReviewService(courseRepo, reviewRepo, purchaseRepo, progressRepo, a.cfg.Reviews.MinProgress)
 
 	// Инициализация HTTP обработчиков
-	authRolesMiddleware := middleware.NewRolesMiddleware(a.grpcConn)
-
-	handler.NewCourseHandler(a.httpServer, courseService, authRolesMiddleware)
-	handler.NewStudentHandler(a.httpServer, courseService, paymentService, progressRepo, purchaseRepo, authRolesMiddleware)
-	handler.NewProgressHandler(a.httpServer, courseService, progressRepo, authRolesMiddleware)
-	handler.NewProfileHandler(a.httpServer, userService, authRolesMiddleware)
-	handler.NewAdminHandler(a.httpServer, moderationService, authRolesMiddleware)
-	handler.NewCategoryHandler(a.httpServer, categoryService)
+	authRolesMiddleware := auth.NewMiddleware(a.grpcConn, a.cfg.Auth.Check)
+
+	handler.NewCourseHandler(a.router, courseService, authRolesMiddleware)
+	handler.NewStudentHandler(a.router, courseService, paymentService, progressService, testService, progressRepo, purchaseRepo, authRolesMiddleware)
+	handler.NewProgressHandler(a.router, courseService, progressService, testService, progressRepo, authRolesMiddleware)
+	handler.NewProfileHandler(a.router, userService, notificationService, authRolesMiddleware)
+	handler.NewAdminHandler(a.router, moderationService, authRolesMiddleware)
+	handler.NewAuthorHandler(a.router, authoringService, notificationService, authRolesMiddleware)
+	handler.NewCategoryHandler(a.router, categoryService)
+	handler.NewReviewHandler(a.router, reviewService, authRolesMiddleware)
+	handler.NewCertificateHandler(a.router, certificateService, authRolesMiddleware)
+	handler.NewPaymentHandler(a.router, paymentService, fakeCheckout, authRolesMiddleware)
 
 	// Настройка Swagger
-	a.httpServer.GET("/api/v1/edu/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	a.router.GET("/api/v1/edu/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	a.httpServer = lifecycle.NewHTTPServer(a.cfg.Server.Port, a.router)
 
 	return a, nil
 }
 
-// Run запускает приложение
-func (a *App) Run() error {
-	fmt.Printf("Запуск HTTP сервера на порту %s\n", a.cfg.Server.Port)
-	if err := a.httpServer.Run(":" + a.cfg.Server.Port); err != nil {
-		return fmt.Errorf("ошибка HTTP сервера: %w", err)
-	}
-	return nil
+// Run запускает приложение и останавливает его после отмены контекста
+func (a *App) Run(ctx context.Context) error {
+	return lifecycle.Run(ctx, a.cfg.Shutdown.Timeout, a.Stop, func() error {
+		a.logger.Info("запуск HTTP сервера", slog.String("port", a.cfg.Server.Port))
+		if err := lifecycle.ServeHTTP(a.httpServer); err != nil {
+			return fmt.Errorf("ошибка HTTP сервера: %w", err)
+		}
+		return nil
+	})
 }
 
-// Stop останавливает приложение
+// Stop останавливает приложение: /readyz начинает отвечать 503, через DrainDelay
+// сервер перестает принимать соединения и ждет завершения начатых запросов,
+// затем закрываются gRPC соединение и пул соединений с базой данных
 func (a *App) Stop(ctx context.Context) error {
+	a.logger.Info("остановка приложения", slog.Duration("drain_delay", a.cfg.Shutdown.DrainDelay))
+
+	a.checker.Drain()
+	a.httpServer.SetKeepAlivesEnabled(false)
+	lifecycle.Wait(ctx, a.cfg.Shutdown.DrainDelay)
+
+	var errs []error
+	if err := a.httpServer.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("остановка HTTP сервера: %w", err))
+	}
+
 	// Закрываем gRPC соединение
-	if a.grpcConn != nil {
-		if err := a.grpcConn.Close(); err != nil {
-			return err
-		}
+	if err := a.grpcConn.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("закрытие gRPC соединения: %w", err))
+	}
+
+	if err := a.db.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("закрытие базы данных: %w", err))
 	}
 
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	a.logger.Info("приложение остановлено")
 	return nil
 }
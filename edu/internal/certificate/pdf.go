@@ -0,0 +1,109 @@
+// Package certificate формирует PDF сертификата о прохождении курса.
+package certificate
+
+import (
+	"course2/internal/models"
+	"fmt"
+	"io"
+
+	"github.com/boombuler/barcode/qr"
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const (
+	fontFamily = "Go"
+	// qrSize - сторона QR-кода в миллиметрах
+	qrSize = 36.0
+)
+
+// WritePDF рисует сертификат на листе A4 альбомной ориентации и пишет PDF в w.
+// verifyURL - ссылка на страницу проверки, она же кодируется в QR-код.
+func WritePDF(w io.Writer, cert *models.Certificate, verifyURL string) error {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetTitle("Сертификат "+cert.Serial, true)
+	pdf.SetSubject(cert.CourseTitle, true)
+	pdf.SetCreator("edu", true)
+	pdf.SetCreationDate(cert.IssuedAt)
+	pdf.SetAutoPageBreak(false, 0)
+
+	// Шрифты Go входят в модуль x/image и поддерживают кириллицу, поэтому PDF не зависит от шрифтов системы
+	pdf.AddUTF8FontFromBytes(fontFamily, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", gobold.TTF)
+	pdf.AddPage()
+
+	pageW, pageH := pdf.GetPageSize()
+
+	pdf.SetDrawColor(40, 70, 120)
+	pdf.SetLineWidth(1.2)
+	pdf.Rect(10, 10, pageW-20, pageH-20, "D")
+	pdf.SetLineWidth(0.3)
+	pdf.Rect(14, 14, pageW-28, pageH-28, "D")
+
+	centered := func(style string, size float64, height float64, text string) {
+		pdf.SetFont(fontFamily, style, size)
+		pdf.SetX(25)
+		pdf.MultiCell(pageW-50, height, text, "", "C", false)
+	}
+
+	pdf.SetTextColor(40, 70, 120)
+	pdf.SetY(32)
+	centered("B", 36, 16, "СЕРТИФИКАТ")
+	centered("", 16, 8, "о прохождении курса")
+
+	pdf.SetTextColor(30, 30, 30)
+	pdf.Ln(12)
+	centered("", 14, 8, "Настоящим подтверждается, что")
+	pdf.Ln(2)
+	centered("B", 28, 13, cert.StudentName)
+	pdf.Ln(2)
+	centered("", 14, 8, "успешно завершил(а) курс")
+	pdf.Ln(2)
+	centered("B", 22, 11, "«"+cert.CourseTitle+"»")
+	pdf.Ln(6)
+	centered("", 12, 7, "Дата завершения: "+cert.CompletedAt.UTC().Format("02.01.2006"))
+
+	qrX, qrY := pageW-25-qrSize, pageH-25-qrSize
+	if err := drawQR(pdf, verifyURL, qrX, qrY, qrSize); err != nil {
+		return err
+	}
+
+	pdf.SetTextColor(90, 90, 90)
+	pdf.SetFont(fontFamily, "", 10)
+	pdf.SetXY(25, qrY+qrSize-16)
+	pdf.CellFormat(qrX-30, 5, "Серийный номер: "+cert.Serial, "", 1, "L", false, 0, "")
+	pdf.SetX(25)
+	pdf.CellFormat(qrX-30, 5, "Проверить подлинность:", "", 1, "L", false, 0, "")
+	pdf.SetX(25)
+	pdf.SetFont(fontFamily, "", 9)
+	pdf.CellFormat(qrX-30, 5, verifyURL, "", 1, "L", false, 0, verifyURL)
+
+	if err := pdf.Output(w); err != nil {
+		return fmt.Errorf("формирование PDF: %w", err)
+	}
+	return nil
+}
+
+// drawQR рисует QR-код векторными квадратами, чтобы он оставался четким при любом масштабе
+func drawQR(pdf *fpdf.Fpdf, content string, x, y, size float64) error {
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return fmt.Errorf("построение QR-кода: %w", err)
+	}
+
+	bounds := code.Bounds()
+	modules := bounds.Dx()
+	module := size / float64(modules)
+
+	pdf.SetFillColor(0, 0, 0)
+	for row := 0; row < modules; row++ {
+		for col := 0; col < modules; col++ {
+			r, _, _, _ := code.At(bounds.Min.X+col, bounds.Min.Y+row).RGBA()
+			if r == 0 {
+				pdf.Rect(x+float64(col)*module, y+float64(row)*module, module, module, "F")
+			}
+		}
+	}
+	return nil
+}
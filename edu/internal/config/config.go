@@ -2,37 +2,64 @@ package config
 
 import (
 	"os"
+	"time"
+
+	"platform/auth"
+	"platform/config"
 )
 
 type Config struct {
 	Database struct {
-		URL string
-	}
+		URL string `yaml:"url" env:"DB_URL" validate:"required"`
+	} `yaml:"database"`
 	Server struct {
-		Port string
-	}
+		Port string `yaml:"port" env:"SERVER_PORT" default:"8081" validate:"required"`
+	} `yaml:"server"`
 	Auth struct {
-		GRPCAddress string
-	}
+		GRPCAddress string `yaml:"grpc_address" env:"AUTH_GRPC_ADDRESS" default:"localhost:9090" validate:"required"`
+		// Check - таймаут проверки токена и кэш решений
+		Check auth.Config `yaml:"check"`
+	} `yaml:"auth"`
+	Progress struct {
+		// CourseCompletionXP - бонус XP за прохождение всех уроков курса
+		CourseCompletionXP int `yaml:"course_completion_xp" env:"COURSE_COMPLETION_XP" default:"50" validate:"gte=0"`
+	} `yaml:"progress"`
+	Reviews struct {
+		// MinProgress - процент прохождения курса, после которого можно оставить отзыв. 0 - достаточно покупки.
+		MinProgress float64 `yaml:"min_progress" env:"REVIEW_MIN_PROGRESS" default:"0" validate:"gte=0,lte=100"`
+	} `yaml:"reviews"`
+	Certificates struct {
+		// VerifyURL - адрес публичной проверки сертификата, к нему добавляется серийный номер.
+		// Печатается в PDF и кодируется в QR-код.
+		VerifyURL string `yaml:"verify_url" env:"CERTIFICATE_VERIFY_URL" default:"http://localhost:8090/api/v1/edu/certificates/" validate:"required,url"`
+	} `yaml:"certificates"`
+	Payments struct {
+		// Provider - платежный провайдер. fake - для разработки: денег не списывает,
+		// оплату завершает подписанное уведомление или страница оплаты (см. FakeCheckout).
+		Provider string `yaml:"provider" env:"PAYMENT_PROVIDER" default:"fake" validate:"oneof=fake"`
+		// Currency - валюта цен курсов
+		Currency string `yaml:"currency" env:"PAYMENT_CURRENCY" default:"RUB" validate:"len=3"`
+		// WebhookSecret - ключ подписи уведомлений провайдера
+		WebhookSecret string `yaml:"webhook_secret" env:"PAYMENT_WEBHOOK_SECRET" secret:"true" validate:"required,min=32"`
+		// FakeCheckout включает страницу оплаты провайдера fake, на которой студент сам завершает
+		// оплату своего заказа. Только для разработки: курс выдается без списания денег.
+		FakeCheckout bool `yaml:"fake_checkout" env:"PAYMENT_FAKE_CHECKOUT" default:"false"`
+		// FakeCheckoutURL - адрес страницы оплаты провайдера fake, к нему добавляется номер сессии
+		FakeCheckoutURL string `yaml:"fake_checkout_url" env:"PAYMENT_FAKE_CHECKOUT_URL" default:"http://localhost:8090/api/v1/edu/payments/fake/sessions/" validate:"required,url"`
+	} `yaml:"payments"`
+	Shutdown struct {
+		// Timeout - общее время на остановку, включая DrainDelay
+		Timeout time.Duration `yaml:"timeout" env:"SHUTDOWN_TIMEOUT" default:"20s" validate:"gt=0"`
+		// DrainDelay - время между переходом /readyz в 503 и закрытием HTTP сервера
+		DrainDelay time.Duration `yaml:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY" default:"5s" validate:"gte=0,ltefield=Timeout"`
+	} `yaml:"shutdown"`
 }
 
+// New загружает конфигурацию из значений по умолчанию, YAML-файла, переменных окружения и флагов
 func New() (*Config, error) {
 	cfg := &Config{}
-
-	// Database configuration
-	cfg.Database.URL = os.Getenv("DB_URL")
-
-	// Server configuration
-	cfg.Server.Port = os.Getenv("SERVER_PORT")
-	if cfg.Server.Port == "" {
-		cfg.Server.Port = "8081" // default port
-	}
-
-	// Auth configuration
-	cfg.Auth.GRPCAddress = os.Getenv("AUTH_GRPC_ADDRESS")
-	if cfg.Auth.GRPCAddress == "" {
-		cfg.Auth.GRPCAddress = "localhost:9090" // default address
+	if err := config.Load(cfg, os.Args[1:]); err != nil {
+		return nil, err
 	}
-
 	return cfg, nil
 }
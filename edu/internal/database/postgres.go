@@ -2,12 +2,13 @@ package database
 
 import (
 	"database/sql"
+	"platform/tracing"
 
 	_ "github.com/lib/pq"
 )
 
 func NewPostgresDB(url string) (*sql.DB, error) {
-	db, err := sql.Open("postgres", url)
+	db, err := tracing.OpenDB("postgres", url)
 	if err != nil {
 		return nil, err
 	}
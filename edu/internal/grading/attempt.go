@@ -0,0 +1,109 @@
+package grading
+
+import (
+	"course2/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// GradeAttempt проверяет ответы по вопросам попытки. Ответы даны в порядке вариантов, показанном
+// студенту, и переводятся к исходному порядку, в котором записан правильный ответ.
+// Возвращает результат и ответы в исходном порядке.
+func GradeAttempt(attempt *models.TestAttempt, answers map[uuid.UUID]models.Answer) (*Result, map[uuid.UUID]models.Answer) {
+	original := make(map[uuid.UUID]models.Answer, len(answers))
+	for _, item := range attempt.Items {
+		if answer, ok := answers[item.Question.ID]; ok {
+			original[item.Question.ID] = ToOriginal(item, answer)
+		}
+	}
+	return Grade(attempt.Questions(), original), original
+}
+
+// ToOriginal переводит номера вариантов в ответе из порядка попытки в исходный порядок вопроса.
+// Номера вне списка вариантов заменяются на -1 и не засчитываются.
+func ToOriginal(item *models.AttemptItem, answer models.Answer) models.Answer {
+	return translate(answer, item.OptionOrder, item.MatchOrder)
+}
+
+// ToServed переводит номера вариантов в ответе из исходного порядка вопроса в порядок попытки
+func ToServed(item *models.AttemptItem, answer models.Answer) models.Answer {
+	return translate(answer, inverse(item.OptionOrder), inverse(item.MatchOrder))
+}
+
+// ServedKey возвращает правильный ответ с номерами вариантов в порядке попытки
+func ServedKey(item *models.AttemptItem) *models.AnswerKey {
+	if item.Question.Key == nil {
+		return nil
+	}
+	key := *item.Question.Key
+	if item.OptionOrder == nil && item.MatchOrder == nil {
+		return &key
+	}
+
+	options, matches := inverse(item.OptionOrder), inverse(item.MatchOrder)
+	key.Choices = remap(key.Choices, options)
+	key.Order = remap(key.Order, options)
+	key.Pairs = translatePairs(key.Pairs, options, matches)
+	return &key
+}
+
+// translate переводит номера вариантов ответа по перестановкам options и matches,
+// где options[i] - новый номер варианта i
+func translate(answer models.Answer, options, matches []int) models.Answer {
+	if options == nil && matches == nil {
+		return answer
+	}
+
+	result := answer
+	result.Choices = remap(answer.Choices, options)
+	result.Order = remap(answer.Order, options)
+	if options == nil || len(answer.Pairs) == len(options) {
+		result.Pairs = translatePairs(answer.Pairs, options, matches)
+	}
+	return result
+}
+
+func translatePairs(pairs, options, matches []int) []int {
+	if pairs == nil {
+		return nil
+	}
+	result := make([]int, len(pairs))
+	for option, match := range pairs {
+		result[index(options, option)] = index(matches, match)
+	}
+	return result
+}
+
+func remap(indexes, order []int) []int {
+	if indexes == nil {
+		return nil
+	}
+	result := make([]int, len(indexes))
+	for i, value := range indexes {
+		result[i] = index(order, value)
+	}
+	return result
+}
+
+// index возвращает номер, под которым вариант i стоит в перестановке order
+func index(order []int, i int) int {
+	if order == nil {
+		return i
+	}
+	if i < 0 || i >= len(order) {
+		return -1
+	}
+	return order[i]
+}
+
+// inverse возвращает обратную перестановку
+func inverse(order []int) []int {
+	if order == nil {
+		return nil
+	}
+	result := make([]int, len(order))
+	for i, value := range order {
+		result[value] = i
+	}
+	return result
+}
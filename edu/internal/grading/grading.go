@@ -0,0 +1,180 @@
+// Package grading проверяет ответы на вопросы тестов и считает результат.
+package grading
+
+import (
+	"course2/internal/models"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Result - результат проверки ответов на тест
+type Result struct {
+	// Score - процент набранных баллов от максимума, от 0 до 100
+	Score     int              `json:"score"`
+	Points    float64          `json:"points"`
+	MaxPoints int              `json:"max_points"`
+	Questions []QuestionResult `json:"questions"`
+}
+
+// QuestionResult - результат по одному вопросу
+type QuestionResult struct {
+	QuestionID uuid.UUID `json:"question_id"`
+	Points     float64   `json:"points"`
+	MaxPoints  int       `json:"max_points"`
+	// Correct - ответ полностью верный
+	Correct     bool   `json:"correct"`
+	Explanation string `json:"explanation,omitempty"`
+}
+
+// Grade проверяет ответы на вопросы теста. Вопрос без ответа приносит 0 баллов.
+func Grade(questions []*models.Question, answers map[uuid.UUID]models.Answer) *Result {
+	result := &Result{Questions: make([]QuestionResult, 0, len(questions))}
+	for _, question := range questions {
+		var credit float64
+		if answer, ok := answers[question.ID]; ok {
+			credit = Credit(question, answer)
+		}
+
+		points := float64(question.Points) * credit
+		result.Points += points
+		result.MaxPoints += question.Points
+		result.Questions = append(result.Questions, QuestionResult{
+			QuestionID:  question.ID,
+			Points:      roundPoints(points),
+			MaxPoints:   question.Points,
+			Correct:     credit == 1,
+			Explanation: question.Explanation,
+		})
+	}
+
+	result.Points = roundPoints(result.Points)
+	if result.MaxPoints > 0 {
+		// Как и раньше, процент округляется вниз: 2 из 3 - это 66, а не 67
+		result.Score = int(math.Floor(result.Points*100/float64(result.MaxPoints) + 1e-9))
+	}
+	return result
+}
+
+// Credit возвращает долю баллов за ответ, от 0 до 1
+func Credit(question *models.Question, answer models.Answer) float64 {
+	key := question.Key
+	if key == nil {
+		return 0
+	}
+
+	switch question.Type {
+	case models.QuestionSingleChoice:
+		if len(answer.Choices) == 1 && slices.Contains(key.Choices, answer.Choices[0]) {
+			return 1
+		}
+
+	case models.QuestionMultipleChoice:
+		return choicesCredit(key.Choices, answer.Choices, len(question.Options))
+
+	case models.QuestionTrueFalse:
+		if answer.Bool != nil && key.Bool != nil && *answer.Bool == *key.Bool {
+			return 1
+		}
+
+	case models.QuestionNumeric:
+		if answer.Number != nil && key.Number != nil && math.Abs(*answer.Number-*key.Number) <= key.Tolerance+1e-9 {
+			return 1
+		}
+
+	case models.QuestionShortText:
+		if answer.Text == nil {
+			return 0
+		}
+		text := NormalizeText(*answer.Text, key.CaseSensitive)
+		for _, accepted := range key.Accepted {
+			if text == NormalizeText(accepted, key.CaseSensitive) {
+				return 1
+			}
+		}
+
+	case models.QuestionOrdering:
+		if !isPermutation(answer.Order, len(key.Order)) {
+			return 0
+		}
+		return matchedShare(key.Order, answer.Order)
+
+	case models.QuestionMatching:
+		if len(answer.Pairs) != len(key.Pairs) {
+			return 0
+		}
+		return matchedShare(key.Pairs, answer.Pairs)
+	}
+
+	return 0
+}
+
+// choicesCredit засчитывает множественный выбор частично: каждый верный вариант дает долю баллов,
+// каждый неверный столько же отнимает. Отметить все варианты не выгодно.
+func choicesCredit(correct, chosen []int, optionsCount int) float64 {
+	if len(correct) == 0 {
+		return 0
+	}
+
+	seen := make(map[int]bool, len(chosen))
+	right, wrong := 0, 0
+	for _, choice := range chosen {
+		if choice < 0 || choice >= optionsCount || seen[choice] {
+			continue
+		}
+		seen[choice] = true
+		if slices.Contains(correct, choice) {
+			right++
+		} else {
+			wrong++
+		}
+	}
+
+	return max(0, float64(right-wrong)/float64(len(correct)))
+}
+
+// matchedShare возвращает долю позиций, на которых ответ совпадает с ключом
+func matchedShare(key, answer []int) float64 {
+	if len(key) == 0 {
+		return 0
+	}
+	matched := 0
+	for i := range key {
+		if key[i] == answer[i] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(key))
+}
+
+// isPermutation проверяет, что values - перестановка чисел от 0 до n-1
+func isPermutation(values []int, n int) bool {
+	if len(values) != n {
+		return false
+	}
+	seen := make([]bool, n)
+	for _, v := range values {
+		if v < 0 || v >= n || seen[v] {
+			return false
+		}
+		seen[v] = true
+	}
+	return true
+}
+
+// NormalizeText приводит короткий ответ к виду для сравнения: убирает лишние пробелы,
+// заменяет ё на е и, если регистр не важен, приводит к нижнему регистру
+func NormalizeText(text string, caseSensitive bool) string {
+	text = strings.Join(strings.Fields(text), " ")
+	text = strings.NewReplacer("ё", "е", "Ё", "Е").Replace(text)
+	if !caseSensitive {
+		text = strings.ToLower(text)
+	}
+	return text
+}
+
+func roundPoints(points float64) float64 {
+	return math.Round(points*100) / 100
+}
@@ -0,0 +1,173 @@
+package grading
+
+import (
+	"course2/internal/models"
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestChoicesCredit(t *testing.T) {
+	tests := []struct {
+		name    string
+		correct []int
+		chosen  []int
+		want    float64
+	}{
+		{"все верные", []int{0, 2}, []int{0, 2}, 1},
+		{"один из двух верных", []int{0, 2}, []int{2}, 0.5},
+		{"верный и неверный", []int{0, 2}, []int{0, 1}, 0},
+		{"неверных больше верных - не меньше 0", []int{0}, []int{0, 1, 2, 3}, 0},
+		{"только неверные", []int{0, 2}, []int{1, 3}, 0},
+		{"ничего не выбрано", []int{0, 2}, nil, 0},
+		{"повторы считаются один раз", []int{0, 2}, []int{0, 0, 0}, 0.5},
+		{"повтор неверного отнимает один раз", []int{0, 1, 2}, []int{0, 1, 3, 3}, 1.0 / 3},
+		{"номера вне списка вариантов не считаются", []int{0, 2}, []int{0, 2, -1, 4, 100}, 1},
+		{"пустой ключ", nil, []int{0}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := choicesCredit(tt.correct, tt.chosen, 4)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("choicesCredit(%v, %v) = %v, want %v", tt.correct, tt.chosen, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredit(t *testing.T) {
+	numeric := &models.Question{
+		Type: models.QuestionNumeric,
+		Key:  &models.AnswerKey{Number: ptr(3.14), Tolerance: 0.01},
+	}
+	exact := &models.Question{
+		Type: models.QuestionNumeric,
+		Key:  &models.AnswerKey{Number: ptr(0.3)},
+	}
+	text := &models.Question{
+		Type: models.QuestionShortText,
+		Key:  &models.AnswerKey{Accepted: []string{"Ёжик в тумане"}},
+	}
+	caseSensitive := &models.Question{
+		Type: models.QuestionShortText,
+		Key:  &models.AnswerKey{Accepted: []string{"Go"}, CaseSensitive: true},
+	}
+	ordering := &models.Question{
+		Type:    models.QuestionOrdering,
+		Options: []string{"a", "b", "c", "d"},
+		Key:     &models.AnswerKey{Order: []int{2, 0, 3, 1}},
+	}
+	matching := &models.Question{
+		Type:    models.QuestionMatching,
+		Options: []string{"a", "b", "c", "d"},
+		Matches: []string{"w", "x", "y", "z"},
+		Key:     &models.AnswerKey{Pairs: []int{1, 0, 3, 2}},
+	}
+	single := &models.Question{
+		Type:    models.QuestionSingleChoice,
+		Options: []string{"a", "b", "c"},
+		Key:     &models.AnswerKey{Choices: []int{1}},
+	}
+	multiple := &models.Question{
+		Type:    models.QuestionMultipleChoice,
+		Options: []string{"a", "b", "c", "d"},
+		Key:     &models.AnswerKey{Choices: []int{0, 3}},
+	}
+
+	tests := []struct {
+		name     string
+		question *models.Question
+		answer   models.Answer
+		want     float64
+	}{
+		{"число точно", numeric, models.Answer{Number: ptr(3.14)}, 1},
+		{"число на верхней границе допуска", numeric, models.Answer{Number: ptr(3.15)}, 1},
+		{"число на нижней границе допуска", numeric, models.Answer{Number: ptr(3.13)}, 1},
+		{"число за границей допуска", numeric, models.Answer{Number: ptr(3.1501)}, 0},
+		{"число без допуска с ошибкой округления", exact, models.Answer{Number: ptr(0.1 + 0.2)}, 1},
+		{"число без ответа", numeric, models.Answer{}, 0},
+
+		{"текст как в ключе", text, models.Answer{Text: ptr("Ёжик в тумане")}, 1},
+		{"текст через е", text, models.Answer{Text: ptr("ежик в тумане")}, 1},
+		{"текст в другом регистре и с пробелами", text, models.Answer{Text: ptr("  ЕЖИК   в\tТУМАНЕ ")}, 1},
+		{"другой текст", text, models.Answer{Text: ptr("ежик")}, 0},
+		{"регистр важен", caseSensitive, models.Answer{Text: ptr("go")}, 0},
+		{"регистр важен, совпадает", caseSensitive, models.Answer{Text: ptr(" Go ")}, 1},
+
+		{"порядок верный", ordering, models.Answer{Order: []int{2, 0, 3, 1}}, 1},
+		{"порядок наполовину верный", ordering, models.Answer{Order: []int{2, 0, 1, 3}}, 0.5},
+		{"порядок не перестановка", ordering, models.Answer{Order: []int{2, 2, 3, 1}}, 0},
+		{"порядок короче ключа", ordering, models.Answer{Order: []int{2, 0, 3}}, 0},
+		{"порядок вне списка", ordering, models.Answer{Order: []int{2, 0, 3, 4}}, 0},
+
+		{"сопоставление верное", matching, models.Answer{Pairs: []int{1, 0, 3, 2}}, 1},
+		{"сопоставление на три четверти", matching, models.Answer{Pairs: []int{1, 0, 3, 3}}, 0.75},
+		{"сопоставление короче ключа", matching, models.Answer{Pairs: []int{1, 0, 3}}, 0},
+		{"сопоставление длиннее ключа", matching, models.Answer{Pairs: []int{1, 0, 3, 2, 0}}, 0},
+
+		{"один вариант верный", single, models.Answer{Choices: []int{1}}, 1},
+		{"один вариант, выбрано два", single, models.Answer{Choices: []int{1, 0}}, 0},
+		{"несколько вариантов частично", multiple, models.Answer{Choices: []int{3}}, 0.5},
+		{"вопрос без ключа", &models.Question{Type: models.QuestionSingleChoice}, models.Answer{Choices: []int{0}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Credit(tt.question, tt.answer)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Credit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		text          string
+		caseSensitive bool
+		want          string
+	}{
+		{"Ёлка", false, "елка"},
+		{"Ёлка", true, "Елка"},
+		{"ещё", false, "еще"},
+		{"  Привет,\t\nмир  ", false, "привет, мир"},
+		{"Hello   World", true, "Hello World"},
+		{"", false, ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeText(tt.text, tt.caseSensitive); got != tt.want {
+			t.Errorf("NormalizeText(%q, %v) = %q, want %q", tt.text, tt.caseSensitive, got, tt.want)
+		}
+	}
+}
+
+func TestGrade(t *testing.T) {
+	questions := []*models.Question{
+		{ID: uuid.New(), Type: models.QuestionTrueFalse, Points: 1, Key: &models.AnswerKey{Bool: ptr(true)}},
+		{ID: uuid.New(), Type: models.QuestionTrueFalse, Points: 1, Key: &models.AnswerKey{Bool: ptr(true)}},
+		{ID: uuid.New(), Type: models.QuestionTrueFalse, Points: 1, Key: &models.AnswerKey{Bool: ptr(false)}},
+	}
+	answers := map[uuid.UUID]models.Answer{
+		questions[0].ID: {Bool: ptr(true)},
+		questions[2].ID: {Bool: ptr(false)},
+	}
+
+	result := Grade(questions, answers)
+	if result.Points != 2 || result.MaxPoints != 3 {
+		t.Fatalf("Grade() points = %v/%v, want 2/3", result.Points, result.MaxPoints)
+	}
+	// Процент округляется вниз
+	if result.Score != 66 {
+		t.Errorf("Grade() score = %v, want 66", result.Score)
+	}
+	if result.Questions[1].Correct || result.Questions[1].Points != 0 {
+		t.Errorf("вопрос без ответа засчитан: %+v", result.Questions[1])
+	}
+}
@@ -0,0 +1,70 @@
+package grading
+
+import (
+	"bytes"
+	"course2/internal/models"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Версии формата ответов на тест
+const (
+	// SchemaV1 - {"<id вопроса>": <номер варианта>}, только для single_choice
+	SchemaV1 = 1
+	// SchemaV2 - {"version": 2, "attempt_id": "...", "answers": {"<id вопроса>": Answer}}
+	SchemaV2 = 2
+	// SchemaVersion - текущая версия формата
+	SchemaVersion = SchemaV2
+)
+
+var (
+	ErrInvalidSubmission  = errors.New("ответы на тест не разобраны")
+	ErrUnsupportedVersion = errors.New("неподдерживаемая версия формата ответов")
+)
+
+// Submission - ответы студента на тест
+type Submission struct {
+	Version int `json:"version" example:"2"`
+	// AttemptID - попытка, на вопросы которой даны ответы. Если не указана, ответы относятся
+	// к текущей незавершенной попытке.
+	AttemptID *uuid.UUID                  `json:"attempt_id,omitempty"`
+	Answers   map[uuid.UUID]models.Answer `json:"answers"`
+}
+
+// ParseSubmission разбирает тело запроса с ответами. Тело без поля version считается
+// форматом версии 1 и преобразуется в ответы single_choice.
+func ParseSubmission(body []byte) (*Submission, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, ErrInvalidSubmission
+	}
+
+	if _, ok := fields["version"]; !ok {
+		var legacy map[uuid.UUID]int
+		if err := json.Unmarshal(body, &legacy); err != nil {
+			return nil, ErrInvalidSubmission
+		}
+
+		submission := &Submission{Version: SchemaV1, Answers: make(map[uuid.UUID]models.Answer, len(legacy))}
+		for questionID, choice := range legacy {
+			submission.Answers[questionID] = models.Answer{Choices: []int{choice}}
+		}
+		return submission, nil
+	}
+
+	submission := &Submission{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(submission); err != nil {
+		return nil, ErrInvalidSubmission
+	}
+	if submission.Version != SchemaV2 {
+		return nil, ErrUnsupportedVersion
+	}
+	if submission.Answers == nil {
+		submission.Answers = map[uuid.UUID]models.Answer{}
+	}
+	return submission, nil
+}
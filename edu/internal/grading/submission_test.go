@@ -0,0 +1,227 @@
+package grading
+
+import (
+	"course2/internal/models"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParseSubmission(t *testing.T) {
+	questionID := uuid.MustParse("6f1c1a52-8d0e-4d6b-9a57-1d2b6c3e4f50")
+	attemptID := uuid.MustParse("0b7c2d4e-1f3a-4b5c-8d9e-a0b1c2d3e4f5")
+
+	tests := []struct {
+		name    string
+		body    string
+		want    *Submission
+		wantErr error
+	}{
+		{
+			name: "версия 1",
+			body: `{"6f1c1a52-8d0e-4d6b-9a57-1d2b6c3e4f50": 2}`,
+			want: &Submission{
+				Version: SchemaV1,
+				Answers: map[uuid.UUID]models.Answer{questionID: {Choices: []int{2}}},
+			},
+		},
+		{
+			name: "версия 1 без ответов",
+			body: `{}`,
+			want: &Submission{Version: SchemaV1, Answers: map[uuid.UUID]models.Answer{}},
+		},
+		{
+			name:    "версия 1 с ответом не числом",
+			body:    `{"6f1c1a52-8d0e-4d6b-9a57-1d2b6c3e4f50": "2"}`,
+			wantErr: ErrInvalidSubmission,
+		},
+		{
+			name:    "версия 1 с неверным id вопроса",
+			body:    `{"q1": 2}`,
+			wantErr: ErrInvalidSubmission,
+		},
+		{
+			name: "версия 2",
+			body: `{"version": 2, "attempt_id": "0b7c2d4e-1f3a-4b5c-8d9e-a0b1c2d3e4f5",
+				"answers": {"6f1c1a52-8d0e-4d6b-9a57-1d2b6c3e4f50": {"choices": [0, 3]}}}`,
+			want: &Submission{
+				Version:   SchemaV2,
+				AttemptID: &attemptID,
+				Answers:   map[uuid.UUID]models.Answer{questionID: {Choices: []int{0, 3}}},
+			},
+		},
+		{
+			name: "версия 2 без ответов",
+			body: `{"version": 2}`,
+			want: &Submission{Version: SchemaV2, Answers: map[uuid.UUID]models.Answer{}},
+		},
+		{
+			name:    "неизвестная версия",
+			body:    `{"version": 3, "answers": {}}`,
+			wantErr: ErrUnsupportedVersion,
+		},
+		{
+			name:    "неизвестное поле",
+			body:    `{"version": 2, "answers": {}, "extra": true}`,
+			wantErr: ErrInvalidSubmission,
+		},
+		{
+			name:    "неизвестное поле ответа",
+			body:    `{"version": 2, "answers": {"6f1c1a52-8d0e-4d6b-9a57-1d2b6c3e4f50": {"choice": 1}}}`,
+			wantErr: ErrInvalidSubmission,
+		},
+		{
+			name:    "не объект",
+			body:    `[1, 2]`,
+			wantErr: ErrInvalidSubmission,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSubmission([]byte(tt.body))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseSubmission() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSubmission() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSubmission() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServedOrderRoundTrip(t *testing.T) {
+	// Студент видит варианты в порядке c, a, d, b и колонку сопоставления в порядке z, x, w, y
+	item := &models.AttemptItem{
+		Question: &models.Question{
+			ID:      uuid.New(),
+			Options: []string{"a", "b", "c", "d"},
+			Matches: []string{"w", "x", "y", "z"},
+			Key: &models.AnswerKey{
+				Choices: []int{0, 3},
+				Order:   []int{2, 0, 3, 1},
+				Pairs:   []int{1, 0, 3, 2},
+			},
+		},
+		OptionOrder: []int{2, 0, 3, 1},
+		MatchOrder:  []int{3, 1, 0, 2},
+	}
+
+	served := item.Served()
+	key := ServedKey(item)
+
+	// Правильный ответ в порядке попытки указывает на те же тексты вариантов
+	for i, choice := range key.Choices {
+		if served.Options[choice] != item.Question.Options[item.Question.Key.Choices[i]] {
+			t.Errorf("ServedKey choices[%d] = %q, want %q", i, served.Options[choice], item.Question.Options[item.Question.Key.Choices[i]])
+		}
+	}
+	for i, option := range key.Order {
+		if served.Options[option] != item.Question.Options[item.Question.Key.Order[i]] {
+			t.Errorf("ServedKey order[%d] = %q, want %q", i, served.Options[option], item.Question.Options[item.Question.Key.Order[i]])
+		}
+	}
+	for option, match := range key.Pairs {
+		original := item.Question.Key.Pairs[item.OptionOrder[option]]
+		if served.Matches[match] != item.Question.Matches[original] {
+			t.Errorf("ServedKey pairs[%d] = %q, want %q", option, served.Matches[match], item.Question.Matches[original])
+		}
+	}
+
+	// Ответ, совпадающий с правильным в порядке попытки, в исходном порядке совпадает с ключом
+	answer := models.Answer{Choices: key.Choices, Order: key.Order, Pairs: key.Pairs}
+	original := ToOriginal(item, answer)
+	want := models.Answer{Choices: item.Question.Key.Choices, Order: item.Question.Key.Order, Pairs: item.Question.Key.Pairs}
+	if !reflect.DeepEqual(original, want) {
+		t.Errorf("ToOriginal() = %+v, want %+v", original, want)
+	}
+	if back := ToServed(item, original); !reflect.DeepEqual(back, answer) {
+		t.Errorf("ToServed(ToOriginal()) = %+v, want %+v", back, answer)
+	}
+}
+
+func TestToOriginal(t *testing.T) {
+	item := &models.AttemptItem{
+		Question:    &models.Question{ID: uuid.New(), Options: []string{"a", "b", "c"}, Matches: []string{"x", "y", "z"}},
+		OptionOrder: []int{2, 0, 1},
+		MatchOrder:  []int{1, 2, 0},
+	}
+
+	tests := []struct {
+		name   string
+		answer models.Answer
+		want   models.Answer
+	}{
+		{
+			name:   "выбор",
+			answer: models.Answer{Choices: []int{0, 2}},
+			want:   models.Answer{Choices: []int{2, 1}},
+		},
+		{
+			name:   "номера вне списка вариантов",
+			answer: models.Answer{Choices: []int{-1, 3, 1}},
+			want:   models.Answer{Choices: []int{-1, -1, 0}},
+		},
+		{
+			name:   "порядок",
+			answer: models.Answer{Order: []int{1, 2, 0}},
+			want:   models.Answer{Order: []int{0, 1, 2}},
+		},
+		{
+			name:   "сопоставление",
+			answer: models.Answer{Pairs: []int{0, 1, 2}},
+			want:   models.Answer{Pairs: []int{2, 0, 1}},
+		},
+		{
+			name:   "сопоставление с номером вне списка",
+			answer: models.Answer{Pairs: []int{0, 5, 2}},
+			want:   models.Answer{Pairs: []int{-1, 0, 1}},
+		},
+		{
+			name:   "сопоставление неверной длины не переводится",
+			answer: models.Answer{Pairs: []int{0, 1}},
+			want:   models.Answer{Pairs: []int{0, 1}},
+		},
+		{
+			name:   "ответ без номеров вариантов",
+			answer: models.Answer{Bool: ptr(true)},
+			want:   models.Answer{Bool: ptr(true)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToOriginal(item, tt.answer); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToOriginal() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithoutShuffle(t *testing.T) {
+	item := &models.AttemptItem{
+		Question: &models.Question{ID: uuid.New(), Options: []string{"a", "b"}, Key: &models.AnswerKey{Choices: []int{1}}},
+	}
+	answer := models.Answer{Choices: []int{1, 7}}
+
+	if got := ToOriginal(item, answer); !reflect.DeepEqual(got, answer) {
+		t.Errorf("ToOriginal() = %+v, want %+v", got, answer)
+	}
+	if got := ToServed(item, answer); !reflect.DeepEqual(got, answer) {
+		t.Errorf("ToServed() = %+v, want %+v", got, answer)
+	}
+	if got := ServedKey(item); !reflect.DeepEqual(got, item.Question.Key) || got == item.Question.Key {
+		t.Errorf("ServedKey() = %+v, want copy of %+v", got, item.Question.Key)
+	}
+	if ServedKey(&models.AttemptItem{Question: &models.Question{}}) != nil {
+		t.Error("ServedKey() без ключа не nil")
+	}
+}
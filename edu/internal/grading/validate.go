@@ -0,0 +1,98 @@
+package grading
+
+import (
+	"course2/internal/models"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrInvalidQuestion         = errors.New("вопрос заполнен неверно")
+	ErrCorrectAnswerOutOfRange = errors.New("номер правильного ответа вне списка вариантов")
+)
+
+// ValidateQuestion проверяет, что у вопроса есть варианты и правильный ответ, подходящие для его типа.
+// Поля, которые тип не использует, очищаются.
+func ValidateQuestion(question *models.Question) error {
+	if question.Points < 1 {
+		return fmt.Errorf("%w: баллы за вопрос должны быть больше 0", ErrInvalidQuestion)
+	}
+
+	key := question.Key
+	if key == nil {
+		return fmt.Errorf("%w: не указан правильный ответ", ErrInvalidQuestion)
+	}
+
+	switch question.Type {
+	case models.QuestionSingleChoice, models.QuestionMultipleChoice:
+		question.Matches = nil
+		if len(question.Options) < 2 {
+			return fmt.Errorf("%w: нужно не меньше двух вариантов", ErrInvalidQuestion)
+		}
+		if question.Type == models.QuestionSingleChoice && len(key.Choices) != 1 {
+			return fmt.Errorf("%w: нужен ровно один правильный вариант", ErrInvalidQuestion)
+		}
+		if len(key.Choices) == 0 {
+			return fmt.Errorf("%w: нужен хотя бы один правильный вариант", ErrInvalidQuestion)
+		}
+		for i, choice := range key.Choices {
+			if choice < 0 || choice >= len(question.Options) {
+				return ErrCorrectAnswerOutOfRange
+			}
+			if slices.Contains(key.Choices[:i], choice) {
+				return fmt.Errorf("%w: правильный вариант указан дважды", ErrInvalidQuestion)
+			}
+		}
+
+	case models.QuestionTrueFalse:
+		question.Options, question.Matches = nil, nil
+		if key.Bool == nil {
+			return fmt.Errorf("%w: не указан правильный ответ", ErrInvalidQuestion)
+		}
+
+	case models.QuestionNumeric:
+		question.Options, question.Matches = nil, nil
+		if key.Number == nil || key.Tolerance < 0 {
+			return fmt.Errorf("%w: нужны число и неотрицательная погрешность", ErrInvalidQuestion)
+		}
+
+	case models.QuestionShortText:
+		question.Options, question.Matches = nil, nil
+		if len(key.Accepted) == 0 {
+			return fmt.Errorf("%w: нужен хотя бы один принимаемый ответ", ErrInvalidQuestion)
+		}
+		for _, accepted := range key.Accepted {
+			if NormalizeText(accepted, key.CaseSensitive) == "" {
+				return fmt.Errorf("%w: принимаемый ответ пуст", ErrInvalidQuestion)
+			}
+		}
+
+	case models.QuestionOrdering:
+		question.Matches = nil
+		if len(question.Options) < 2 {
+			return fmt.Errorf("%w: нужно не меньше двух элементов", ErrInvalidQuestion)
+		}
+		if !isPermutation(key.Order, len(question.Options)) {
+			return fmt.Errorf("%w: порядок должен содержать каждый элемент один раз", ErrInvalidQuestion)
+		}
+
+	case models.QuestionMatching:
+		if len(question.Options) < 2 || len(question.Matches) < 2 {
+			return fmt.Errorf("%w: в каждой колонке нужно не меньше двух вариантов", ErrInvalidQuestion)
+		}
+		if len(key.Pairs) != len(question.Options) {
+			return fmt.Errorf("%w: пара нужна для каждого варианта", ErrInvalidQuestion)
+		}
+		for _, pair := range key.Pairs {
+			if pair < 0 || pair >= len(question.Matches) {
+				return ErrCorrectAnswerOutOfRange
+			}
+		}
+
+	default:
+		return fmt.Errorf("%w: неизвестный тип %q", ErrInvalidQuestion, question.Type)
+	}
+
+	return nil
+}
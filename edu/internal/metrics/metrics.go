@@ -0,0 +1,47 @@
+// Package metrics содержит бизнес-метрики сервиса обучения.
+// Метрики HTTP, gRPC и базы данных собирает platform/metrics.
+package metrics
+
+import (
+	platform "platform/metrics"
+)
+
+const subsystem = "edu"
+
+// Значения метки result для тестов
+const (
+	ResultPassed = "passed"
+	ResultFailed = "failed"
+)
+
+// Значения метки result для уведомлений платежного провайдера
+const (
+	// PaymentEventApplied - уведомление изменило статус заказа
+	PaymentEventApplied = "applied"
+	// PaymentEventSkipped - повторное уведомление или недопустимый переход статуса
+	PaymentEventSkipped = "skipped"
+	// PaymentEventRejected - неверная подпись, неизвестная сессия или сумма
+	PaymentEventRejected = "rejected"
+)
+
+var (
+	// CoursePurchases - количество покупок курсов
+	CoursePurchases = platform.NewCounter(subsystem, "course_purchases_total",
+		"Количество покупок курсов.")
+
+	// LessonsViewed - количество первых просмотров уроков
+	LessonsViewed = platform.NewCounter(subsystem, "lessons_viewed_total",
+		"Количество первых просмотров уроков пользователями.")
+
+	// CoursesCompleted - количество завершенных курсов
+	CoursesCompleted = platform.NewCounter(subsystem, "courses_completed_total",
+		"Количество курсов, все уроки которых пройдены.")
+
+	// TestsSubmitted - количество отправленных тестов по результату
+	TestsSubmitted = platform.NewCounterVec(subsystem, "tests_submitted_total",
+		"Количество отправленных тестов по результату.", "result")
+
+	// PaymentEvents - количество уведомлений платежного провайдера по результату обработки
+	PaymentEvents = platform.NewCounterVec(subsystem, "payment_events_total",
+		"Количество уведомлений платежного провайдера по результату обработки.", "result")
+)
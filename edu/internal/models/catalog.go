@@ -0,0 +1,73 @@
+package models
+
+import (
+	"platform/pagination"
+
+	"github.com/google/uuid"
+)
+
+// CourseFilter - условия поиска по каталогу курсов. Пустые поля не ограничивают выборку.
+type CourseFilter struct {
+	// Query - полнотекстовый запрос по названию и описанию
+	Query       string
+	CategoryIDs []uuid.UUID
+	Levels      []string
+	MinPrice    *float64
+	MaxPrice    *float64
+	// FreeOnly - только бесплатные курсы
+	FreeOnly    bool
+	MinRating   *float64
+	MinDuration *int
+	MaxDuration *int
+}
+
+// CourseSort - порядок курсов в каталоге
+type CourseSort string
+
+const (
+	// CourseSortRelevance - по релевантности запросу, без запроса совпадает с CourseSortPopular
+	CourseSortRelevance CourseSort = "relevance"
+	CourseSortPopular   CourseSort = "popular"
+	CourseSortRating    CourseSort = "rating"
+	CourseSortPriceAsc  CourseSort = "price_asc"
+	CourseSortPriceDesc CourseSort = "price_desc"
+	CourseSortNewest    CourseSort = "newest"
+)
+
+// FacetCount - число курсов с значением Value
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int    `json:"count"`
+}
+
+// RangeCount - число курсов в диапазоне [Min, Max]. Без Max диапазон не ограничен сверху.
+type RangeCount struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int      `json:"count"`
+}
+
+// PriceFacet - распределение курсов по цене
+type PriceFacet struct {
+	Free int     `json:"free"`
+	Paid int     `json:"paid"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+}
+
+// CatalogFacets - счетчики для боковой панели фильтров.
+// Счетчик каждого фильтра учитывает все остальные выбранные фильтры, кроме него самого.
+type CatalogFacets struct {
+	Levels     []FacetCount `json:"levels"`
+	Categories []FacetCount `json:"categories"`
+	Price      PriceFacet   `json:"price"`
+	Ratings    []RangeCount `json:"ratings"`
+	Durations  []RangeCount `json:"durations"`
+}
+
+// CatalogPage - страница результатов поиска по каталогу. Facets заполняются только на первой странице.
+type CatalogPage struct {
+	pagination.Page[*Course]
+	Facets *CatalogFacets `json:"facets,omitempty"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CertificateRefundReason - причина отзыва сертификата при возврате оплаты курса
+const CertificateRefundReason = "Возврат оплаты курса"
+
+// CertificateAnonymousName - имя в сертификате студента, который не указал имя и фамилию.
+// Сертификат проверяется публично, поэтому email в него не попадает.
+const CertificateAnonymousName = "Слушатель курса"
+
+// Certificate - сертификат о прохождении курса. Имя студента и название курса
+// сохраняются на момент выдачи.
+type Certificate struct {
+	ID           uuid.UUID  `json:"id"`
+	Serial       string     `json:"serial"`
+	UserID       uuid.UUID  `json:"user_id"`
+	CourseID     uuid.UUID  `json:"course_id"`
+	StudentName  string     `json:"student_name"`
+	CourseTitle  string     `json:"course_title"`
+	CompletedAt  time.Time  `json:"completed_at"`
+	IssuedAt     time.Time  `json:"issued_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	RevokeReason string     `json:"revoke_reason,omitempty"`
+}
+
+// CertificateVerification - публичный результат проверки сертификата по серийному номеру
+type CertificateVerification struct {
+	Serial string `json:"serial"`
+	// Valid - сертификат выдан платформой и не отозван
+	Valid        bool       `json:"valid"`
+	StudentName  string     `json:"student_name"`
+	CourseTitle  string     `json:"course_title"`
+	CompletedAt  time.Time  `json:"completed_at"`
+	IssuedAt     time.Time  `json:"issued_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	RevokeReason string     `json:"revoke_reason,omitempty"`
+}
@@ -6,37 +6,102 @@ import (
 	"github.com/google/uuid"
 )
 
+// Статусы курса
+const (
+	// CourseStatusDraft - черновик, автор редактирует курс
+	CourseStatusDraft = "draft"
+	// CourseStatusPendingReview - курс отправлен на проверку
+	CourseStatusPendingReview = "pending_review"
+	// CourseStatusPublished - курс опубликован в каталоге
+	CourseStatusPublished = "published"
+	// CourseStatusRejected - курс отклонен, автор может исправить и отправить снова
+	CourseStatusRejected = "rejected"
+	// CourseStatusArchived - курс снят с публикации
+	CourseStatusArchived = "archived"
+)
+
+// Порядок прохождения уроков курса
+const (
+	// ProgressionFree - уроки открываются в любом порядке
+	ProgressionFree = "free"
+	// ProgressionSequential - урок открывается после прохождения всех уроков с меньшим order_num
+	ProgressionSequential = "sequential"
+	// ProgressionPrerequisites - урок открывается после прохождения его предварительных уроков
+	ProgressionPrerequisites = "prerequisites"
+)
+
+// Правило подсчета результата теста по нескольким попыткам
+const (
+	// ScoringBest - лучший результат
+	ScoringBest = "best"
+	// ScoringLast - результат последней попытки
+	ScoringLast = "last"
+	// ScoringAverage - среднее по всем попыткам, с округлением вниз
+	ScoringAverage = "average"
+)
+
+// Что делать с ответами, отправленными после истечения времени на попытку
+const (
+	// LateReject - ответы не принимаются, попытка засчитывается с результатом 0
+	LateReject = "reject"
+	// LateGrade - ответы проверяются как обычно, попытка отмечается как просроченная
+	LateGrade = "grade"
+)
+
+// Когда студенту показываются правильные ответы при разборе попыток
+const (
+	// ReviewNever - правильные ответы не показываются
+	ReviewNever = "never"
+	// ReviewAfterSubmit - правильные ответы показываются после каждой попытки
+	ReviewAfterSubmit = "after_submit"
+	// ReviewAfterPass - правильные ответы показываются после прохождения теста
+	ReviewAfterPass = "after_pass"
+)
+
 type Course struct {
-	ID            uuid.UUID `json:"id"`
-	Title         string    `json:"title"`
-	Description   string    `json:"description"`
-	CategoryID    uuid.UUID `json:"category_id"`
-	Level         string    `json:"level"`
-	Duration      int       `json:"duration"`
-	Rating        float64   `json:"rating"`
-	StudentsCount int       `json:"students_count"`
-	Thumbnail     string    `json:"thumbnail"`
-	Price         float64   `json:"price"`
-	Status        string    `json:"status"`
-	CreatedBy     uuid.UUID `json:"created_by"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            uuid.UUID  `json:"id"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	CategoryID    uuid.UUID  `json:"category_id"`
+	Level         string     `json:"level"`
+	Duration      int        `json:"duration"`
+	Rating        float64    `json:"rating"`
+	StudentsCount int        `json:"students_count"`
+	Thumbnail     string     `json:"thumbnail"`
+	Price         float64    `json:"price"`
+	Status        string     `json:"status"`
+	CreatedBy     uuid.UUID  `json:"created_by"`
+	ReviewerID    *uuid.UUID `json:"reviewer_id,omitempty"`
+	Progression   string     `json:"progression"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 type Test struct {
 	ID           uuid.UUID `json:"id"`
 	LessonID     uuid.UUID `json:"lesson_id"`
 	PassingScore int       `json:"passing_score"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	// QuestionCount - сколько вопросов из пула выдается в попытке, 0 - все вопросы
+	QuestionCount int `json:"question_count"`
+	// TagQuotas - сколько из выдаваемых вопросов взять с каждым тегом
+	TagQuotas        []TagQuota `json:"tag_quotas"`
+	ShuffleQuestions bool       `json:"shuffle_questions"`
+	ShuffleOptions   bool       `json:"shuffle_options"`
+	// MaxAttempts - сколько раз можно сдать тест, 0 - без ограничения
+	MaxAttempts int `json:"max_attempts"`
+	// CooldownSeconds - пауза между попытками; удваивается после каждой неудачной попытки подряд
+	CooldownSeconds int `json:"cooldown_seconds"`
+	// TimeLimitSeconds - время на попытку, 0 - без ограничения
+	TimeLimitSeconds int       `json:"time_limit_seconds"`
+	ScoringPolicy    string    `json:"scoring_policy"`
+	LateSubmission   string    `json:"late_submission"`
+	ReviewPolicy     string    `json:"review_policy"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
 
-type Question struct {
-	ID            uuid.UUID `json:"id"`
-	TestID        uuid.UUID `json:"test_id"`
-	QuestionText  string    `json:"question_text"`
-	Options       []string  `json:"options"`
-	CorrectAnswer int       `json:"correct_answer"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+// TagQuota - число вопросов с тегом Tag в попытке
+type TagQuota struct {
+	Tag   string `json:"tag" binding:"required,max=50" example:"основы"`
+	Count int    `json:"count" binding:"min=1" example:"3"`
 }
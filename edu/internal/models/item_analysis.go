@@ -0,0 +1,40 @@
+package models
+
+import "github.com/google/uuid"
+
+// ItemAnalysis - анализ вопросов теста по проверенным попыткам всех студентов
+type ItemAnalysis struct {
+	TestID uuid.UUID `json:"test_id"`
+	// Attempts - число проверенных попыток, по которым считался анализ: последние из TotalAttempts,
+	// но не больше 2000
+	Attempts      int                 `json:"attempts"`
+	TotalAttempts int                 `json:"total_attempts"`
+	Questions     []*QuestionAnalysis `json:"questions"`
+}
+
+// QuestionAnalysis - показатели вопроса. Показатели без ответов на вопрос не считаются.
+type QuestionAnalysis struct {
+	Question *Question `json:"question"`
+	// Responses - попытки, в которые попал вопрос; Unanswered - из них без ответа
+	Responses  int `json:"responses"`
+	Unanswered int `json:"unanswered"`
+	// Difficulty - индекс трудности: средняя доля баллов за вопрос, от 0 (никто не ответил верно)
+	// до 1 (все ответили верно)
+	Difficulty *float64 `json:"difficulty,omitempty" example:"0.72"`
+	// Discrimination - индекс различения: разница средней доли баллов в 27% лучших и 27% худших
+	// попыток, от -1 до 1. Отрицательное значение - вопрос чаще решают слабые студенты.
+	Discrimination *float64 `json:"discrimination,omitempty" example:"0.41"`
+	// Options - как часто выбирался каждый вариант, для вопросов с выбором ответа
+	Options []*OptionAnalysis `json:"options,omitempty"`
+}
+
+// OptionAnalysis - выбор варианта ответа. Неверный вариант, который почти не выбирают,
+// не работает как дистрактор.
+type OptionAnalysis struct {
+	Index   int    `json:"index"`
+	Text    string `json:"text"`
+	Correct bool   `json:"correct"`
+	Chosen  int    `json:"chosen"`
+	// Share - доля ответивших на вопрос, выбравших вариант
+	Share float64 `json:"share" example:"0.15"`
+}
@@ -6,6 +6,14 @@ import (
 	"github.com/google/uuid"
 )
 
+// Причины блокировки урока
+const (
+	// LockReasonPreviousLessons - не пройдены предыдущие уроки курса
+	LockReasonPreviousLessons = "previous_lessons_incomplete"
+	// LockReasonPrerequisites - не пройдены предварительные уроки
+	LockReasonPrerequisites = "prerequisites_incomplete"
+)
+
 type Lesson struct {
 	ID           uuid.UUID `json:"id"`
 	CourseID     uuid.UUID `json:"course_id"`
@@ -22,4 +30,11 @@ type Lesson struct {
 	PassedTest bool       `json:"passed_test,omitempty"`
 	ViewedAt   *time.Time `json:"viewed_at,omitempty"`
 	HasTest    bool       `json:"has_test,omitempty"`
+
+	// Prerequisites - уроки, которые нужно пройти до этого урока
+	Prerequisites []uuid.UUID `json:"prerequisites,omitempty"`
+	// Поля для отображения доступа: заблокированный урок нельзя открыть, пока не пройдены уроки из BlockedBy
+	Locked     bool        `json:"locked"`
+	LockReason string      `json:"lock_reason,omitempty"`
+	BlockedBy  []uuid.UUID `json:"blocked_by,omitempty"`
 }
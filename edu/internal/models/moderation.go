@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Действия модерации курса
+const (
+	ModerationActionSubmitted = "submitted"
+	ModerationActionAssigned  = "assigned"
+	ModerationActionApproved  = "approved"
+	ModerationActionRejected  = "rejected"
+	ModerationActionArchived  = "archived"
+)
+
+// ModerationEvent - запись истории модерации курса.
+// Reason видит автор курса, Note - внутренняя заметка проверяющих.
+type ModerationEvent struct {
+	ID         uuid.UUID  `json:"id"`
+	CourseID   uuid.UUID  `json:"course_id"`
+	ActorID    uuid.UUID  `json:"actor_id"`
+	Action     string     `json:"action"`
+	FromStatus string     `json:"from_status"`
+	ToStatus   string     `json:"to_status"`
+	ReviewerID *uuid.UUID `json:"reviewer_id,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+	Note       string     `json:"note,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Типы уведомлений
+const (
+	NotificationCourseApproved  = "course_approved"
+	NotificationCourseRejected  = "course_rejected"
+	NotificationCourseArchived  = "course_archived"
+	NotificationReviewHidden    = "review_hidden"
+	NotificationCourseCompleted = "course_completed"
+)
+
+type Notification struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Type      string     `json:"type"`
+	Title     string     `json:"title"`
+	Message   string     `json:"message,omitempty"`
+	CourseID  *uuid.UUID `json:"course_id,omitempty"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы заказа и платежа
+const (
+	// OrderCreated - заказ создан, платежной сессии еще нет
+	OrderCreated = "created"
+	// OrderPending - создана платежная сессия, ждем уведомления провайдера
+	OrderPending = "pending"
+	// OrderPaid - оплата подтверждена, курс выдан
+	OrderPaid = "paid"
+	// OrderFailed - оплата не прошла
+	OrderFailed = "failed"
+	// OrderRefunded - деньги возвращены, доступ к курсу отозван
+	OrderRefunded = "refunded"
+)
+
+// orderTransitions - допустимые переходы статусов заказа
+var orderTransitions = map[string][]string{
+	OrderCreated: {OrderPending, OrderPaid},
+	OrderPending: {OrderPaid, OrderFailed},
+	OrderFailed:  {OrderPaid},
+	OrderPaid:    {OrderRefunded},
+}
+
+// CanTransitionOrder сообщает, может ли заказ перейти из статуса from в статус to
+func CanTransitionOrder(from, to string) bool {
+	return slices.Contains(orderTransitions[from], to)
+}
+
+// OrderStatusesBefore возвращает статусы, из которых заказ может перейти в статус to
+func OrderStatusesBefore(to string) []string {
+	var statuses []string
+	for from, targets := range orderTransitions {
+		if slices.Contains(targets, to) {
+			statuses = append(statuses, from)
+		}
+	}
+	slices.Sort(statuses)
+	return statuses
+}
+
+// Order - заказ курса. Amount - в минимальных единицах валюты (копейках).
+type Order struct {
+	ID       uuid.UUID `json:"id"`
+	UserID   uuid.UUID `json:"user_id"`
+	CourseID uuid.UUID `json:"course_id"`
+	Amount   int64     `json:"amount" example:"149000"`
+	Currency string    `json:"currency" example:"RUB"`
+	Status   string    `json:"status" example:"pending"`
+	// CheckoutURL - страница оплаты последней платежной сессии, пока заказ ждет оплаты
+	CheckoutURL string    `json:"checkout_url,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Payment - платежная сессия заказа у провайдера
+type Payment struct {
+	ID                uuid.UUID `json:"id"`
+	OrderID           uuid.UUID `json:"order_id"`
+	Provider          string    `json:"provider"`
+	SessionID         string    `json:"session_id"`
+	CheckoutURL       string    `json:"checkout_url"`
+	ProviderPaymentID *string   `json:"provider_payment_id,omitempty"`
+	Status            string    `json:"status"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
@@ -24,3 +24,13 @@ type CourseProgress struct {
 	XPEarned         int        `json:"xp_earned"`
 	CompletedAt      *time.Time `json:"completed_at,omitempty"`
 }
+
+// CourseCompletion - событие завершения курса: пройден последний урок курса
+type CourseCompletion struct {
+	UserID      uuid.UUID `json:"user_id"`
+	CourseID    uuid.UUID `json:"course_id"`
+	CourseTitle string    `json:"course_title"`
+	CompletedAt time.Time `json:"completed_at"`
+	// XPAwarded - бонус XP за завершение курса
+	XPAwarded int `json:"xp_awarded"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PurchasedCourse - курс, купленный пользователем
+type PurchasedCourse struct {
+	CourseID    uuid.UUID `json:"course_id"`
+	PurchasedAt time.Time `json:"purchased_at"`
+	// CompletedAt - время прохождения всех уроков курса
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
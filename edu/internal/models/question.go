@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы вопросов теста
+const (
+	// QuestionSingleChoice - один правильный вариант из Options
+	QuestionSingleChoice = "single_choice"
+	// QuestionMultipleChoice - несколько правильных вариантов, засчитывается частично
+	QuestionMultipleChoice = "multiple_choice"
+	// QuestionTrueFalse - верно или неверно
+	QuestionTrueFalse = "true_false"
+	// QuestionNumeric - число с допустимой погрешностью
+	QuestionNumeric = "numeric"
+	// QuestionShortText - короткий текстовый ответ, сравнивается после нормализации
+	QuestionShortText = "short_text"
+	// QuestionOrdering - расположить Options в правильном порядке
+	QuestionOrdering = "ordering"
+	// QuestionMatching - сопоставить каждому варианту из Options вариант из Matches
+	QuestionMatching = "matching"
+)
+
+type Question struct {
+	ID           uuid.UUID `json:"id"`
+	TestID       uuid.UUID `json:"test_id"`
+	Type         string    `json:"type"`
+	QuestionText string    `json:"question_text"`
+	// Options - варианты ответа, элементы для упорядочивания или левая колонка сопоставления
+	Options []string `json:"options,omitempty"`
+	// Matches - правая колонка сопоставления
+	Matches []string `json:"matches,omitempty"`
+	// Points - баллы за полностью верный ответ
+	Points int `json:"points"`
+	// Explanation - пояснение, которое показывается после отправки ответов
+	Explanation string `json:"explanation,omitempty"`
+	// Tags - темы вопроса для выборки из пула
+	Tags []string `json:"tags,omitempty"`
+	// Key - правильный ответ, студентам не отдается
+	Key       *AnswerKey `json:"key,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// AnswerKey - правильный ответ на вопрос. Заполняются поля для типа вопроса.
+type AnswerKey struct {
+	// Choices - номера правильных вариантов, начиная с 0 (single_choice, multiple_choice)
+	Choices []int `json:"choices,omitempty"`
+	// Bool - правильный ответ true_false
+	Bool *bool `json:"bool,omitempty"`
+	// Number и Tolerance - правильное число и допустимое отклонение (numeric)
+	Number    *float64 `json:"number,omitempty"`
+	Tolerance float64  `json:"tolerance,omitempty"`
+	// Accepted - принимаемые варианты ответа (short_text)
+	Accepted      []string `json:"accepted,omitempty"`
+	CaseSensitive bool     `json:"case_sensitive,omitempty"`
+	// Order - номера Options в правильном порядке (ordering)
+	Order []int `json:"order,omitempty"`
+	// Pairs - Pairs[i] - номер варианта из Matches для Options[i] (matching)
+	Pairs []int `json:"pairs,omitempty"`
+}
+
+// Answer - ответ студента на вопрос. Поля соответствуют полям AnswerKey.
+type Answer struct {
+	Choices []int    `json:"choices,omitempty"`
+	Bool    *bool    `json:"bool,omitempty"`
+	Number  *float64 `json:"number,omitempty"`
+	Text    *string  `json:"text,omitempty"`
+	Order   []int    `json:"order,omitempty"`
+	Pairs   []int    `json:"pairs,omitempty"`
+}
+
+// PublicQuestion возвращает вопрос без правильного ответа и пояснения, для студента
+func (q *Question) PublicQuestion() *Question {
+	return &Question{
+		ID:           q.ID,
+		TestID:       q.TestID,
+		Type:         q.Type,
+		QuestionText: q.QuestionText,
+		Options:      q.Options,
+		Matches:      q.Matches,
+		Points:       q.Points,
+	}
+}
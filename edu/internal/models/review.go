@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewSort - порядок отзывов курса
+type ReviewSort string
+
+const (
+	// ReviewSortNewest - сначала новые
+	ReviewSortNewest ReviewSort = "newest"
+	// ReviewSortHelpful - сначала отмеченные полезными
+	ReviewSortHelpful ReviewSort = "helpful"
+)
+
+// Review - отзыв студента о курсе
+type Review struct {
+	ID           uuid.UUID `json:"id"`
+	CourseID     uuid.UUID `json:"course_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	UserName     string    `json:"user_name"`
+	Rating       int       `json:"rating"`
+	Text         string    `json:"text"`
+	HelpfulCount int       `json:"helpful_count"`
+	// ReportsCount - число жалоб, видно только администраторам
+	ReportsCount int        `json:"reports_count,omitempty"`
+	HiddenAt     *time.Time `json:"hidden_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ReviewReport - жалоба пользователя на отзыв
+type ReviewReport struct {
+	ReviewID  uuid.UUID `json:"review_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RatingSummary - рейтинг курса и распределение оценок по видимым отзывам
+type RatingSummary struct {
+	CourseID     uuid.UUID `json:"course_id"`
+	Rating       float64   `json:"rating"`
+	ReviewsCount int       `json:"reviews_count"`
+	// Histogram - число отзывов с оценкой от 1 до 5
+	Histogram map[int]int `json:"histogram"`
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TestAttempt - попытка прохождения теста. Вопросы попытки выбираются из пула при ее начале
+// и не меняются, даже если автор изменит тест.
+type TestAttempt struct {
+	ID        uuid.UUID      `json:"id"`
+	TestID    uuid.UUID      `json:"test_id"`
+	UserID    uuid.UUID      `json:"user_id"`
+	Items     []*AttemptItem `json:"-"`
+	StartedAt time.Time      `json:"started_at"`
+	// ExpiresAt - когда истекает время на попытку, nil - без ограничения
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+	Score       *int       `json:"score,omitempty"`
+	Points      *float64   `json:"points,omitempty"`
+	MaxPoints   *int       `json:"max_points,omitempty"`
+	// Late - попытка завершена после истечения времени
+	Late bool `json:"late"`
+	// Answers - ответы по вопросам попытки; у попытки, отклоненной по времени, ответов нет
+	Answers []*AttemptAnswer `json:"-"`
+}
+
+// AttemptAnswer - ответ на вопрос попытки. Answer - в исходном порядке вариантов вопроса,
+// nil - вопрос без ответа.
+type AttemptAnswer struct {
+	QuestionID uuid.UUID `json:"question_id"`
+	Position   int       `json:"position"`
+	Answer     *Answer   `json:"answer,omitempty"`
+	Points     float64   `json:"points"`
+	MaxPoints  int       `json:"max_points"`
+	Correct    bool      `json:"correct"`
+}
+
+// Duration возвращает время от начала до завершения попытки
+func (a *TestAttempt) Duration() time.Duration {
+	if a.SubmittedAt == nil {
+		return 0
+	}
+	return a.SubmittedAt.Sub(a.StartedAt)
+}
+
+// AttemptSummary - завершенная попытка в истории попыток студента
+type AttemptSummary struct {
+	*TestAttempt
+	DurationSeconds int `json:"duration_seconds"`
+}
+
+// AttemptReview - разбор завершенной попытки: вопросы в порядке попытки с ответами студента.
+// Правильные ответы есть, только если их разрешает показать review_policy теста.
+type AttemptReview struct {
+	Attempt         *AttemptSummary   `json:"attempt"`
+	AnswersRevealed bool              `json:"answers_revealed"`
+	Questions       []*ReviewQuestion `json:"questions"`
+}
+
+// ReviewQuestion - вопрос в разборе попытки. Номера вариантов в answer и в question.key -
+// в порядке, показанном в попытке.
+type ReviewQuestion struct {
+	Question  *Question `json:"question"`
+	Answer    *Answer   `json:"answer,omitempty"`
+	Points    float64   `json:"points"`
+	MaxPoints int       `json:"max_points"`
+	Correct   bool      `json:"correct"`
+}
+
+// AttemptStatus - сводка по попыткам студента с учетом ограничений теста
+type AttemptStatus struct {
+	// AttemptsUsed - начатые попытки, включая незавершенную
+	AttemptsUsed int `json:"attempts_used"`
+	// AttemptsLeft - сколько еще попыток можно начать, nil - без ограничения
+	AttemptsLeft *int `json:"attempts_left,omitempty"`
+	// NextAttemptAt - когда можно начать следующую попытку, если сейчас действует пауза
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	// Score - результат по правилу подсчета теста, nil - нет завершенных попыток
+	Score *int `json:"score,omitempty"`
+}
+
+// CanStart сообщает, можно ли начать новую попытку
+func (s *AttemptStatus) CanStart() bool {
+	return (s.AttemptsLeft == nil || *s.AttemptsLeft > 0) && s.NextAttemptAt == nil
+}
+
+// AttemptItem - вопрос попытки. Question - снимок вопроса с правильным ответом, в исходном порядке вариантов.
+// OptionOrder[i] - номер исходного варианта, показанного i-м; MatchOrder - то же для правой колонки
+// сопоставления. Пустая перестановка - исходный порядок.
+type AttemptItem struct {
+	Question    *Question `json:"question"`
+	OptionOrder []int     `json:"option_order,omitempty"`
+	MatchOrder  []int     `json:"match_order,omitempty"`
+}
+
+// Served возвращает вопрос в том виде, в каком его видит студент: без правильного ответа
+// и с вариантами в порядке попытки
+func (item *AttemptItem) Served() *Question {
+	served := item.Question.PublicQuestion()
+	served.Options = permute(item.Question.Options, item.OptionOrder)
+	served.Matches = permute(item.Question.Matches, item.MatchOrder)
+	return served
+}
+
+// Questions возвращает снимки вопросов попытки
+func (a *TestAttempt) Questions() []*Question {
+	questions := make([]*Question, len(a.Items))
+	for i, item := range a.Items {
+		questions[i] = item.Question
+	}
+	return questions
+}
+
+func permute(values []string, order []int) []string {
+	if order == nil {
+		return values
+	}
+	result := make([]string, len(order))
+	for i, index := range order {
+		result[i] = values[index]
+	}
+	return result
+}
@@ -1,13 +1,31 @@
 package models
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 // TestResponse представляет ответ с тестом и информацией о прогрессе
 type TestResponse struct {
 	// Основная информация о тесте
 	Test *Test `json:"test"`
 
-	// Вопросы теста (без правильных ответов)
+	// Вопросы текущей попытки (без правильных ответов), в порядке попытки
 	Questions []*Question `json:"questions"`
 
+	// Текущая попытка: ответы отправляются на ее вопросы. Нет, если новую попытку начать нельзя.
+	AttemptID *uuid.UUID `json:"attempt_id,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	// ExpiresAt - до какого момента принимаются ответы, если у теста есть лимит времени
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// Оставшиеся попытки, пауза до следующей попытки и результат по правилу подсчета теста
+	Attempts *AttemptStatus `json:"attempts"`
+
+	// Версия формата ответов, которую ожидает отправка теста
+	AnswerSchema int `json:"answer_schema"`
+
 	// Проходной балл
 	PassingScore int `json:"passing_score"`
 
@@ -16,3 +34,9 @@ type TestResponse struct {
 	Passed        bool `json:"passed"`
 	AttemptsCount int  `json:"attempts_count"`
 }
+
+// TestContent - тест урока вместе с правильными ответами, для кабинета автора
+type TestContent struct {
+	Test      *Test       `json:"test"`
+	Questions []*Question `json:"questions"`
+}
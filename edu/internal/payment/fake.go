@@ -0,0 +1,127 @@
+package payment
+
+import (
+	"context"
+	"course2/internal/models"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FakeName - имя провайдера для разработки
+const FakeName = "fake"
+
+// SignatureHeader - заголовок с подписью уведомления Fake: "t=<unix-время>,v1=<hex HMAC-SHA256>".
+// Подписывается строка "<unix-время>.<тело уведомления>".
+const SignatureHeader = "Fake-Signature"
+
+// signatureTolerance - насколько старым может быть подписанное уведомление
+const signatureTolerance = 5 * time.Minute
+
+// Fake - провайдер для разработки и тестов. Денег не списывает: страница оплаты - это адрес
+// checkoutURL с номером сессии, а уведомления создает Sign. Подпись проверяется так же,
+// как у настоящего провайдера.
+type Fake struct {
+	secret      []byte
+	checkoutURL string
+	now         func() time.Time
+}
+
+func NewFake(secret, checkoutURL string) *Fake {
+	return &Fake{
+		secret:      []byte(secret),
+		checkoutURL: checkoutURL,
+		now:         time.Now,
+	}
+}
+
+func (f *Fake) Name() string {
+	return FakeName
+}
+
+func (f *Fake) CreateSession(ctx context.Context, order *models.Order) (*Session, error) {
+	id := "cs_fake_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	return &Session{ID: id, URL: f.checkoutURL + id}, nil
+}
+
+func (f *Fake) ParseWebhook(header http.Header, body []byte) (*Event, error) {
+	timestamp, signature, ok := parseSignature(header.Get(SignatureHeader))
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+
+	signedAt := time.Unix(timestamp, 0)
+	if age := f.now().Sub(signedAt); age > signatureTolerance || age < -signatureTolerance {
+		return nil, ErrInvalidSignature
+	}
+	if !hmac.Equal(signature, f.mac(timestamp, body)) {
+		return nil, ErrInvalidSignature
+	}
+
+	event := &Event{}
+	if err := json.Unmarshal(body, event); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	if err := event.validate(); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Sign возвращает подписанное уведомление о событии, как его отправил бы провайдер.
+// Пустой event.ID заменяется новым идентификатором.
+func (f *Fake) Sign(event *Event) (http.Header, []byte, error) {
+	if event.ID == "" {
+		event.ID = "evt_fake_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timestamp := f.now().Unix()
+	header := http.Header{}
+	header.Set(SignatureHeader, fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(f.mac(timestamp, body))))
+	return header, body, nil
+}
+
+func (f *Fake) mac(timestamp int64, body []byte) []byte {
+	mac := hmac.New(sha256.New, f.secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// parseSignature разбирает заголовок "t=<unix-время>,v1=<hex>"
+func parseSignature(value string) (timestamp int64, signature []byte, ok bool) {
+	for _, part := range strings.Split(value, ",") {
+		key, val, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			return 0, nil, false
+		}
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				return 0, nil, false
+			}
+			timestamp = parsed
+		case "v1":
+			decoded, err := hex.DecodeString(val)
+			if err != nil {
+				return 0, nil, false
+			}
+			signature = decoded
+		}
+	}
+	return timestamp, signature, timestamp != 0 && signature != nil
+}
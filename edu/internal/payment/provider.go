@@ -0,0 +1,60 @@
+// Package payment описывает платежных провайдеров: создание платежной сессии и разбор
+// подписанных уведомлений о ее результате.
+package payment
+
+import (
+	"context"
+	"course2/internal/models"
+	"errors"
+	"net/http"
+)
+
+var (
+	// ErrInvalidSignature - подпись уведомления не сошлась или устарела
+	ErrInvalidSignature = errors.New("неверная подпись уведомления")
+	// ErrInvalidEvent - уведомление не удалось разобрать
+	ErrInvalidEvent = errors.New("неверное уведомление провайдера")
+)
+
+// Provider - платежный провайдер (PaymentProvider). Оплата идет на стороне провайдера,
+// результат приходит уведомлением (webhook), которое провайдер подписывает.
+type Provider interface {
+	// Name - имя провайдера в адресе уведомлений и в таблице payments
+	Name() string
+	// CreateSession создает платежную сессию заказа и возвращает адрес страницы оплаты
+	CreateSession(ctx context.Context, order *models.Order) (*Session, error)
+	// ParseWebhook проверяет подпись уведомления и возвращает событие.
+	// При неверной подписи возвращает ErrInvalidSignature.
+	ParseWebhook(header http.Header, body []byte) (*Event, error)
+}
+
+// Session - платежная сессия у провайдера
+type Session struct {
+	ID  string
+	URL string
+}
+
+// Event - уведомление провайдера о платеже
+type Event struct {
+	// ID - идентификатор события у провайдера, по нему повторная доставка не обрабатывается дважды
+	ID string `json:"id"`
+	// Type - новый статус платежа: models.OrderPaid, models.OrderFailed или models.OrderRefunded
+	Type      string `json:"type"`
+	SessionID string `json:"session_id"`
+	// PaymentID - идентификатор платежа у провайдера
+	PaymentID string `json:"payment_id,omitempty"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+}
+
+// validate проверяет обязательные поля события
+func (e *Event) validate() error {
+	if e.ID == "" || e.SessionID == "" {
+		return ErrInvalidEvent
+	}
+	switch e.Type {
+	case models.OrderPaid, models.OrderFailed, models.OrderRefunded:
+		return nil
+	}
+	return ErrInvalidEvent
+}
@@ -0,0 +1,355 @@
+package repositories
+
+import (
+	"context"
+	"course2/internal/models"
+	"database/sql"
+	"encoding/json"
+	"platform/pagination"
+
+	"github.com/google/uuid"
+)
+
+type AttemptRepository struct {
+	db *sql.DB
+}
+
+func NewAttemptRepository(db *sql.DB) *AttemptRepository {
+	return &AttemptRepository{db: db}
+}
+
+const attemptColumns = `id, test_id, user_id, items, started_at, expires_at, submitted_at, score, late`
+
+func scanAttempt(row scanner) (*models.TestAttempt, error) {
+	attempt := &models.TestAttempt{}
+	var itemsJSON []byte
+	err := row.Scan(
+		&attempt.ID, &attempt.TestID, &attempt.UserID, &itemsJSON,
+		&attempt.StartedAt, &attempt.ExpiresAt, &attempt.SubmittedAt, &attempt.Score, &attempt.Late,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(itemsJSON, &attempt.Items); err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// CreateOpen сохраняет новую попытку с временем на нее timeLimitSeconds (0 - без ограничения).
+// Возвращает false, если у пользователя уже есть незавершенная попытка этого теста.
+func (r *AttemptRepository) CreateOpen(ctx context.Context, attempt *models.TestAttempt, timeLimitSeconds int) (bool, error) {
+	query := `
+		INSERT INTO test_attempts (id, test_id, user_id, items, started_at, expires_at)
+		VALUES (
+			$1, $2, $3, $4, NOW(),
+			CASE WHEN $5 > 0 THEN NOW() + $5 * INTERVAL '1 second' END
+		)
+		ON CONFLICT (user_id, test_id) WHERE submitted_at IS NULL DO NOTHING
+		RETURNING started_at, expires_at
+	`
+
+	itemsJSON, err := json.Marshal(attempt.Items)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.db.QueryRowContext(ctx, query,
+		attempt.ID, attempt.TestID, attempt.UserID, itemsJSON, timeLimitSeconds,
+	).Scan(&attempt.StartedAt, &attempt.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetOpen возвращает незавершенную попытку пользователя или nil
+func (r *AttemptRepository) GetOpen(ctx context.Context, userID, testID uuid.UUID) (*models.TestAttempt, error) {
+	query := `
+		SELECT ` + attemptColumns + `
+		FROM test_attempts
+		WHERE user_id = $1 AND test_id = $2 AND submitted_at IS NULL
+	`
+
+	attempt, err := scanAttempt(r.db.QueryRowContext(ctx, query, userID, testID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+// Submit завершает попытку и сохраняет ее результат: Score, Points, MaxPoints, Late и Answers.
+// Возвращает false, если попытка уже завершена.
+func (r *AttemptRepository) Submit(ctx context.Context, attempt *models.TestAttempt) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE test_attempts
+		SET submitted_at = NOW(), score = $1, points = $2, max_points = $3, late = $4
+		WHERE id = $5 AND submitted_at IS NULL
+		RETURNING submitted_at
+	`, attempt.Score, attempt.Points, attempt.MaxPoints, attempt.Late, attempt.ID).Scan(&attempt.SubmittedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, answer := range attempt.Answers {
+		var answerJSON []byte
+		if answer.Answer != nil {
+			if answerJSON, err = json.Marshal(answer.Answer); err != nil {
+				return false, err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO test_attempt_answers (
+				attempt_id, question_id, position, answer, points, max_points, correct
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, attempt.ID, answer.QuestionID, answer.Position, answerJSON,
+			answer.Points, answer.MaxPoints, answer.Correct)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+const summaryColumns = `id, test_id, user_id, started_at, expires_at, submitted_at, score, points, max_points, late`
+
+func scanSummary(row scanner) (*models.TestAttempt, error) {
+	attempt := &models.TestAttempt{}
+	err := row.Scan(
+		&attempt.ID, &attempt.TestID, &attempt.UserID, &attempt.StartedAt, &attempt.ExpiresAt,
+		&attempt.SubmittedAt, &attempt.Score, &attempt.Points, &attempt.MaxPoints, &attempt.Late,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// ListSubmitted возвращает завершенные попытки пользователя в порядке завершения, без вопросов и ответов
+func (r *AttemptRepository) ListSubmitted(ctx context.Context, userID, testID uuid.UUID) ([]*models.TestAttempt, error) {
+	query := `
+		SELECT ` + summaryColumns + `
+		FROM test_attempts
+		WHERE user_id = $1 AND test_id = $2 AND submitted_at IS NOT NULL
+		ORDER BY submitted_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []*models.TestAttempt
+	for rows.Next() {
+		attempt, err := scanSummary(rows)
+		if err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, rows.Err()
+}
+
+// ListSubmittedPage возвращает страницу завершенных попыток пользователя, начиная с последней,
+// без вопросов и ответов
+func (r *AttemptRepository) ListSubmittedPage(ctx context.Context, userID, testID uuid.UUID, page pagination.Params) (*pagination.Page[*models.TestAttempt], error) {
+	const kind = "test-attempts"
+	afterSubmitted, afterID, err := afterTime(page, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + summaryColumns + `
+		FROM test_attempts
+		WHERE user_id = $1 AND test_id = $2 AND submitted_at IS NOT NULL
+		  AND ($3::timestamptz IS NULL OR (submitted_at, id) < ($3::timestamptz, $4::uuid))
+		ORDER BY submitted_at DESC, id DESC
+		LIMIT $5
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, testID, afterSubmitted, afterID, page.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []*models.TestAttempt
+	for rows.Next() {
+		attempt, err := scanSummary(rows)
+		if err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, attempt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := pagination.NewPage(attempts, page, kind, func(attempt *models.TestAttempt) any {
+		return timeKey{Time: *attempt.SubmittedAt, ID: attempt.ID}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.TotalEstimate, err = pagination.EstimateTotal(ctx, r.db,
+		"FROM test_attempts WHERE user_id = $1 AND test_id = $2 AND submitted_at IS NOT NULL", userID, testID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetSubmitted возвращает завершенную попытку с вопросами и ответами или nil
+func (r *AttemptRepository) GetSubmitted(ctx context.Context, id uuid.UUID) (*models.TestAttempt, error) {
+	query := `
+		SELECT ` + attemptColumns + `, points, max_points
+		FROM test_attempts
+		WHERE id = $1 AND submitted_at IS NOT NULL
+	`
+
+	attempt := &models.TestAttempt{}
+	var itemsJSON []byte
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&attempt.ID, &attempt.TestID, &attempt.UserID, &itemsJSON, &attempt.StartedAt,
+		&attempt.ExpiresAt, &attempt.SubmittedAt, &attempt.Score, &attempt.Late,
+		&attempt.Points, &attempt.MaxPoints,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(itemsJSON, &attempt.Items); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT question_id, position, answer, points, max_points, correct
+		FROM test_attempt_answers
+		WHERE attempt_id = $1
+		ORDER BY position
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		answer, err := scanAttemptAnswer(rows)
+		if err != nil {
+			return nil, err
+		}
+		attempt.Answers = append(attempt.Answers, answer)
+	}
+
+	return attempt, rows.Err()
+}
+
+// ListGraded возвращает не больше limit последних проверенных попыток теста всех студентов
+// с ответами, для анализа вопросов, и общее число проверенных попыток. Попытки, отклоненные
+// по времени, не имеют ответов и не учитываются.
+func (r *AttemptRepository) ListGraded(ctx context.Context, testID uuid.UUID, limit int) ([]*models.TestAttempt, int, error) {
+	query := `
+		WITH recent AS (
+			SELECT t.id, t.score, t.submitted_at, COUNT(*) OVER () AS total
+			FROM test_attempts t
+			WHERE t.test_id = $1 AND t.submitted_at IS NOT NULL
+			  AND EXISTS (SELECT 1 FROM test_attempt_answers a WHERE a.attempt_id = t.id)
+			ORDER BY t.submitted_at DESC, t.id DESC
+			LIMIT $2
+		)
+		SELECT r.total, r.id, r.score, a.question_id, a.position, a.answer, a.points, a.max_points, a.correct
+		FROM recent r
+		JOIN test_attempt_answers a ON a.attempt_id = r.id
+		ORDER BY r.submitted_at, r.id, a.position
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, testID, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var attempts []*models.TestAttempt
+	total := 0
+	for rows.Next() {
+		var id uuid.UUID
+		var score *int
+		row := &attemptAnswerRow{}
+		if err := rows.Scan(append([]any{&total, &id, &score}, row.fields()...)...); err != nil {
+			return nil, 0, err
+		}
+		answer, err := row.answer()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if len(attempts) == 0 || attempts[len(attempts)-1].ID != id {
+			attempts = append(attempts, &models.TestAttempt{ID: id, TestID: testID, Score: score})
+		}
+		last := attempts[len(attempts)-1]
+		last.Answers = append(last.Answers, answer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return attempts, total, nil
+}
+
+// attemptAnswerRow - строка test_attempt_answers до разбора JSON ответа
+type attemptAnswerRow struct {
+	models.AttemptAnswer
+	answerJSON []byte
+}
+
+func (row *attemptAnswerRow) fields() []any {
+	return []any{
+		&row.QuestionID, &row.Position, &row.answerJSON,
+		&row.Points, &row.MaxPoints, &row.Correct,
+	}
+}
+
+func (row *attemptAnswerRow) answer() (*models.AttemptAnswer, error) {
+	answer := row.AttemptAnswer
+	if row.answerJSON != nil {
+		answer.Answer = &models.Answer{}
+		if err := json.Unmarshal(row.answerJSON, answer.Answer); err != nil {
+			return nil, err
+		}
+	}
+	return &answer, nil
+}
+
+func scanAttemptAnswer(row scanner) (*models.AttemptAnswer, error) {
+	answerRow := &attemptAnswerRow{}
+	if err := row.Scan(answerRow.fields()...); err != nil {
+		return nil, err
+	}
+	return answerRow.answer()
+}
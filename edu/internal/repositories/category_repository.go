@@ -4,6 +4,7 @@ import (
 	"context"
 	"course2/internal/models"
 	"database/sql"
+	"platform/pagination"
 )
 
 type CategoryRepository struct {
@@ -16,14 +17,23 @@ func NewCategoryRepository(db *sql.DB) *CategoryRepository {
 	}
 }
 
-func (r *CategoryRepository) List(ctx context.Context) ([]*models.Category, error) {
+// List возвращает страницу категорий по алфавиту
+func (r *CategoryRepository) List(ctx context.Context, page pagination.Params) (*pagination.Page[*models.Category], error) {
+	const kind = "categories"
+	afterCategory, afterID, err := afterName(page, kind)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT id, name, description, created_at, updated_at
 		FROM categories
-		ORDER BY name
+		WHERE $1::text IS NULL OR (name, id) > ($1::text, $2::uuid)
+		ORDER BY name, id
+		LIMIT $3
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, afterCategory, afterID, page.Limit+1)
 	if err != nil {
 		return nil, err
 	}
@@ -49,5 +59,16 @@ func (r *CategoryRepository) List(ctx context.Context) ([]*models.Category, erro
 		return nil, err
 	}
 
-	return categories, nil
+	result, err := pagination.NewPage(categories, page, kind, func(category *models.Category) any {
+		return nameKey{Name: category.Name, ID: category.ID}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.TotalEstimate, err = pagination.EstimateTotal(ctx, r.db, "FROM categories")
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"course2/internal/models"
+	"database/sql"
+	"platform/pagination"
+
+	"github.com/google/uuid"
+)
+
+type CertificateRepository struct {
+	db *sql.DB
+}
+
+func NewCertificateRepository(db *sql.DB) *CertificateRepository {
+	return &CertificateRepository{db: db}
+}
+
+const certificateColumns = `
+	id, serial, user_id, course_id, student_name, course_title,
+	completed_at, issued_at, revoked_at, revoke_reason
+`
+
+func scanCertificate(row scanner) (*models.Certificate, error) {
+	certificate := &models.Certificate{}
+	err := row.Scan(
+		&certificate.ID, &certificate.Serial, &certificate.UserID, &certificate.CourseID,
+		&certificate.StudentName, &certificate.CourseTitle, &certificate.CompletedAt,
+		&certificate.IssuedAt, &certificate.RevokedAt, &certificate.RevokeReason,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return certificate, nil
+}
+
+// Create сохраняет сертификат. Возвращает false, если у пользователя уже есть сертификат по этому курсу.
+func (r *CertificateRepository) Create(ctx context.Context, certificate *models.Certificate) (bool, error) {
+	query := `
+		INSERT INTO certificates (
+			id, serial, user_id, course_id, student_name, course_title,
+			completed_at, issued_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, NOW()
+		)
+		ON CONFLICT (user_id, course_id) DO NOTHING
+		RETURNING issued_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		certificate.ID, certificate.Serial, certificate.UserID, certificate.CourseID,
+		certificate.StudentName, certificate.CourseTitle, certificate.CompletedAt,
+	).Scan(&certificate.IssuedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *CertificateRepository) GetBySerial(ctx context.Context, serial string) (*models.Certificate, error) {
+	query := `SELECT ` + certificateColumns + ` FROM certificates WHERE serial = $1`
+
+	certificate, err := scanCertificate(r.db.QueryRowContext(ctx, query, serial))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return certificate, nil
+}
+
+func (r *CertificateRepository) GetByUserCourse(ctx context.Context, userID, courseID uuid.UUID) (*models.Certificate, error) {
+	query := `SELECT ` + certificateColumns + ` FROM certificates WHERE user_id = $1 AND course_id = $2`
+
+	certificate, err := scanCertificate(r.db.QueryRowContext(ctx, query, userID, courseID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return certificate, nil
+}
+
+// ListByUser возвращает сертификаты пользователя, сначала новые
+func (r *CertificateRepository) ListByUser(ctx context.Context, userID uuid.UUID, page pagination.Params) (*pagination.Page[*models.Certificate], error) {
+	const kind = "certificates"
+	afterIssued, afterID, err := afterTime(page, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + certificateColumns + `
+		FROM certificates
+		WHERE user_id = $1
+		  AND ($2::timestamptz IS NULL OR (issued_at, id) < ($2::timestamptz, $3::uuid))
+		ORDER BY issued_at DESC, id DESC
+		LIMIT $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, afterIssued, afterID, page.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var certificates []*models.Certificate
+	for rows.Next() {
+		certificate, err := scanCertificate(rows)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := pagination.NewPage(certificates, page, kind, func(certificate *models.Certificate) any {
+		return timeKey{Time: certificate.IssuedAt, ID: certificate.ID}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.TotalEstimate, err = pagination.EstimateTotal(ctx, r.db, "FROM certificates WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Revoke отзывает сертификат. Возвращает false, если сертификат уже отозван.
+func (r *CertificateRepository) Revoke(ctx context.Context, certificate *models.Certificate, adminID uuid.UUID, reason string) (bool, error) {
+	query := `
+		UPDATE certificates
+		SET revoked_at = NOW(), revoked_by = $1, revoke_reason = $2
+		WHERE id = $3 AND revoked_at IS NULL
+		RETURNING revoked_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, adminID, reason, certificate.ID).Scan(&certificate.RevokedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	certificate.RevokeReason = reason
+	return true, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"course2/internal/models"
 	"database/sql"
+	"platform/pagination"
 
 	"github.com/google/uuid"
 )
@@ -21,9 +22,9 @@ func (r *CourseRepository) Create(ctx context.Context, course *models.Course) er
 		INSERT INTO courses (
 			id, title, description, category_id, level, duration,
 			rating, students_count, thumbnail, price, status, created_by,
-			created_at, updated_at
+			progression, created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW()
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW()
 		)
 	`
 
@@ -31,6 +32,7 @@ func (r *CourseRepository) Create(ctx context.Context, course *models.Course) er
 		course.ID, course.Title, course.Description, course.CategoryID,
 		course.Level, course.Duration, course.Rating, course.StudentsCount,
 		course.Thumbnail, course.Price, course.Status, course.CreatedBy,
+		course.Progression,
 	)
 
 	return err
@@ -40,7 +42,7 @@ func (r *CourseRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.C
 	query := `
 		SELECT id, title, description, category_id, level, duration,
 			   rating, students_count, thumbnail, price, status, created_by,
-			   created_at, updated_at
+			   reviewer_id, progression, created_at, updated_at
 		FROM courses
 		WHERE id = $1
 	`
@@ -50,7 +52,7 @@ func (r *CourseRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.C
 		&course.ID, &course.Title, &course.Description, &course.CategoryID,
 		&course.Level, &course.Duration, &course.Rating, &course.StudentsCount,
 		&course.Thumbnail, &course.Price, &course.Status, &course.CreatedBy,
-		&course.CreatedAt, &course.UpdatedAt,
+		&course.ReviewerID, &course.Progression, &course.CreatedAt, &course.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -63,22 +65,103 @@ func (r *CourseRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.C
 	return course, nil
 }
 
-func (r *CourseRepository) List(ctx context.Context, offset, limit int) ([]*models.Course, error) {
+// ListByAuthor возвращает курсы автора во всех статусах, сначала новые
+func (r *CourseRepository) ListByAuthor(ctx context.Context, authorID uuid.UUID, page pagination.Params) (*pagination.Page[*models.Course], error) {
+	const kind = "author-courses"
+	afterCreated, afterID, err := afterTime(page, kind)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		SELECT id, title, description, category_id, level, duration,
 			   rating, students_count, thumbnail, price, status, created_by,
-			   created_at, updated_at
+			   reviewer_id, progression, created_at, updated_at
 		FROM courses
-		ORDER BY created_at DESC
-		OFFSET $1 LIMIT $2
+		WHERE created_by = $1
+		  AND ($2::timestamptz IS NULL OR (created_at, id) < ($2::timestamptz, $3::uuid))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, offset, limit)
+	rows, err := r.db.QueryContext(ctx, query, authorID, afterCreated, afterID, page.Limit+1)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	courses, err := scanCourses(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := pagination.NewPage(courses, page, kind, func(course *models.Course) any {
+		return timeKey{Time: course.CreatedAt, ID: course.ID}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.TotalEstimate, err = pagination.EstimateTotal(ctx, r.db, "FROM courses WHERE created_by = $1", authorID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListForReview возвращает очередь модерации: курсы в статусе status, сначала давно ожидающие.
+// reviewerID отбирает курсы проверяющего, unassigned - курсы без проверяющего.
+func (r *CourseRepository) ListForReview(ctx context.Context, status string, reviewerID *uuid.UUID, unassigned bool, page pagination.Params) (*pagination.Page[*models.Course], error) {
+	const kind = "review-queue"
+	afterUpdated, afterID, err := afterTime(page, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	const from = `
+		FROM courses
+		WHERE status = $1
+		  AND ($2::uuid IS NULL OR reviewer_id = $2)
+		  AND (NOT $3 OR reviewer_id IS NULL)
+	`
+
+	query := `
+		SELECT id, title, description, category_id, level, duration,
+			   rating, students_count, thumbnail, price, status, created_by,
+			   reviewer_id, progression, created_at, updated_at
+		` + from + `
+		  AND ($4::timestamptz IS NULL OR (updated_at, id) > ($4::timestamptz, $5::uuid))
+		ORDER BY updated_at, id
+		LIMIT $6
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, status, reviewerID, unassigned, afterUpdated, afterID, page.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	courses, err := scanCourses(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := pagination.NewPage(courses, page, kind, func(course *models.Course) any {
+		return timeKey{Time: course.UpdatedAt, ID: course.ID}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.TotalEstimate, err = pagination.EstimateTotal(ctx, r.db, from, status, reviewerID, unassigned)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// scanCourses читает курсы из результата запроса
+func scanCourses(rows *sql.Rows) ([]*models.Course, error) {
 	var courses []*models.Course
 	for rows.Next() {
 		course := &models.Course{}
@@ -86,7 +169,7 @@ func (r *CourseRepository) List(ctx context.Context, offset, limit int) ([]*mode
 			&course.ID, &course.Title, &course.Description, &course.CategoryID,
 			&course.Level, &course.Duration, &course.Rating, &course.StudentsCount,
 			&course.Thumbnail, &course.Price, &course.Status, &course.CreatedBy,
-			&course.CreatedAt, &course.UpdatedAt,
+			&course.ReviewerID, &course.Progression, &course.CreatedAt, &course.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -94,22 +177,24 @@ func (r *CourseRepository) List(ctx context.Context, offset, limit int) ([]*mode
 		courses = append(courses, course)
 	}
 
-	return courses, nil
+	return courses, rows.Err()
 }
 
+// Update изменяет данные курса. Статус и проверяющий меняются только
+// через ModerationRepository.ChangeStatus.
 func (r *CourseRepository) Update(ctx context.Context, course *models.Course) error {
 	query := `
 		UPDATE courses
 		SET title = $1, description = $2, category_id = $3, level = $4,
 			duration = $5, rating = $6, students_count = $7, thumbnail = $8,
-			price = $9, status = $10, updated_at = NOW()
+			price = $9, progression = $10, updated_at = NOW()
 		WHERE id = $11
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
 		course.Title, course.Description, course.CategoryID, course.Level,
 		course.Duration, course.Rating, course.StudentsCount, course.Thumbnail,
-		course.Price, course.Status, course.ID,
+		course.Price, course.Progression, course.ID,
 	)
 	if err != nil {
 		return err
@@ -146,38 +231,3 @@ func (r *CourseRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 	return nil
 }
-
-func (r *CourseRepository) ListByCategory(ctx context.Context, categoryID uuid.UUID, offset, limit int) ([]*models.Course, error) {
-	query := `
-		SELECT id, title, description, category_id, level, duration,
-			   rating, students_count, thumbnail, price, status, created_by,
-			   created_at, updated_at
-		FROM courses
-		WHERE category_id = $1
-		ORDER BY created_at DESC
-		OFFSET $2 LIMIT $3
-	`
-
-	rows, err := r.db.QueryContext(ctx, query, categoryID, offset, limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var courses []*models.Course
-	for rows.Next() {
-		course := &models.Course{}
-		err := rows.Scan(
-			&course.ID, &course.Title, &course.Description, &course.CategoryID,
-			&course.Level, &course.Duration, &course.Rating, &course.StudentsCount,
-			&course.Thumbnail, &course.Price, &course.Status, &course.CreatedBy,
-			&course.CreatedAt, &course.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		courses = append(courses, course)
-	}
-
-	return courses, nil
-}
@@ -0,0 +1,403 @@
+package repositories
+
+import (
+	"context"
+	"course2/internal/models"
+	"platform/pagination"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Фильтры, которые не применяются при подсчете собственного фасета
+const (
+	facetNone     = ""
+	facetLevel    = "level"
+	facetCategory = "category"
+	facetPrice    = "price"
+	facetRating   = "rating"
+	facetDuration = "duration"
+)
+
+// Пороги фасета рейтинга: "от 4.5", "от 4" и т.д.
+var ratingThresholds = []float64{4.5, 4, 3.5, 3}
+
+// Диапазоны фасета длительности, границы включаются
+var durationRanges = []struct{ min, max int }{
+	{0, 60},
+	{61, 180},
+	{181, 600},
+	{601, -1},
+}
+
+// searchQuery - условия WHERE и их параметры
+type searchQuery struct {
+	conditions []string
+	args       []any
+}
+
+// arg добавляет параметр запроса и возвращает его плейсхолдер
+func (q *searchQuery) arg(value any) string {
+	q.args = append(q.args, value)
+	return "$" + strconv.Itoa(len(q.args))
+}
+
+func (q *searchQuery) where(condition string) {
+	q.conditions = append(q.conditions, condition)
+}
+
+func (q *searchQuery) whereSQL() string {
+	return "WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// tsQuery - запрос полнотекстового поиска с русской и английской морфологией
+func tsQuery(param string) string {
+	return "(websearch_to_tsquery('russian', " + param + ") || websearch_to_tsquery('english', " + param + "))"
+}
+
+// newSearchQuery собирает условия поиска по каталогу. Условие фасета skip не применяется.
+func newSearchQuery(status string, filter *models.CourseFilter, skip string) *searchQuery {
+	q := &searchQuery{}
+	q.where("c.status = " + q.arg(status))
+
+	if filter.Query != "" {
+		q.where("c.search_vector @@ " + tsQuery(q.arg(filter.Query)))
+	}
+	if skip != facetCategory && len(filter.CategoryIDs) > 0 {
+		ids := make([]string, len(filter.CategoryIDs))
+		for i, id := range filter.CategoryIDs {
+			ids[i] = id.String()
+		}
+		q.where("c.category_id = ANY(" + q.arg(pq.Array(ids)) + "::uuid[])")
+	}
+	if skip != facetLevel && len(filter.Levels) > 0 {
+		q.where("c.level = ANY(" + q.arg(pq.Array(filter.Levels)) + ")")
+	}
+	if skip != facetPrice {
+		if filter.FreeOnly {
+			q.where("COALESCE(c.price, 0) = 0")
+		}
+		if filter.MinPrice != nil {
+			q.where("COALESCE(c.price, 0) >= " + q.arg(*filter.MinPrice))
+		}
+		if filter.MaxPrice != nil {
+			q.where("COALESCE(c.price, 0) <= " + q.arg(*filter.MaxPrice))
+		}
+	}
+	if skip != facetRating && filter.MinRating != nil {
+		q.where("COALESCE(c.rating, 0) >= " + q.arg(*filter.MinRating))
+	}
+	if skip != facetDuration {
+		if filter.MinDuration != nil {
+			q.where("COALESCE(c.duration, 0) >= " + q.arg(*filter.MinDuration))
+		}
+		if filter.MaxDuration != nil {
+			q.where("COALESCE(c.duration, 0) <= " + q.arg(*filter.MaxDuration))
+		}
+	}
+
+	return q
+}
+
+// Типы ключей сортировки каталога в Postgres
+const (
+	sortInteger   = "integer"
+	sortTimestamp = "timestamptz"
+	sortFloat     = "float8"
+	sortNumeric   = "numeric"
+)
+
+// sortKey - выражение сортировки каталога, его тип в Postgres и направление
+type sortKey struct {
+	expr string
+	typ  string
+	desc bool
+}
+
+// newValue возвращает переменную для значения ключа сортировки последнего курса страницы
+func (k sortKey) newValue() any {
+	switch k.typ {
+	case sortInteger:
+		return new(int)
+	case sortTimestamp:
+		return new(time.Time)
+	}
+	return new(float64)
+}
+
+// pageKey возвращает ключ курсора по значению из newValue и id курса
+func (k sortKey) pageKey(value any, id uuid.UUID) any {
+	switch v := value.(type) {
+	case *int:
+		return countKey{Count: *v, ID: id}
+	case *time.Time:
+		return timeKey{Time: *v, ID: id}
+	case *float64:
+		return numberKey{Number: *v, ID: id}
+	}
+	return nil
+}
+
+// after разбирает курсор каталога в ключ типа сортировки. Значение проверяется при разборе,
+// поэтому измененный клиентом курсор дает INVALID_CURSOR, а не ошибку приведения типа в Postgres.
+// Для первой страницы возвращает nil id.
+func (k sortKey) after(page pagination.Params, kind string) (any, *uuid.UUID, error) {
+	switch k.typ {
+	case sortInteger:
+		count, id, err := afterCount(page, kind)
+		return count, id, err
+	case sortTimestamp:
+		t, id, err := afterTime(page, kind)
+		return t, id, err
+	}
+	number, id, err := afterNumber(page, kind)
+	return number, id, err
+}
+
+// catalogSort возвращает ключ сортировки каталога. Выражения совпадают с индексами
+// из миграции 000014, вторым ключом всегда идет id в том же направлении.
+func catalogSort(sort models.CourseSort, q *searchQuery, filter *models.CourseFilter) sortKey {
+	switch sort {
+	case models.CourseSortRating:
+		return sortKey{expr: "COALESCE(c.rating, 0)", typ: sortFloat, desc: true}
+	case models.CourseSortPriceAsc:
+		// Цена - DECIMAL(10, 2), поэтому значение точно переживает float64 в курсоре
+		return sortKey{expr: "COALESCE(c.price, 0)", typ: sortNumeric}
+	case models.CourseSortPriceDesc:
+		return sortKey{expr: "COALESCE(c.price, 0)", typ: sortNumeric, desc: true}
+	case models.CourseSortNewest:
+		return sortKey{expr: "c.created_at", typ: sortTimestamp, desc: true}
+	case models.CourseSortRelevance:
+		if filter.Query != "" {
+			// ts_rank возвращает real; сравнение в float8 точное и не переполняется на значении из курсора
+			return sortKey{expr: "ts_rank(c.search_vector, " + tsQuery(q.arg(filter.Query)) + ")", typ: sortFloat, desc: true}
+		}
+	}
+	return sortKey{expr: "COALESCE(c.students_count, 0)", typ: sortInteger, desc: true}
+}
+
+// Search возвращает страницу каталога: курсы в статусе status, подходящие под filter
+func (r *CourseRepository) Search(ctx context.Context, status string, filter *models.CourseFilter, sort models.CourseSort, page pagination.Params) (*pagination.Page[*models.Course], error) {
+	q := newSearchQuery(status, filter, facetNone)
+	from := "FROM courses c " + q.whereSQL()
+	totalArgs := slices.Clone(q.args)
+
+	if sort == models.CourseSortRelevance && filter.Query == "" {
+		sort = models.CourseSortPopular
+	}
+	kind := "courses:" + string(sort)
+	key := catalogSort(sort, q, filter)
+
+	direction, compare := "", ">"
+	if key.desc {
+		direction, compare = " DESC", "<"
+	}
+
+	afterValue, afterID, err := key.after(page, kind)
+	if err != nil {
+		return nil, err
+	}
+	if afterID != nil {
+		q.where("(" + key.expr + ", c.id) " + compare + " (" + q.arg(afterValue) + "::" + key.typ + ", " + q.arg(*afterID) + ")")
+	}
+
+	query := `
+		SELECT c.id, c.title, c.description, c.category_id, c.level, c.duration,
+			   c.rating, c.students_count, c.thumbnail, c.price, c.status, c.created_by,
+			   c.reviewer_id, c.progression, c.created_at, c.updated_at, (` + key.expr + `)::` + key.typ + `
+		FROM courses c
+		` + q.whereSQL() + `
+		ORDER BY ` + key.expr + direction + `, c.id` + direction + `
+		LIMIT ` + q.arg(page.Limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var courses []*models.Course
+	keys := make(map[*models.Course]any)
+	for rows.Next() {
+		course := &models.Course{}
+		value := key.newValue()
+		err := rows.Scan(
+			&course.ID, &course.Title, &course.Description, &course.CategoryID,
+			&course.Level, &course.Duration, &course.Rating, &course.StudentsCount,
+			&course.Thumbnail, &course.Price, &course.Status, &course.CreatedBy,
+			&course.ReviewerID, &course.Progression, &course.CreatedAt, &course.UpdatedAt, value,
+		)
+		if err != nil {
+			return nil, err
+		}
+		keys[course] = value
+		courses = append(courses, course)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := pagination.NewPage(courses, page, kind, func(course *models.Course) any {
+		return key.pageKey(keys[course], course.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.TotalEstimate, err = pagination.EstimateTotal(ctx, r.db, from, totalArgs...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Facets считает фасеты каталога. Для каждого фасета применяются все фильтры, кроме его собственного,
+// чтобы в боковой панели было видно, сколько курсов добавит выбор еще одного значения.
+func (r *CourseRepository) Facets(ctx context.Context, status string, filter *models.CourseFilter) (*models.CatalogFacets, error) {
+	facets := &models.CatalogFacets{}
+	var err error
+
+	if facets.Levels, err = r.levelFacet(ctx, status, filter); err != nil {
+		return nil, err
+	}
+	if facets.Categories, err = r.categoryFacet(ctx, status, filter); err != nil {
+		return nil, err
+	}
+	if facets.Price, err = r.priceFacet(ctx, status, filter); err != nil {
+		return nil, err
+	}
+	if facets.Ratings, err = r.ratingFacet(ctx, status, filter); err != nil {
+		return nil, err
+	}
+	if facets.Durations, err = r.durationFacet(ctx, status, filter); err != nil {
+		return nil, err
+	}
+
+	return facets, nil
+}
+
+func (r *CourseRepository) levelFacet(ctx context.Context, status string, filter *models.CourseFilter) ([]models.FacetCount, error) {
+	q := newSearchQuery(status, filter, facetLevel)
+	q.where("COALESCE(c.level, '') <> ''")
+
+	query := `
+		SELECT c.level, COUNT(*)
+		FROM courses c
+		` + q.whereSQL() + `
+		GROUP BY c.level
+		ORDER BY COUNT(*) DESC, c.level
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	levels := []models.FacetCount{}
+	for rows.Next() {
+		var facet models.FacetCount
+		if err := rows.Scan(&facet.Value, &facet.Count); err != nil {
+			return nil, err
+		}
+		levels = append(levels, facet)
+	}
+
+	return levels, rows.Err()
+}
+
+func (r *CourseRepository) categoryFacet(ctx context.Context, status string, filter *models.CourseFilter) ([]models.FacetCount, error) {
+	q := newSearchQuery(status, filter, facetCategory)
+
+	query := `
+		SELECT cat.id, cat.name, COUNT(*)
+		FROM courses c
+		JOIN categories cat ON cat.id = c.category_id
+		` + q.whereSQL() + `
+		GROUP BY cat.id, cat.name
+		ORDER BY COUNT(*) DESC, cat.name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []models.FacetCount{}
+	for rows.Next() {
+		var facet models.FacetCount
+		if err := rows.Scan(&facet.Value, &facet.Label, &facet.Count); err != nil {
+			return nil, err
+		}
+		categories = append(categories, facet)
+	}
+
+	return categories, rows.Err()
+}
+
+func (r *CourseRepository) priceFacet(ctx context.Context, status string, filter *models.CourseFilter) (models.PriceFacet, error) {
+	q := newSearchQuery(status, filter, facetPrice)
+
+	query := `
+		SELECT COUNT(*) FILTER (WHERE COALESCE(c.price, 0) = 0),
+			   COUNT(*) FILTER (WHERE COALESCE(c.price, 0) > 0),
+			   COALESCE(MIN(c.price), 0), COALESCE(MAX(c.price), 0)
+		FROM courses c
+		` + q.whereSQL()
+
+	var price models.PriceFacet
+	err := r.db.QueryRowContext(ctx, query, q.args...).Scan(&price.Free, &price.Paid, &price.Min, &price.Max)
+	return price, err
+}
+
+func (r *CourseRepository) ratingFacet(ctx context.Context, status string, filter *models.CourseFilter) ([]models.RangeCount, error) {
+	q := newSearchQuery(status, filter, facetRating)
+
+	columns := make([]string, len(ratingThresholds))
+	for i, threshold := range ratingThresholds {
+		columns[i] = "COUNT(*) FILTER (WHERE COALESCE(c.rating, 0) >= " + q.arg(threshold) + ")"
+	}
+
+	ratings := make([]models.RangeCount, len(ratingThresholds))
+	dest := make([]any, len(ratingThresholds))
+	for i, threshold := range ratingThresholds {
+		ratings[i].Min = threshold
+		dest[i] = &ratings[i].Count
+	}
+
+	query := `SELECT ` + strings.Join(columns, ", ") + ` FROM courses c ` + q.whereSQL()
+	if err := r.db.QueryRowContext(ctx, query, q.args...).Scan(dest...); err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+func (r *CourseRepository) durationFacet(ctx context.Context, status string, filter *models.CourseFilter) ([]models.RangeCount, error) {
+	q := newSearchQuery(status, filter, facetDuration)
+
+	columns := make([]string, len(durationRanges))
+	durations := make([]models.RangeCount, len(durationRanges))
+	dest := make([]any, len(durationRanges))
+	for i, bucket := range durationRanges {
+		condition := "COALESCE(c.duration, 0) >= " + q.arg(bucket.min)
+		durations[i].Min = float64(bucket.min)
+		if bucket.max >= 0 {
+			condition += " AND COALESCE(c.duration, 0) <= " + q.arg(bucket.max)
+			maxDuration := float64(bucket.max)
+			durations[i].Max = &maxDuration
+		}
+		columns[i] = "COUNT(*) FILTER (WHERE " + condition + ")"
+		dest[i] = &durations[i].Count
+	}
+
+	query := `SELECT ` + strings.Join(columns, ", ") + ` FROM courses c ` + q.whereSQL()
+	if err := r.db.QueryRowContext(ctx, query, q.args...).Scan(dest...); err != nil {
+		return nil, err
+	}
+	return durations, nil
+}
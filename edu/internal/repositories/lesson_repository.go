@@ -163,3 +163,58 @@ func (r *LessonRepository) GetLesson(ctx context.Context, lessonID uuid.UUID) (*
 
 	return lesson, nil
 }
+
+// ListPrerequisites возвращает предварительные уроки всех уроков курса: урок -> уроки, которые нужно пройти до него
+func (r *LessonRepository) ListPrerequisites(ctx context.Context, courseID uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	query := `
+		SELECT lp.lesson_id, lp.prerequisite_id
+		FROM lesson_prerequisites lp
+		JOIN lessons l ON l.id = lp.lesson_id
+		JOIN lessons p ON p.id = lp.prerequisite_id
+		WHERE l.course_id = $1
+		ORDER BY lp.lesson_id, p.order_num
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prerequisites := make(map[uuid.UUID][]uuid.UUID)
+	for rows.Next() {
+		var lessonID, prerequisiteID uuid.UUID
+		if err := rows.Scan(&lessonID, &prerequisiteID); err != nil {
+			return nil, err
+		}
+		prerequisites[lessonID] = append(prerequisites[lessonID], prerequisiteID)
+	}
+
+	return prerequisites, rows.Err()
+}
+
+// SetPrerequisites заменяет список предварительных уроков урока
+func (r *LessonRepository) SetPrerequisites(ctx context.Context, lessonID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM lesson_prerequisites WHERE lesson_id = $1`, lessonID); err != nil {
+		return err
+	}
+
+	for _, prerequisiteID := range prerequisiteIDs {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO lesson_prerequisites (lesson_id, prerequisite_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, lessonID, prerequisiteID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
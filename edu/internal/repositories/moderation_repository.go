@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"course2/internal/models"
+	"database/sql"
+	"platform/pagination"
+
+	"github.com/google/uuid"
+)
+
+type ModerationRepository struct {
+	db *sql.DB
+}
+
+func NewModerationRepository(db *sql.DB) *ModerationRepository {
+	return &ModerationRepository{db: db}
+}
+
+// ChangeStatus переводит курс из event.FromStatus в event.ToStatus, назначает проверяющего
+// event.ReviewerID и записывает событие в историю. Если notification не nil, уведомление
+// сохраняется в той же транзакции. Возвращает false, если статус курса уже изменился.
+func (r *ModerationRepository) ChangeStatus(ctx context.Context, event *models.ModerationEvent, notification *models.Notification) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE courses
+		SET status = $1, reviewer_id = $2, updated_at = NOW()
+		WHERE id = $3 AND status = $4
+	`, event.ToStatus, event.ReviewerID, event.CourseID, event.FromStatus)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO course_moderation_events (
+			id, course_id, actor_id, action, from_status, to_status,
+			reviewer_id, reason, note, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, NOW()
+		)
+		RETURNING created_at
+	`,
+		event.ID, event.CourseID, event.ActorID, event.Action, event.FromStatus,
+		event.ToStatus, event.ReviewerID, event.Reason, event.Note,
+	).Scan(&event.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	if notification != nil {
+		if err := createNotification(ctx, tx, notification); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// ListEvents возвращает страницу истории модерации курса в хронологическом порядке
+func (r *ModerationRepository) ListEvents(ctx context.Context, courseID uuid.UUID, page pagination.Params) (*pagination.Page[*models.ModerationEvent], error) {
+	const kind = "moderation-events"
+	afterCreated, afterID, err := afterTime(page, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, course_id, actor_id, action, from_status, to_status,
+			   reviewer_id, reason, note, created_at
+		FROM course_moderation_events
+		WHERE course_id = $1
+		  AND ($2::timestamptz IS NULL OR (created_at, id) > ($2::timestamptz, $3::uuid))
+		ORDER BY created_at, id
+		LIMIT $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, courseID, afterCreated, afterID, page.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*models.ModerationEvent
+	for rows.Next() {
+		event := &models.ModerationEvent{}
+		var actorID uuid.NullUUID
+		err := rows.Scan(
+			&event.ID, &event.CourseID, &actorID, &event.Action, &event.FromStatus,
+			&event.ToStatus, &event.ReviewerID, &event.Reason, &event.Note,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		event.ActorID = actorID.UUID
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := pagination.NewPage(events, page, kind, func(event *models.ModerationEvent) any {
+		return timeKey{Time: event.CreatedAt, ID: event.ID}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.TotalEstimate, err = pagination.EstimateTotal(ctx, r.db, "FROM course_moderation_events WHERE course_id = $1", courseID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"course2/internal/models"
+	"database/sql"
+	"platform/pagination"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// execer - *sql.DB или *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	return createNotification(ctx, r.db, notification)
+}
+
+func createNotification(ctx context.Context, db execer, notification *models.Notification) error {
+	query := `
+		INSERT INTO notifications (
+			id, user_id, type, title, message, course_id, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, NOW()
+		)
+	`
+
+	_, err := db.ExecContext(ctx, query,
+		notification.ID, notification.UserID, notification.Type,
+		notification.Title, notification.Message, notification.CourseID,
+	)
+
+	return err
+}
+
+// ListByUser возвращает уведомления пользователя, сначала новые
+func (r *NotificationRepository) ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, page pagination.Params) (*pagination.Page[*models.Notification], error) {
+	kind := "notifications"
+	if unreadOnly {
+		kind = "notifications:unread"
+	}
+	afterCreated, afterID, err := afterTime(page, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	const from = `
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+	`
+
+	query := `
+		SELECT id, user_id, type, title, message, course_id, read_at, created_at
+		` + from + `
+		  AND ($3::timestamptz IS NULL OR (created_at, id) < ($3::timestamptz, $4::uuid))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $5
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, unreadOnly, afterCreated, afterID, page.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*models.Notification
+	for rows.Next() {
+		notification := &models.Notification{}
+		err := rows.Scan(
+			&notification.ID, &notification.UserID, &notification.Type,
+			&notification.Title, &notification.Message, &notification.CourseID,
+			&notification.ReadAt, &notification.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := pagination.NewPage(notifications, page, kind, func(notification *models.Notification) any {
+		return timeKey{Time: notification.CreatedAt, ID: notification.ID}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.TotalEstimate, err = pagination.EstimateTotal(ctx, r.db, from, userID, unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// MarkRead отмечает уведомление пользователя прочитанным
+func (r *NotificationRepository) MarkRead(ctx context.Context, id, userID uuid.UUID) error {
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,303 @@
+package repositories
+
+import (
+	"context"
+	"course2/internal/models"
+	"course2/internal/payment"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type OrderRepository struct {
+	db *sql.DB
+}
+
+func NewOrderRepository(db *sql.DB) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+// orderColumns - колонки заказа и адрес оплаты последней незавершенной платежной сессии
+const orderColumns = `
+	o.id, o.user_id, o.course_id, o.amount, o.currency, o.status, o.created_at, o.updated_at,
+	COALESCE((
+		SELECT p.checkout_url FROM payments p
+		WHERE p.order_id = o.id AND p.status = 'pending'
+		ORDER BY p.created_at DESC
+		LIMIT 1
+	), '')`
+
+func scanOrder(row scanner) (*models.Order, error) {
+	order := &models.Order{}
+	err := row.Scan(
+		&order.ID, &order.UserID, &order.CourseID, &order.Amount, &order.Currency,
+		&order.Status, &order.CreatedAt, &order.UpdatedAt, &order.CheckoutURL,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// CreateOpen сохраняет новый заказ в статусе created. Возвращает false, если у пользователя
+// уже есть незавершенный заказ этого курса.
+func (r *OrderRepository) CreateOpen(ctx context.Context, order *models.Order) (bool, error) {
+	query := `
+		INSERT INTO orders (id, user_id, course_id, amount, currency, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, 'created', NOW(), NOW())
+		ON CONFLICT (user_id, course_id) WHERE status IN ('created', 'pending') DO NOTHING
+		RETURNING status, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		order.ID, order.UserID, order.CourseID, order.Amount, order.Currency,
+	).Scan(&order.Status, &order.CreatedAt, &order.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CreatePaid сохраняет оплаченный заказ бесплатного курса и в той же транзакции выдает курс
+func (r *OrderRepository) CreatePaid(ctx context.Context, order *models.Order) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO orders (id, user_id, course_id, amount, currency, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, 'paid', NOW(), NOW())
+		RETURNING status, created_at, updated_at
+	`, order.ID, order.UserID, order.CourseID, order.Amount, order.Currency,
+	).Scan(&order.Status, &order.CreatedAt, &order.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := grantPurchase(ctx, tx, order.UserID, order.CourseID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetOpen возвращает незавершенный заказ курса пользователя или nil
+func (r *OrderRepository) GetOpen(ctx context.Context, userID, courseID uuid.UUID) (*models.Order, error) {
+	query := `
+		SELECT ` + orderColumns + `
+		FROM orders o
+		WHERE o.user_id = $1 AND o.course_id = $2 AND o.status IN ('created', 'pending')
+	`
+
+	order, err := scanOrder(r.db.QueryRowContext(ctx, query, userID, courseID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// GetByID возвращает заказ или nil
+func (r *OrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Order, error) {
+	query := `
+		SELECT ` + orderColumns + `
+		FROM orders o
+		WHERE o.id = $1
+	`
+
+	order, err := scanOrder(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// GetBySession возвращает заказ и платеж по платежной сессии провайдера или nil
+func (r *OrderRepository) GetBySession(ctx context.Context, provider, sessionID string) (*models.Order, *models.Payment, error) {
+	query := `
+		SELECT ` + orderColumns + `,
+			p.id, p.order_id, p.provider, p.session_id, p.checkout_url,
+			p.provider_payment_id, p.status, p.created_at, p.updated_at
+		FROM payments p
+		JOIN orders o ON o.id = p.order_id
+		WHERE p.provider = $1 AND p.session_id = $2
+	`
+
+	order := &models.Order{}
+	record := &models.Payment{}
+	err := r.db.QueryRowContext(ctx, query, provider, sessionID).Scan(
+		&order.ID, &order.UserID, &order.CourseID, &order.Amount, &order.Currency,
+		&order.Status, &order.CreatedAt, &order.UpdatedAt, &order.CheckoutURL,
+		&record.ID, &record.OrderID, &record.Provider, &record.SessionID, &record.CheckoutURL,
+		&record.ProviderPaymentID, &record.Status, &record.CreatedAt, &record.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return order, record, nil
+}
+
+// AddPayment сохраняет платежную сессию заказа и переводит заказ в pending.
+// Возвращает false, если заказ уже не ждет оплаты.
+func (r *OrderRepository) AddPayment(ctx context.Context, order *models.Order, record *models.Payment) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE orders
+		SET status = 'pending', updated_at = NOW()
+		WHERE id = $1 AND status IN ('created', 'pending')
+		RETURNING status, updated_at
+	`, order.ID).Scan(&order.Status, &order.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO payments (id, order_id, provider, session_id, checkout_url, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, 'pending', NOW(), NOW())
+		RETURNING status, created_at, updated_at
+	`, record.ID, order.ID, record.Provider, record.SessionID, record.CheckoutURL,
+	).Scan(&record.Status, &record.CreatedAt, &record.UpdatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	order.CheckoutURL = record.CheckoutURL
+	return true, tx.Commit()
+}
+
+// ApplyEvent применяет уведомление провайдера к платежу и его заказу в одной транзакции:
+// paid выдает курс, refunded отзывает его. Событие запоминается, поэтому повторная доставка
+// ничего не меняет. Возвращает false для повторного события и для перехода, недопустимого
+// в текущем статусе заказа (такое событие тоже запоминается).
+func (r *OrderRepository) ApplyEvent(ctx context.Context, record *models.Payment, event *payment.Event) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO payment_events (provider, event_id, type, payment_id, received_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (provider, event_id) DO NOTHING
+	`, record.Provider, event.ID, event.Type, record.ID)
+	if err != nil {
+		return false, err
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return false, err
+	}
+
+	var userID, courseID uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+		UPDATE orders
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND status = ANY($3)
+		RETURNING user_id, course_id
+	`, event.Type, record.OrderID, pq.Array(models.OrderStatusesBefore(event.Type))).Scan(&userID, &courseID)
+	if err == sql.ErrNoRows {
+		return false, tx.Commit()
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var providerPaymentID *string
+	if event.PaymentID != "" {
+		providerPaymentID = &event.PaymentID
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE payments
+		SET status = $1, provider_payment_id = COALESCE($2, provider_payment_id), updated_at = NOW()
+		WHERE id = $3
+	`, event.Type, providerPaymentID, record.ID)
+	if err != nil {
+		return false, err
+	}
+
+	switch event.Type {
+	case models.OrderPaid:
+		err = grantPurchase(ctx, tx, userID, courseID)
+	case models.OrderRefunded:
+		err = revokePurchase(ctx, tx, userID, courseID, record.OrderID)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// revokePurchase отзывает курс после возврата заказа orderID: удаляет покупку вместе с отметкой
+// о завершении, XP за завершение курса и отзывает сертификат. Если курс оплачен другим заказом
+// (например, заказ оплачен из failed после повторной покупки), покупка остается.
+// Сертификат не удаляется, чтобы его проверка по серийному номеру показывала отзыв;
+// при повторной покупке и завершении курса он не выдается заново.
+func revokePurchase(ctx context.Context, tx *sql.Tx, userID, courseID, orderID uuid.UUID) error {
+	var paidElsewhere bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM orders
+			WHERE user_id = $1 AND course_id = $2 AND status = $3 AND id <> $4
+		)
+	`, userID, courseID, models.OrderPaid, orderID).Scan(&paidElsewhere)
+	if err != nil || paidElsewhere {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM purchased_courses WHERE user_id = $1 AND course_id = $2
+	`, userID, courseID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM xp_entries WHERE user_id = $1 AND course_id = $2 AND type = 'course_complete'
+	`, userID, courseID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE certificates
+		SET revoked_at = NOW(), revoke_reason = $3
+		WHERE user_id = $1 AND course_id = $2 AND revoked_at IS NULL
+	`, userID, courseID, models.CertificateRefundReason)
+	return err
+}
+
+// grantPurchase выдает курс пользователю. Уже выданный курс не меняется.
+func grantPurchase(ctx context.Context, tx *sql.Tx, userID, courseID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO purchased_courses (user_id, course_id, purchased_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id, course_id) DO NOTHING
+	`, userID, courseID)
+	return err
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"platform/pagination"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// timeKey - ключ последнего элемента страницы для списков, упорядоченных по времени и id
+type timeKey struct {
+	Time time.Time `json:"t"`
+	ID   uuid.UUID `json:"id"`
+}
+
+// afterTime разбирает курсор списка kind. Для первой страницы возвращает nil, nil:
+// в запросе условие ($n::timestamptz IS NULL OR (...) < ($n, $m)) тогда не ограничивает выборку.
+func afterTime(page pagination.Params, kind string) (*time.Time, *uuid.UUID, error) {
+	var key timeKey
+	ok, err := page.Decode(kind, &key)
+	if err != nil || !ok {
+		return nil, nil, err
+	}
+	return &key.Time, &key.ID, nil
+}
+
+// countKey - ключ последнего элемента страницы для списков, упорядоченных по счетчику и id
+type countKey struct {
+	Count int       `json:"c"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// afterCount разбирает курсор списка kind, упорядоченного по счетчику. Для первой страницы возвращает nil, nil.
+func afterCount(page pagination.Params, kind string) (*int, *uuid.UUID, error) {
+	var key countKey
+	ok, err := page.Decode(kind, &key)
+	if err != nil || !ok {
+		return nil, nil, err
+	}
+	return &key.Count, &key.ID, nil
+}
+
+// numberKey - ключ последнего элемента страницы для списков, упорядоченных по дробному числу и id
+type numberKey struct {
+	Number float64   `json:"n"`
+	ID     uuid.UUID `json:"id"`
+}
+
+// afterNumber разбирает курсор списка kind, упорядоченного по дробному числу. Для первой страницы возвращает nil, nil.
+func afterNumber(page pagination.Params, kind string) (*float64, *uuid.UUID, error) {
+	var key numberKey
+	ok, err := page.Decode(kind, &key)
+	if err != nil || !ok {
+		return nil, nil, err
+	}
+	return &key.Number, &key.ID, nil
+}
+
+// nameKey - ключ последнего элемента страницы для списков, упорядоченных по названию и id
+type nameKey struct {
+	Name string    `json:"n"`
+	ID   uuid.UUID `json:"id"`
+}
+
+// afterName разбирает курсор списка kind, упорядоченного по названию. Для первой страницы возвращает nil, nil.
+func afterName(page pagination.Params, kind string) (*string, *uuid.UUID, error) {
+	var key nameKey
+	ok, err := page.Decode(kind, &key)
+	if err != nil || !ok {
+		return nil, nil, err
+	}
+	return &key.Name, &key.ID, nil
+}
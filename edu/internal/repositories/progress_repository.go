@@ -65,6 +65,32 @@ func (r *ProgressRepository) GetLessonProgress(ctx context.Context, userID, less
 }
 
 func (r *ProgressRepository) UpdateLessonProgress(ctx context.Context, progress *models.LessonProgress) error {
+	return updateLessonProgress(ctx, r.db, progress)
+}
+
+// SaveTestResult сохраняет прогресс урока после отправки теста и в той же транзакции
+// начисляет xp (nil - без начисления)
+func (r *ProgressRepository) SaveTestResult(ctx context.Context, progress *models.LessonProgress, xp *models.XPEntry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateLessonProgress(ctx, tx, progress); err != nil {
+		return err
+	}
+
+	if xp != nil {
+		if err := addXPEntry(ctx, tx, xp); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func updateLessonProgress(ctx context.Context, db execer, progress *models.LessonProgress) error {
 	query := `
 		UPDATE lesson_progress
 		SET viewed_at = $1, test_score = $2, passed_test = $3,
@@ -73,7 +99,7 @@ func (r *ProgressRepository) UpdateLessonProgress(ctx context.Context, progress
 		WHERE id = $7 AND user_id = $8 AND lesson_id = $9
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := db.ExecContext(ctx, query,
 		progress.ViewedAt, progress.TestScore, progress.PassedTest,
 		progress.CompletedAt, progress.LastAttemptAt, progress.IsCompleted,
 		progress.ID, progress.UserID, progress.LessonID,
@@ -143,7 +169,38 @@ func (r *ProgressRepository) GetCourseProgress(ctx context.Context, userID, cour
 	return progress, nil
 }
 
+// CompletedLessons возвращает пройденные пользователем уроки курса
+func (r *ProgressRepository) CompletedLessons(ctx context.Context, userID, courseID uuid.UUID) (map[uuid.UUID]bool, error) {
+	query := `
+		SELECT lp.lesson_id
+		FROM lesson_progress lp
+		JOIN lessons l ON l.id = lp.lesson_id
+		WHERE lp.user_id = $1 AND l.course_id = $2 AND lp.is_completed
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	completed := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var lessonID uuid.UUID
+		if err := rows.Scan(&lessonID); err != nil {
+			return nil, err
+		}
+		completed[lessonID] = true
+	}
+
+	return completed, rows.Err()
+}
+
 func (r *ProgressRepository) AddXPEntry(ctx context.Context, entry *models.XPEntry) error {
+	return addXPEntry(ctx, r.db, entry)
+}
+
+func addXPEntry(ctx context.Context, db execer, entry *models.XPEntry) error {
 	query := `
 		INSERT INTO xp_entries (
 			id, user_id, course_id, lesson_id, type,
@@ -153,7 +210,7 @@ func (r *ProgressRepository) AddXPEntry(ctx context.Context, entry *models.XPEnt
 		)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := db.ExecContext(ctx, query,
 		entry.ID, entry.UserID, entry.CourseID, entry.LessonID,
 		entry.Type, entry.Amount,
 	)
@@ -2,7 +2,9 @@ package repositories
 
 import (
 	"context"
+	"course2/internal/models"
 	"database/sql"
+	"platform/pagination"
 	"time"
 
 	"github.com/google/uuid"
@@ -43,52 +45,116 @@ func (r *PurchaseRepository) HasPurchased(ctx context.Context, userID, courseID
 	return exists, nil
 }
 
-func (r *PurchaseRepository) GetPurchasedCourses(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+// GetPurchasedCourses возвращает покупки пользователя, сначала новые
+func (r *PurchaseRepository) GetPurchasedCourses(ctx context.Context, userID uuid.UUID, page pagination.Params) (*pagination.Page[*models.PurchasedCourse], error) {
+	const kind = "purchases"
+	afterPurchased, afterID, err := afterTime(page, kind)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT course_id
+		SELECT course_id, purchased_at, completed_at
 		FROM purchased_courses
 		WHERE user_id = $1
-		ORDER BY purchased_at DESC
+		  AND ($2::timestamptz IS NULL OR (purchased_at, course_id) < ($2::timestamptz, $3::uuid))
+		ORDER BY purchased_at DESC, course_id DESC
+		LIMIT $4
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	rows, err := r.db.QueryContext(ctx, query, userID, afterPurchased, afterID, page.Limit+1)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var courseIDs []uuid.UUID
+	var purchases []*models.PurchasedCourse
 	for rows.Next() {
-		var courseID uuid.UUID
-		if err := rows.Scan(&courseID); err != nil {
+		purchase := &models.PurchasedCourse{}
+		if err := rows.Scan(&purchase.CourseID, &purchase.PurchasedAt, &purchase.CompletedAt); err != nil {
 			return nil, err
 		}
-		courseIDs = append(courseIDs, courseID)
+		purchases = append(purchases, purchase)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := pagination.NewPage(purchases, page, kind, func(purchase *models.PurchasedCourse) any {
+		return timeKey{Time: purchase.PurchasedAt, ID: purchase.CourseID}
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return courseIDs, nil
+	result.TotalEstimate, err = pagination.EstimateTotal(ctx, r.db, "FROM purchased_courses WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-func (r *PurchaseRepository) UpdateCompletedAt(ctx context.Context, userID, courseID uuid.UUID, completedAt time.Time) error {
+// GetCompletedAt возвращает время завершения купленного курса или nil, если курс не куплен или не завершен
+func (r *PurchaseRepository) GetCompletedAt(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
 	query := `
-		UPDATE purchased_courses
-		SET completed_at = $1
-		WHERE user_id = $2 AND course_id = $3
+		SELECT completed_at
+		FROM purchased_courses
+		WHERE user_id = $1 AND course_id = $2
 	`
 
-	result, err := r.db.ExecContext(ctx, query, completedAt, userID, courseID)
+	var completedAt *time.Time
+	err := r.db.QueryRowContext(ctx, query, userID, courseID).Scan(&completedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return completedAt, nil
+}
+
+// CompleteCourse отмечает курс завершенным, если пройдены все его уроки, и в той же транзакции
+// начисляет бонус xp (nil - без бонуса). Возвращает время завершения или nil, если курс
+// не куплен, пройден не полностью или уже был завершен: бонус начисляется один раз.
+func (r *PurchaseRepository) CompleteCourse(ctx context.Context, userID, courseID uuid.UUID, xp *models.XPEntry) (*time.Time, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer tx.Rollback()
 
-	rows, err := result.RowsAffected()
+	var completedAt time.Time
+	err = tx.QueryRowContext(ctx, `
+		UPDATE purchased_courses
+		SET completed_at = NOW()
+		WHERE user_id = $1 AND course_id = $2 AND completed_at IS NULL
+		  AND EXISTS (SELECT 1 FROM lessons WHERE course_id = $2)
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM lessons l
+			LEFT JOIN lesson_progress lp ON lp.lesson_id = l.id AND lp.user_id = $1
+			WHERE l.course_id = $2 AND NOT COALESCE(lp.is_completed, FALSE)
+		  )
+		RETURNING completed_at
+	`, userID, courseID).Scan(&completedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if rows == 0 {
-		return sql.ErrNoRows
+	if xp != nil {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO xp_entries (id, user_id, course_id, type, amount, earned_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, xp.ID, userID, courseID, xp.Type, xp.Amount, completedAt)
+		if err != nil {
+			return nil, err
+		}
+		xp.EarnedAt = completedAt
 	}
 
-	return nil
+	return &completedAt, tx.Commit()
 }
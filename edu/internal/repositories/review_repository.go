@@ -2,7 +2,9 @@ package repositories
 
 import (
 	"context"
+	"course2/internal/models"
 	"database/sql"
+	"platform/pagination"
 
 	"github.com/google/uuid"
 )
@@ -15,85 +17,123 @@ func NewReviewRepository(db *sql.DB) *ReviewRepository {
 	return &ReviewRepository{db: db}
 }
 
-func (r *ReviewRepository) Create(ctx context.Context, userID, courseID uuid.UUID, rating int, text string) error {
-	query := `
-		INSERT INTO reviews (id, user_id, course_id, rating, text, created_at)
-		VALUES ($1, $2, $3, $4, $5, NOW())
-	`
+// reviewColumns - поля отзыва вместе с именем автора
+const reviewColumns = `
+	r.id, r.course_id, r.user_id, TRIM(CONCAT(u.first_name, ' ', u.last_name)),
+	r.rating, r.text, r.helpful_count, r.reports_count, r.hidden_at,
+	r.created_at, r.updated_at
+`
+
+// scanner - строка результата запроса: *sql.Row или *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
 
-	_, err := r.db.ExecContext(ctx, query, uuid.New(), userID, courseID, rating, text)
+func scanReview(row scanner) (*models.Review, error) {
+	review := &models.Review{}
+	err := row.Scan(
+		&review.ID, &review.CourseID, &review.UserID, &review.UserName,
+		&review.Rating, &review.Text, &review.HelpfulCount, &review.ReportsCount,
+		&review.HiddenAt, &review.CreatedAt, &review.UpdatedAt,
+	)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return review, nil
+}
 
-	// Обновляем средний рейтинг курса
-	updateQuery := `
-		UPDATE courses
-		SET rating = (
-			SELECT AVG(rating)::float
-			FROM reviews
-			WHERE course_id = $1
-		)
-		WHERE id = $1
-	`
+func scanReviews(rows *sql.Rows) ([]*models.Review, error) {
+	var reviews []*models.Review
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
 
-	_, err = r.db.ExecContext(ctx, updateQuery, courseID)
-	return err
+// Create сохраняет отзыв и пересчитывает рейтинг курса.
+// Возвращает false, если пользователь уже оставил отзыв на этот курс.
+func (r *ReviewRepository) Create(ctx context.Context, review *models.Review) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO reviews (id, course_id, user_id, rating, text, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (course_id, user_id) DO NOTHING
+		RETURNING created_at, updated_at
+	`, review.ID, review.CourseID, review.UserID, review.Rating, review.Text).Scan(&review.CreatedAt, &review.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := recomputeRating(ctx, tx, review.CourseID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
 }
 
-func (r *ReviewRepository) GetByCourse(ctx context.Context, courseID uuid.UUID) ([]struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Rating    int
-	Text      string
-	CreatedAt string
-}, error) {
+func (r *ReviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Review, error) {
 	query := `
-		SELECT id, user_id, rating, text, created_at
-		FROM reviews
-		WHERE course_id = $1
-		ORDER BY created_at DESC
+		SELECT ` + reviewColumns + `
+		FROM reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.id = $1
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, courseID)
+	review, err := scanReview(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var reviews []struct {
-		ID        uuid.UUID
-		UserID    uuid.UUID
-		Rating    int
-		Text      string
-		CreatedAt string
+	return review, nil
+}
+
+// Update изменяет оценку и текст отзыва и пересчитывает рейтинг курса
+func (r *ReviewRepository) Update(ctx context.Context, review *models.Review) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
-	for rows.Next() {
-		var review struct {
-			ID        uuid.UUID
-			UserID    uuid.UUID
-			Rating    int
-			Text      string
-			CreatedAt string
-		}
-		if err := rows.Scan(&review.ID, &review.UserID, &review.Rating, &review.Text, &review.CreatedAt); err != nil {
-			return nil, err
-		}
-		reviews = append(reviews, review)
+	err = tx.QueryRowContext(ctx, `
+		UPDATE reviews
+		SET rating = $1, text = $2, updated_at = NOW()
+		WHERE id = $3
+		RETURNING updated_at
+	`, review.Rating, review.Text, review.ID).Scan(&review.UpdatedAt)
+	if err != nil {
+		return err
 	}
 
-	return reviews, nil
+	if err := recomputeRating(ctx, tx, review.CourseID); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (r *ReviewRepository) Update(ctx context.Context, id uuid.UUID, rating int, text string) error {
-	query := `
-		UPDATE reviews
-		SET rating = $1, text = $2
-		WHERE id = $3
-	`
+// Delete удаляет отзыв вместе с отметками и жалобами и пересчитывает рейтинг курса
+func (r *ReviewRepository) Delete(ctx context.Context, review *models.Review) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	result, err := r.db.ExecContext(ctx, query, rating, text, id)
+	result, err := tx.ExecContext(ctx, `DELETE FROM reviews WHERE id = $1`, review.ID)
 	if err != nil {
 		return err
 	}
@@ -102,30 +142,377 @@ func (r *ReviewRepository) Update(ctx context.Context, id uuid.UUID, rating int,
 	if err != nil {
 		return err
 	}
-
 	if rows == 0 {
 		return sql.ErrNoRows
 	}
 
-	return nil
+	if err := recomputeRating(ctx, tx, review.CourseID); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (r *ReviewRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM reviews WHERE id = $1`
+// Hide скрывает отзыв, пересчитывает рейтинг курса и сохраняет уведомление автору отзыва.
+// Возвращает false, если отзыв уже скрыт.
+func (r *ReviewRepository) Hide(ctx context.Context, review *models.Review, adminID uuid.UUID, notification *models.Notification) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	err = tx.QueryRowContext(ctx, `
+		UPDATE reviews
+		SET hidden_at = NOW(), hidden_by = $1
+		WHERE id = $2 AND hidden_at IS NULL
+		RETURNING hidden_at
+	`, adminID, review.ID).Scan(&review.HiddenAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
 	if err != nil {
-		return err
+		return false, err
+	}
+
+	if err := recomputeRating(ctx, tx, review.CourseID); err != nil {
+		return false, err
+	}
+	if notification != nil {
+		if err := createNotification(ctx, tx, notification); err != nil {
+			return false, err
+		}
+	}
+	return true, tx.Commit()
+}
+
+// Unhide возвращает скрытый отзыв и пересчитывает рейтинг курса. Жалобы на отзыв снимаются.
+// Возвращает false, если отзыв не скрыт.
+func (r *ReviewRepository) Unhide(ctx context.Context, review *models.Review) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE reviews
+		SET hidden_at = NULL, hidden_by = NULL, reports_count = 0
+		WHERE id = $1 AND hidden_at IS NOT NULL
+	`, review.ID)
+	if err != nil {
+		return false, err
 	}
 
 	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM review_reports WHERE review_id = $1`, review.ID); err != nil {
+		return false, err
+	}
+
+	if err := recomputeRating(ctx, tx, review.CourseID); err != nil {
+		return false, err
+	}
+	review.HiddenAt = nil
+	review.ReportsCount = 0
+	return true, tx.Commit()
+}
+
+// Vote отмечает отзыв полезным. Повторная отметка того же пользователя ничего не меняет.
+func (r *ReviewRepository) Vote(ctx context.Context, reviewID, userID uuid.UUID) error {
+	return r.changeCounter(ctx, reviewID, `
+		INSERT INTO review_votes (review_id, user_id, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT DO NOTHING
+	`, `UPDATE reviews SET helpful_count = helpful_count + 1 WHERE id = $1`, userID)
+}
+
+// Unvote снимает отметку «полезный отзыв»
+func (r *ReviewRepository) Unvote(ctx context.Context, reviewID, userID uuid.UUID) error {
+	return r.changeCounter(ctx, reviewID, `
+		DELETE FROM review_votes WHERE review_id = $1 AND user_id = $2
+	`, `UPDATE reviews SET helpful_count = helpful_count - 1 WHERE id = $1`, userID)
+}
+
+// Report сохраняет жалобу на отзыв. Повторная жалоба того же пользователя ничего не меняет.
+func (r *ReviewRepository) Report(ctx context.Context, reviewID, userID uuid.UUID, reason string) error {
+	return r.changeCounter(ctx, reviewID, `
+		INSERT INTO review_reports (review_id, user_id, reason, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT DO NOTHING
+	`, `UPDATE reviews SET reports_count = reports_count + 1 WHERE id = $1`, userID, reason)
+}
+
+// changeCounter выполняет change и, если он затронул строку, обновляет счетчик отзыва counter
+// в той же транзакции. Аргументы change: reviewID, затем args.
+func (r *ReviewRepository) changeCounter(ctx context.Context, reviewID uuid.UUID, change, counter string, args ...any) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, change, append([]any{reviewID}, args...)...)
 	if err != nil {
 		return err
 	}
 
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
 	if rows == 0 {
-		return sql.ErrNoRows
+		return nil
 	}
 
-	return nil
+	if _, err := tx.ExecContext(ctx, counter, reviewID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListByCourse возвращает видимые отзывы курса в порядке sort
+func (r *ReviewRepository) ListByCourse(ctx context.Context, courseID uuid.UUID, sort models.ReviewSort, page pagination.Params) (*pagination.Page[*models.Review], error) {
+	const from = `
+		FROM reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.course_id = $1 AND r.hidden_at IS NULL
+	`
+
+	var (
+		query string
+		args  []any
+		key   func(*models.Review) any
+	)
+	kind := "reviews:" + string(sort)
+
+	switch sort {
+	case models.ReviewSortHelpful:
+		afterHelpful, afterID, err := afterCount(page, kind)
+		if err != nil {
+			return nil, err
+		}
+		query = `
+			SELECT ` + reviewColumns + `
+			` + from + `
+			  AND ($2::integer IS NULL OR (r.helpful_count, r.id) < ($2::integer, $3::uuid))
+			ORDER BY r.helpful_count DESC, r.id DESC
+			LIMIT $4
+		`
+		args = []any{courseID, afterHelpful, afterID}
+		key = func(review *models.Review) any {
+			return countKey{Count: review.HelpfulCount, ID: review.ID}
+		}
+	default:
+		afterCreated, afterID, err := afterTime(page, kind)
+		if err != nil {
+			return nil, err
+		}
+		query = `
+			SELECT ` + reviewColumns + `
+			` + from + `
+			  AND ($2::timestamptz IS NULL OR (r.created_at, r.id) < ($2::timestamptz, $3::uuid))
+			ORDER BY r.created_at DESC, r.id DESC
+			LIMIT $4
+		`
+		args = []any{courseID, afterCreated, afterID}
+		key = func(review *models.Review) any {
+			return timeKey{Time: review.CreatedAt, ID: review.ID}
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, page.Limit+1)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews, err := scanReviews(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := pagination.NewPage(reviews, page, kind, key)
+	if err != nil {
+		return nil, err
+	}
+
+	result.TotalEstimate, err = pagination.EstimateTotal(ctx, r.db, from, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListReported возвращает видимые отзывы с жалобами, сначала с наибольшим числом жалоб
+func (r *ReviewRepository) ListReported(ctx context.Context, page pagination.Params) (*pagination.Page[*models.Review], error) {
+	const kind = "reviews:reported"
+	afterReports, afterID, err := afterCount(page, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	const from = `
+		FROM reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.reports_count > 0 AND r.hidden_at IS NULL
+	`
+
+	query := `
+		SELECT ` + reviewColumns + `
+		` + from + `
+		  AND ($1::integer IS NULL OR (r.reports_count, r.id) < ($1::integer, $2::uuid))
+		ORDER BY r.reports_count DESC, r.id DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, afterReports, afterID, page.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews, err := scanReviews(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := pagination.NewPage(reviews, page, kind, func(review *models.Review) any {
+		return countKey{Count: review.ReportsCount, ID: review.ID}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.TotalEstimate, err = pagination.EstimateTotal(ctx, r.db, from)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListReports возвращает жалобы на отзыв, сначала новые
+func (r *ReviewRepository) ListReports(ctx context.Context, reviewID uuid.UUID, page pagination.Params) (*pagination.Page[*models.ReviewReport], error) {
+	const kind = "review-reports"
+	afterCreated, afterUserID, err := afterTime(page, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT review_id, user_id, reason, created_at
+		FROM review_reports
+		WHERE review_id = $1
+		  AND ($2::timestamptz IS NULL OR (created_at, user_id) < ($2::timestamptz, $3::uuid))
+		ORDER BY created_at DESC, user_id DESC
+		LIMIT $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, reviewID, afterCreated, afterUserID, page.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []*models.ReviewReport
+	for rows.Next() {
+		report := &models.ReviewReport{}
+		if err := rows.Scan(&report.ReviewID, &report.UserID, &report.Reason, &report.CreatedAt); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := pagination.NewPage(reports, page, kind, func(report *models.ReviewReport) any {
+		return timeKey{Time: report.CreatedAt, ID: report.UserID}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.TotalEstimate, err = pagination.EstimateTotal(ctx, r.db, "FROM review_reports WHERE review_id = $1", reviewID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetRating возвращает рейтинг курса и распределение оценок
+func (r *ReviewRepository) GetRating(ctx context.Context, courseID uuid.UUID) (*models.RatingSummary, error) {
+	query := `
+		SELECT c.id, COALESCE(c.rating, 0), COALESCE(cr.reviews_count, 0),
+			   COALESCE(cr.stars_1, 0), COALESCE(cr.stars_2, 0), COALESCE(cr.stars_3, 0),
+			   COALESCE(cr.stars_4, 0), COALESCE(cr.stars_5, 0)
+		FROM courses c
+		LEFT JOIN course_ratings cr ON cr.course_id = c.id
+		WHERE c.id = $1
+	`
+
+	summary := &models.RatingSummary{}
+	var stars [5]int
+	err := r.db.QueryRowContext(ctx, query, courseID).Scan(
+		&summary.CourseID, &summary.Rating, &summary.ReviewsCount,
+		&stars[0], &stars[1], &stars[2], &stars[3], &stars[4],
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Histogram = make(map[int]int, len(stars))
+	for i, count := range stars {
+		summary.Histogram[i+1] = count
+	}
+	return summary, nil
+}
+
+// recomputeRating пересчитывает рейтинг курса и распределение оценок по видимым отзывам.
+// Строка курса блокируется до конца транзакции, поэтому параллельные изменения отзывов
+// одного курса пересчитываются по очереди и последний пересчет видит все отзывы.
+func recomputeRating(ctx context.Context, tx *sql.Tx, courseID uuid.UUID) error {
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM courses WHERE id = $1 FOR UPDATE`, courseID); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO course_ratings (course_id, reviews_count, stars_1, stars_2, stars_3, stars_4, stars_5, updated_at)
+		SELECT $1, COUNT(*),
+			   COUNT(*) FILTER (WHERE rating = 1), COUNT(*) FILTER (WHERE rating = 2),
+			   COUNT(*) FILTER (WHERE rating = 3), COUNT(*) FILTER (WHERE rating = 4),
+			   COUNT(*) FILTER (WHERE rating = 5), NOW()
+		FROM reviews
+		WHERE course_id = $1 AND hidden_at IS NULL
+		ON CONFLICT (course_id) DO UPDATE SET
+			reviews_count = EXCLUDED.reviews_count,
+			stars_1 = EXCLUDED.stars_1,
+			stars_2 = EXCLUDED.stars_2,
+			stars_3 = EXCLUDED.stars_3,
+			stars_4 = EXCLUDED.stars_4,
+			stars_5 = EXCLUDED.stars_5,
+			updated_at = EXCLUDED.updated_at
+	`, courseID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE courses
+		SET rating = (
+			SELECT COALESCE(ROUND(AVG(rating)::numeric, 2), 0)::float
+			FROM reviews
+			WHERE course_id = $1 AND hidden_at IS NULL
+		)
+		WHERE id = $1
+	`, courseID)
+	return err
 }
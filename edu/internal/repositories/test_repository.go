@@ -20,14 +20,23 @@ func NewTestRepository(db *sql.DB) *TestRepository {
 func (r *TestRepository) Create(ctx context.Context, test *models.Test) error {
 	query := `
 		INSERT INTO tests (
-			id, lesson_id, passing_score, created_at, updated_at
+			id, lesson_id, passing_score, question_count, tag_quotas,
+			shuffle_questions, shuffle_options, max_attempts, cooldown_seconds,
+			time_limit_seconds, scoring_policy, late_submission, review_policy, created_at, updated_at
 		) VALUES (
-			$1, $2, $3, NOW(), NOW()
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW()
 		)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		test.ID, test.LessonID, test.PassingScore,
+	quotasJSON, err := jsonArray(test.TagQuotas)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, query,
+		test.ID, test.LessonID, test.PassingScore, test.QuestionCount, quotasJSON,
+		test.ShuffleQuestions, test.ShuffleOptions, test.MaxAttempts, test.CooldownSeconds,
+		test.TimeLimitSeconds, test.ScoringPolicy, test.LateSubmission, test.ReviewPolicy,
 	)
 
 	return err
@@ -35,14 +44,19 @@ func (r *TestRepository) Create(ctx context.Context, test *models.Test) error {
 
 func (r *TestRepository) GetByLessonID(ctx context.Context, lessonID uuid.UUID) (*models.Test, error) {
 	query := `
-		SELECT id, lesson_id, passing_score, created_at, updated_at
+		SELECT id, lesson_id, passing_score, question_count, tag_quotas,
+			   shuffle_questions, shuffle_options, max_attempts, cooldown_seconds,
+			   time_limit_seconds, scoring_policy, late_submission, review_policy, created_at, updated_at
 		FROM tests
 		WHERE lesson_id = $1
 	`
 
 	test := &models.Test{}
+	var quotasJSON []byte
 	err := r.db.QueryRowContext(ctx, query, lessonID).Scan(
-		&test.ID, &test.LessonID, &test.PassingScore,
+		&test.ID, &test.LessonID, &test.PassingScore, &test.QuestionCount, &quotasJSON,
+		&test.ShuffleQuestions, &test.ShuffleOptions, &test.MaxAttempts, &test.CooldownSeconds,
+		&test.TimeLimitSeconds, &test.ScoringPolicy, &test.LateSubmission, &test.ReviewPolicy,
 		&test.CreatedAt, &test.UpdatedAt,
 	)
 
@@ -53,18 +67,33 @@ func (r *TestRepository) GetByLessonID(ctx context.Context, lessonID uuid.UUID)
 		return nil, err
 	}
 
+	if err := json.Unmarshal(quotasJSON, &test.TagQuotas); err != nil {
+		return nil, err
+	}
+
 	return test, nil
 }
 
 func (r *TestRepository) Update(ctx context.Context, test *models.Test) error {
 	query := `
 		UPDATE tests
-		SET passing_score = $1, updated_at = NOW()
-		WHERE id = $2 AND lesson_id = $3
+		SET passing_score = $1, question_count = $2, tag_quotas = $3,
+			shuffle_questions = $4, shuffle_options = $5, max_attempts = $6,
+			cooldown_seconds = $7, time_limit_seconds = $8, scoring_policy = $9,
+			late_submission = $10, review_policy = $11, updated_at = NOW()
+		WHERE id = $12 AND lesson_id = $13
 	`
 
+	quotasJSON, err := jsonArray(test.TagQuotas)
+	if err != nil {
+		return err
+	}
+
 	result, err := r.db.ExecContext(ctx, query,
-		test.PassingScore, test.ID, test.LessonID,
+		test.PassingScore, test.QuestionCount, quotasJSON,
+		test.ShuffleQuestions, test.ShuffleOptions, test.MaxAttempts, test.CooldownSeconds,
+		test.TimeLimitSeconds, test.ScoringPolicy, test.LateSubmission, test.ReviewPolicy,
+		test.ID, test.LessonID,
 	)
 	if err != nil {
 		return err
@@ -103,24 +132,88 @@ func (r *TestRepository) Delete(ctx context.Context, id uuid.UUID) error {
 }
 
 // Методы для работы с вопросами
+
+const questionColumns = `
+	id, test_id, type, question_text, options, matches, points,
+	explanation, answer_key, tags, created_at, updated_at
+`
+
+// scanQuestion читает вопрос и разбирает его JSON-поля
+func scanQuestion(row scanner) (*models.Question, error) {
+	question := &models.Question{}
+	var optionsJSON, matchesJSON, keyJSON, tagsJSON []byte
+	err := row.Scan(
+		&question.ID, &question.TestID, &question.Type, &question.QuestionText,
+		&optionsJSON, &matchesJSON, &question.Points, &question.Explanation,
+		&keyJSON, &tagsJSON, &question.CreatedAt, &question.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range []struct {
+		data []byte
+		dest any
+	}{
+		{optionsJSON, &question.Options},
+		{matchesJSON, &question.Matches},
+		{keyJSON, &question.Key},
+		{tagsJSON, &question.Tags},
+	} {
+		if field.data == nil {
+			continue
+		}
+		if err := json.Unmarshal(field.data, field.dest); err != nil {
+			return nil, err
+		}
+	}
+
+	return question, nil
+}
+
+// questionJSON сериализует JSON-поля вопроса: варианты, правую колонку, правильный ответ и теги
+func questionJSON(question *models.Question) (options, matches, key, tags []byte, err error) {
+	if options, err = json.Marshal(question.Options); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if matches, err = json.Marshal(question.Matches); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if key, err = json.Marshal(question.Key); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if tags, err = jsonArray(question.Tags); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return options, matches, key, tags, nil
+}
+
+// jsonArray сериализует срез в JSON-массив; nil сохраняется как [], а не null
+func jsonArray[T any](values []T) ([]byte, error) {
+	if values == nil {
+		values = []T{}
+	}
+	return json.Marshal(values)
+}
+
 func (r *TestRepository) CreateQuestion(ctx context.Context, question *models.Question) error {
 	query := `
 		INSERT INTO questions (
-			id, test_id, question_text, options, correct_answer,
-			created_at, updated_at
+			id, test_id, type, question_text, options, matches, points,
+			explanation, answer_key, tags, created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, NOW(), NOW()
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW()
 		)
 	`
 
-	optionsJSON, err := json.Marshal(question.Options)
+	optionsJSON, matchesJSON, keyJSON, tagsJSON, err := questionJSON(question)
 	if err != nil {
 		return err
 	}
 
 	_, err = r.db.ExecContext(ctx, query,
-		question.ID, question.TestID, question.QuestionText,
-		optionsJSON, question.CorrectAnswer,
+		question.ID, question.TestID, question.Type, question.QuestionText,
+		optionsJSON, matchesJSON, question.Points, question.Explanation, keyJSON, tagsJSON,
 	)
 
 	return err
@@ -128,8 +221,7 @@ func (r *TestRepository) CreateQuestion(ctx context.Context, question *models.Qu
 
 func (r *TestRepository) GetQuestions(ctx context.Context, testID uuid.UUID) ([]*models.Question, error) {
 	query := `
-		SELECT id, test_id, question_text, options, correct_answer,
-			   created_at, updated_at
+		SELECT ` + questionColumns + `
 		FROM questions
 		WHERE test_id = $1
 		ORDER BY created_at
@@ -143,43 +235,46 @@ func (r *TestRepository) GetQuestions(ctx context.Context, testID uuid.UUID) ([]
 
 	var questions []*models.Question
 	for rows.Next() {
-		question := &models.Question{}
-		var optionsJSON []byte
-		err := rows.Scan(
-			&question.ID, &question.TestID, &question.QuestionText,
-			&optionsJSON, &question.CorrectAnswer, &question.CreatedAt,
-			&question.UpdatedAt,
-		)
+		question, err := scanQuestion(rows)
 		if err != nil {
 			return nil, err
 		}
+		questions = append(questions, question)
+	}
 
-		err = json.Unmarshal(optionsJSON, &question.Options)
-		if err != nil {
-			return nil, err
-		}
+	return questions, rows.Err()
+}
 
-		questions = append(questions, question)
+func (r *TestRepository) GetQuestion(ctx context.Context, id uuid.UUID) (*models.Question, error) {
+	query := `SELECT ` + questionColumns + ` FROM questions WHERE id = $1`
+
+	question, err := scanQuestion(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return questions, nil
+	return question, nil
 }
 
 func (r *TestRepository) UpdateQuestion(ctx context.Context, question *models.Question) error {
 	query := `
 		UPDATE questions
-		SET question_text = $1, options = $2, correct_answer = $3,
-			updated_at = NOW()
-		WHERE id = $4 AND test_id = $5
+		SET type = $1, question_text = $2, options = $3, matches = $4,
+			points = $5, explanation = $6, answer_key = $7, tags = $8, updated_at = NOW()
+		WHERE id = $9 AND test_id = $10
 	`
 
-	optionsJSON, err := json.Marshal(question.Options)
+	optionsJSON, matchesJSON, keyJSON, tagsJSON, err := questionJSON(question)
 	if err != nil {
 		return err
 	}
 
 	result, err := r.db.ExecContext(ctx, query,
-		question.QuestionText, optionsJSON, question.CorrectAnswer,
+		question.Type, question.QuestionText, optionsJSON, matchesJSON,
+		question.Points, question.Explanation, keyJSON, tagsJSON,
 		question.ID, question.TestID,
 	)
 	if err != nil {
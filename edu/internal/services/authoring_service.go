@@ -0,0 +1,459 @@
+package services
+
+import (
+	"context"
+	"course2/internal/grading"
+	"course2/internal/models"
+	"course2/internal/repositories"
+	"platform/pagination"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Editor - пользователь, который изменяет курс.
+// Автор работает только со своими курсами, администратор - с любыми.
+type Editor struct {
+	UserID uuid.UUID
+	Admin  bool
+}
+
+// AuthoringService реализует кабинет автора: черновики курсов, их содержимое и отправку на проверку
+type AuthoringService struct {
+	courseRepo        *repositories.CourseRepository
+	lessonRepo        *repositories.LessonRepository
+	testRepo          *repositories.TestRepository
+	attemptRepo       *repositories.AttemptRepository
+	courseService     *CourseService
+	moderationService *ModerationService
+}
+
+func NewAuthoringService(
+	courseRepo *repositories.CourseRepository,
+	lessonRepo *repositories.LessonRepository,
+	testRepo *repositories.TestRepository,
+	attemptRepo *repositories.AttemptRepository,
+	courseService *CourseService,
+	moderationService *ModerationService,
+) *AuthoringService {
+	return &AuthoringService{
+		courseRepo:        courseRepo,
+		lessonRepo:        lessonRepo,
+		testRepo:          testRepo,
+		attemptRepo:       attemptRepo,
+		courseService:     courseService,
+		moderationService: moderationService,
+	}
+}
+
+// ListCourses возвращает курсы автора во всех статусах
+func (s *AuthoringService) ListCourses(ctx context.Context, editor Editor, page pagination.Params) (*pagination.Page[*models.Course], error) {
+	return s.courseRepo.ListByAuthor(ctx, editor.UserID, page)
+}
+
+// CreateDraft создает черновик курса от имени автора
+func (s *AuthoringService) CreateDraft(ctx context.Context, course *models.Course, editor Editor) error {
+	course.ID = uuid.New()
+	course.CreatedBy = editor.UserID
+	course.Status = models.CourseStatusDraft
+	course.Rating = 0
+	course.StudentsCount = 0
+	if course.Progression == "" {
+		course.Progression = models.ProgressionFree
+	}
+	return s.courseRepo.Create(ctx, course)
+}
+
+// GetCourse возвращает курс автора в любом статусе
+func (s *AuthoringService) GetCourse(ctx context.Context, courseID uuid.UUID, editor Editor) (*models.Course, error) {
+	return s.ownCourse(ctx, courseID, editor)
+}
+
+// UpdateCourse изменяет описание курса. Статус, автор и статистика курса не меняются.
+func (s *AuthoringService) UpdateCourse(ctx context.Context, course *models.Course, editor Editor) error {
+	existing, err := s.editableCourse(ctx, course.ID, editor)
+	if err != nil {
+		return err
+	}
+
+	course.Status = existing.Status
+	course.CreatedBy = existing.CreatedBy
+	course.ReviewerID = existing.ReviewerID
+	course.Rating = existing.Rating
+	course.StudentsCount = existing.StudentsCount
+	course.CreatedAt = existing.CreatedAt
+	if course.Progression == "" {
+		course.Progression = existing.Progression
+	}
+	return s.courseRepo.Update(ctx, course)
+}
+
+// DeleteCourse удаляет курс, который еще не опубликован
+func (s *AuthoringService) DeleteCourse(ctx context.Context, courseID uuid.UUID, editor Editor) error {
+	if _, err := s.editableCourse(ctx, courseID, editor); err != nil {
+		return err
+	}
+	return s.courseRepo.Delete(ctx, courseID)
+}
+
+// SubmitForReview отправляет черновик или отклоненный курс на проверку
+func (s *AuthoringService) SubmitForReview(ctx context.Context, courseID uuid.UUID, editor Editor) (*models.Course, error) {
+	course, err := s.ownCourse(ctx, courseID, editor)
+	if err != nil {
+		return nil, err
+	}
+
+	lessons, err := s.lessonRepo.ListByCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if len(lessons) == 0 {
+		return nil, ErrCourseHasNoLessons
+	}
+
+	if err := s.moderationService.SubmitCourse(ctx, course, editor.UserID); err != nil {
+		return nil, err
+	}
+	return course, nil
+}
+
+// ListEvents возвращает историю модерации курса без внутренних заметок проверяющих
+func (s *AuthoringService) ListEvents(ctx context.Context, courseID uuid.UUID, editor Editor, page pagination.Params) (*pagination.Page[*models.ModerationEvent], error) {
+	if _, err := s.ownCourse(ctx, courseID, editor); err != nil {
+		return nil, err
+	}
+
+	events, err := s.moderationService.ListEvents(ctx, courseID, page)
+	if err != nil {
+		return nil, err
+	}
+	if !editor.Admin {
+		for _, event := range events.Items {
+			event.Note = ""
+		}
+	}
+	return events, nil
+}
+
+// Методы для работы с уроками курса
+
+// ListLessons возвращает уроки курса автора вместе с предварительными уроками
+func (s *AuthoringService) ListLessons(ctx context.Context, courseID uuid.UUID, editor Editor) ([]*models.Lesson, error) {
+	if _, err := s.ownCourse(ctx, courseID, editor); err != nil {
+		return nil, err
+	}
+
+	lessons, err := s.lessonRepo.ListByCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	prerequisites, err := s.lessonRepo.ListPrerequisites(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	for _, lesson := range lessons {
+		lesson.Prerequisites = prerequisites[lesson.ID]
+	}
+	return lessons, nil
+}
+
+func (s *AuthoringService) AddLesson(ctx context.Context, lesson *models.Lesson, editor Editor) error {
+	if _, err := s.editableCourse(ctx, lesson.CourseID, editor); err != nil {
+		return err
+	}
+	return s.courseService.AddLesson(ctx, lesson)
+}
+
+func (s *AuthoringService) UpdateLesson(ctx context.Context, lesson *models.Lesson, editor Editor) error {
+	if _, err := s.editableLesson(ctx, lesson.CourseID, lesson.ID, editor); err != nil {
+		return err
+	}
+	return s.courseService.UpdateLesson(ctx, lesson)
+}
+
+func (s *AuthoringService) DeleteLesson(ctx context.Context, courseID, lessonID uuid.UUID, editor Editor) error {
+	if _, err := s.editableLesson(ctx, courseID, lessonID, editor); err != nil {
+		return err
+	}
+	return s.courseService.DeleteLesson(ctx, lessonID)
+}
+
+// SetPrerequisites задает уроки, которые нужно пройти до урока lessonID. Предварительные уроки
+// должны быть из того же курса и не должны образовывать цикл. Учитываются при порядке прохождения
+// prerequisites, при других порядках сохраняются, но не проверяются.
+func (s *AuthoringService) SetPrerequisites(ctx context.Context, courseID, lessonID uuid.UUID, prerequisiteIDs []uuid.UUID, editor Editor) ([]uuid.UUID, error) {
+	if _, err := s.editableLesson(ctx, courseID, lessonID, editor); err != nil {
+		return nil, err
+	}
+
+	lessons, err := s.lessonRepo.ListByCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	inCourse := make(map[uuid.UUID]bool, len(lessons))
+	for _, lesson := range lessons {
+		inCourse[lesson.ID] = true
+	}
+
+	ids := make([]uuid.UUID, 0, len(prerequisiteIDs))
+	for _, id := range prerequisiteIDs {
+		if id == lessonID || !inCourse[id] {
+			return nil, ErrInvalidPrerequisite
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	prerequisites, err := s.lessonRepo.ListPrerequisites(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	prerequisites[lessonID] = ids
+	if reachable(prerequisites, ids, lessonID) {
+		return nil, ErrPrerequisiteCycle
+	}
+
+	if err := s.lessonRepo.SetPrerequisites(ctx, lessonID, ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// reachable проверяет, ведет ли цепочка предварительных уроков от from к target
+func reachable(prerequisites map[uuid.UUID][]uuid.UUID, from []uuid.UUID, target uuid.UUID) bool {
+	visited := make(map[uuid.UUID]bool)
+	stack := slices.Clone(from)
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == target {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		stack = append(stack, prerequisites[id]...)
+	}
+	return false
+}
+
+// Методы для работы с тестом урока
+
+// GetTest возвращает тест урока вместе с правильными ответами
+func (s *AuthoringService) GetTest(ctx context.Context, courseID, lessonID uuid.UUID, editor Editor) (*models.TestContent, error) {
+	if _, err := s.ownCourse(ctx, courseID, editor); err != nil {
+		return nil, err
+	}
+	if _, err := s.courseLesson(ctx, courseID, lessonID); err != nil {
+		return nil, err
+	}
+
+	test, err := s.lessonTest(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := s.testRepo.GetQuestions(ctx, test.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TestContent{Test: test, Questions: questions}, nil
+}
+
+// ItemAnalysis возвращает анализ вопросов теста урока по последним проверенным попыткам студентов
+func (s *AuthoringService) ItemAnalysis(ctx context.Context, courseID, lessonID uuid.UUID, editor Editor) (*models.ItemAnalysis, error) {
+	if _, err := s.ownCourse(ctx, courseID, editor); err != nil {
+		return nil, err
+	}
+	if _, err := s.courseLesson(ctx, courseID, lessonID); err != nil {
+		return nil, err
+	}
+
+	test, err := s.lessonTest(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := s.testRepo.GetQuestions(ctx, test.ID)
+	if err != nil {
+		return nil, err
+	}
+	attempts, total, err := s.attemptRepo.ListGraded(ctx, test.ID, itemAnalysisAttempts)
+	if err != nil {
+		return nil, err
+	}
+	return analyzeItems(test, questions, attempts, total), nil
+}
+
+func (s *AuthoringService) AddTest(ctx context.Context, courseID uuid.UUID, test *models.Test, editor Editor) error {
+	if test.ScoringPolicy == "" {
+		test.ScoringPolicy = models.ScoringBest
+	}
+	if test.LateSubmission == "" {
+		test.LateSubmission = models.LateReject
+	}
+	if test.ReviewPolicy == "" {
+		test.ReviewPolicy = models.ReviewNever
+	}
+	if err := ValidateTestSettings(test); err != nil {
+		return err
+	}
+	if _, err := s.editableLesson(ctx, courseID, test.LessonID, editor); err != nil {
+		return err
+	}
+
+	existing, err := s.testRepo.GetByLessonID(ctx, test.LessonID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrTestAlreadyExists
+	}
+	return s.courseService.AddTest(ctx, test)
+}
+
+func (s *AuthoringService) UpdateTest(ctx context.Context, courseID uuid.UUID, test *models.Test, editor Editor) error {
+	existing, err := s.editableTest(ctx, courseID, test.LessonID, editor)
+	if err != nil {
+		return err
+	}
+
+	if test.ScoringPolicy == "" {
+		test.ScoringPolicy = existing.ScoringPolicy
+	}
+	if test.LateSubmission == "" {
+		test.LateSubmission = existing.LateSubmission
+	}
+	if test.ReviewPolicy == "" {
+		test.ReviewPolicy = existing.ReviewPolicy
+	}
+	if err := ValidateTestSettings(test); err != nil {
+		return err
+	}
+
+	test.ID = existing.ID
+	test.CreatedAt = existing.CreatedAt
+	return s.courseService.UpdateTest(ctx, test)
+}
+
+func (s *AuthoringService) DeleteTest(ctx context.Context, courseID, lessonID uuid.UUID, editor Editor) error {
+	test, err := s.editableTest(ctx, courseID, lessonID, editor)
+	if err != nil {
+		return err
+	}
+	return s.courseService.DeleteTest(ctx, test.ID)
+}
+
+// Методы для работы с вопросами теста
+
+func (s *AuthoringService) AddQuestion(ctx context.Context, courseID, lessonID uuid.UUID, question *models.Question, editor Editor) error {
+	test, err := s.editableTest(ctx, courseID, lessonID, editor)
+	if err != nil {
+		return err
+	}
+	if err := grading.ValidateQuestion(question); err != nil {
+		return err
+	}
+
+	question.TestID = test.ID
+	return s.courseService.AddQuestion(ctx, question)
+}
+
+func (s *AuthoringService) UpdateQuestion(ctx context.Context, courseID, lessonID uuid.UUID, question *models.Question, editor Editor) error {
+	test, err := s.editableTest(ctx, courseID, lessonID, editor)
+	if err != nil {
+		return err
+	}
+	if err := grading.ValidateQuestion(question); err != nil {
+		return err
+	}
+
+	question.TestID = test.ID
+	return s.courseService.UpdateQuestion(ctx, question)
+}
+
+func (s *AuthoringService) DeleteQuestion(ctx context.Context, courseID, lessonID, questionID uuid.UUID, editor Editor) error {
+	test, err := s.editableTest(ctx, courseID, lessonID, editor)
+	if err != nil {
+		return err
+	}
+
+	question, err := s.testRepo.GetQuestion(ctx, questionID)
+	if err != nil {
+		return err
+	}
+	if question == nil || question.TestID != test.ID {
+		return ErrQuestionNotFound
+	}
+	return s.courseService.DeleteQuestion(ctx, questionID)
+}
+
+// ownCourse возвращает курс, если editor - его автор или администратор
+func (s *AuthoringService) ownCourse(ctx context.Context, courseID uuid.UUID, editor Editor) (*models.Course, error) {
+	course, err := s.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+	if !editor.Admin && course.CreatedBy != editor.UserID {
+		return nil, ErrInsufficientPermissions
+	}
+	return course, nil
+}
+
+// editableCourse возвращает курс, который editor может изменять.
+// Автор изменяет только черновики и отклоненные курсы, администратор - курсы в любом статусе.
+func (s *AuthoringService) editableCourse(ctx context.Context, courseID uuid.UUID, editor Editor) (*models.Course, error) {
+	course, err := s.ownCourse(ctx, courseID, editor)
+	if err != nil {
+		return nil, err
+	}
+	if !editor.Admin && course.Status != models.CourseStatusDraft && course.Status != models.CourseStatusRejected {
+		return nil, ErrCourseNotEditable
+	}
+	return course, nil
+}
+
+// editableLesson возвращает урок изменяемого курса
+func (s *AuthoringService) editableLesson(ctx context.Context, courseID, lessonID uuid.UUID, editor Editor) (*models.Lesson, error) {
+	if _, err := s.editableCourse(ctx, courseID, editor); err != nil {
+		return nil, err
+	}
+	return s.courseLesson(ctx, courseID, lessonID)
+}
+
+// editableTest возвращает тест урока изменяемого курса
+func (s *AuthoringService) editableTest(ctx context.Context, courseID, lessonID uuid.UUID, editor Editor) (*models.Test, error) {
+	if _, err := s.editableLesson(ctx, courseID, lessonID, editor); err != nil {
+		return nil, err
+	}
+	return s.lessonTest(ctx, lessonID)
+}
+
+// courseLesson возвращает урок, если он принадлежит курсу
+func (s *AuthoringService) courseLesson(ctx context.Context, courseID, lessonID uuid.UUID) (*models.Lesson, error) {
+	lesson, err := s.lessonRepo.GetByID(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson == nil || lesson.CourseID != courseID {
+		return nil, ErrLessonNotFound
+	}
+	return lesson, nil
+}
+
+// lessonTest возвращает тест урока
+func (s *AuthoringService) lessonTest(ctx context.Context, lessonID uuid.UUID) (*models.Test, error) {
+	test, err := s.testRepo.GetByLessonID(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if test == nil {
+		return nil, ErrTestNotFound
+	}
+	return test, nil
+}
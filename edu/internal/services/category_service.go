@@ -4,6 +4,7 @@ import (
 	"context"
 	"course2/internal/models"
 	"course2/internal/repositories"
+	"platform/pagination"
 )
 
 type CategoryService struct {
@@ -16,6 +17,6 @@ func NewCategoryService(categoryRepo *repositories.CategoryRepository) *Category
 	}
 }
 
-func (s *CategoryService) ListCategories(ctx context.Context) ([]*models.Category, error) {
-	return s.categoryRepo.List(ctx)
+func (s *CategoryService) ListCategories(ctx context.Context, page pagination.Params) (*pagination.Page[*models.Category], error) {
+	return s.categoryRepo.List(ctx, page)
 }
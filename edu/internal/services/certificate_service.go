@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"course2/internal/certificate"
+	"course2/internal/models"
+	"course2/internal/repositories"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"platform/pagination"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// serialAlphabet - алфавит Crockford base32 без похожих символов I, L, O и U
+const serialAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+type CertificateService struct {
+	certificateRepo *repositories.CertificateRepository
+	userRepo        *repositories.UserRepository
+	courseRepo      *repositories.CourseRepository
+	purchaseRepo    *repositories.PurchaseRepository
+	// verifyURL - адрес публичной проверки, к которому добавляется серийный номер
+	verifyURL string
+}
+
+func NewCertificateService(
+	certificateRepo *repositories.CertificateRepository,
+	userRepo *repositories.UserRepository,
+	courseRepo *repositories.CourseRepository,
+	purchaseRepo *repositories.PurchaseRepository,
+	verifyURL string,
+) *CertificateService {
+	return &CertificateService{
+		certificateRepo: certificateRepo,
+		userRepo:        userRepo,
+		courseRepo:      courseRepo,
+		purchaseRepo:    purchaseRepo,
+		verifyURL:       verifyURL,
+	}
+}
+
+// CourseCompleted выдает сертификат при завершении курса. Подписывается на ProgressService.OnCourseCompleted.
+func (s *CertificateService) CourseCompleted(ctx context.Context, completion *models.CourseCompletion) error {
+	_, err := s.issue(ctx, completion.UserID, completion.CourseID, completion.CourseTitle, completion.CompletedAt)
+	return err
+}
+
+// GetForCourse возвращает сертификат пользователя по курсу. Если курс завершен, а сертификата нет
+// (например, курс завершен до появления сертификатов или выдача не удалась), сертификат выдается сейчас.
+func (s *CertificateService) GetForCourse(ctx context.Context, userID, courseID uuid.UUID) (*models.Certificate, error) {
+	existing, err := s.certificateRepo.GetByUserCourse(ctx, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	completedAt, err := s.purchaseRepo.GetCompletedAt(ctx, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if completedAt == nil {
+		return nil, ErrCourseNotCompleted
+	}
+
+	course, err := s.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+
+	return s.issue(ctx, userID, courseID, course.Title, *completedAt)
+}
+
+// ListMine возвращает сертификаты пользователя
+func (s *CertificateService) ListMine(ctx context.Context, userID uuid.UUID, page pagination.Params) (*pagination.Page[*models.Certificate], error) {
+	return s.certificateRepo.ListByUser(ctx, userID, page)
+}
+
+// Verify проверяет подлинность сертификата по серийному номеру
+func (s *CertificateService) Verify(ctx context.Context, serial string) (*models.CertificateVerification, error) {
+	cert, err := s.getBySerial(ctx, serial)
+	if err != nil {
+		return nil, err
+	}
+
+	return newVerification(cert), nil
+}
+
+// newVerification собирает публичный результат проверки сертификата
+func newVerification(cert *models.Certificate) *models.CertificateVerification {
+	return &models.CertificateVerification{
+		Serial:       cert.Serial,
+		Valid:        cert.RevokedAt == nil,
+		StudentName:  cert.StudentName,
+		CourseTitle:  cert.CourseTitle,
+		CompletedAt:  cert.CompletedAt,
+		IssuedAt:     cert.IssuedAt,
+		RevokedAt:    cert.RevokedAt,
+		RevokeReason: cert.RevokeReason,
+	}
+}
+
+// WritePDF пишет PDF сертификата в w. Скачать PDF может владелец сертификата или администратор,
+// отозванный сертификат не выдается.
+func (s *CertificateService) WritePDF(ctx context.Context, serial string, userID uuid.UUID, role string, w io.Writer) error {
+	cert, err := s.getBySerial(ctx, serial)
+	if err != nil {
+		return err
+	}
+	if cert.UserID != userID && role != "admin" {
+		return ErrCertificateNotFound
+	}
+	if cert.RevokedAt != nil {
+		return ErrCertificateRevoked
+	}
+
+	return certificate.WritePDF(w, cert, s.VerifyURL(cert.Serial))
+}
+
+// Revoke отзывает сертификат. Повторный отзыв не меняет причину и дату первого.
+func (s *CertificateService) Revoke(ctx context.Context, serial string, adminID uuid.UUID, reason string) (*models.Certificate, error) {
+	cert, err := s.getBySerial(ctx, serial)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.certificateRepo.Revoke(ctx, cert, adminID, reason)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		return nil, ErrCertificateRevoked
+	}
+	return cert, nil
+}
+
+// VerifyURL возвращает адрес публичной проверки сертификата
+func (s *CertificateService) VerifyURL(serial string) string {
+	return s.verifyURL + serial
+}
+
+func (s *CertificateService) getBySerial(ctx context.Context, serial string) (*models.Certificate, error) {
+	cert, err := s.certificateRepo.GetBySerial(ctx, strings.ToUpper(strings.TrimSpace(serial)))
+	if err != nil {
+		return nil, err
+	}
+	if cert == nil {
+		return nil, ErrCertificateNotFound
+	}
+	return cert, nil
+}
+
+// issue создает сертификат, если у пользователя его еще нет, и возвращает действующую запись.
+// Имя студента и название курса фиксируются на момент выдачи.
+func (s *CertificateService) issue(ctx context.Context, userID, courseID uuid.UUID, courseTitle string, completedAt time.Time) (*models.Certificate, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("пользователь %s не найден", userID)
+	}
+
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	cert := &models.Certificate{
+		ID:          uuid.New(),
+		Serial:      serial,
+		UserID:      userID,
+		CourseID:    courseID,
+		StudentName: studentName(user),
+		CourseTitle: courseTitle,
+		CompletedAt: completedAt,
+	}
+
+	created, err := s.certificateRepo.Create(ctx, cert)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		// Сертификат уже выдан, например параллельным запросом
+		return s.certificateRepo.GetByUserCourse(ctx, userID, courseID)
+	}
+	return cert, nil
+}
+
+// studentName собирает имя для сертификата из имени и фамилии. Если они не заполнены, в сертификат
+// попадает models.CertificateAnonymousName: имя видно любому, у кого есть серийный номер.
+func studentName(user *models.User) string {
+	var parts []string
+	for _, part := range []*string{user.FirstName, user.LastName} {
+		if part != nil && strings.TrimSpace(*part) != "" {
+			parts = append(parts, strings.TrimSpace(*part))
+		}
+	}
+	if len(parts) == 0 {
+		return models.CertificateAnonymousName
+	}
+	return strings.Join(parts, " ")
+}
+
+// newSerial генерирует серийный номер вида EDU-7K3M-QX9P-2D4F (60 случайных бит)
+func newSerial() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("EDU")
+	for i, c := range buf {
+		if i%4 == 0 {
+			b.WriteByte('-')
+		}
+		b.WriteByte(serialAlphabet[c%byte(len(serialAlphabet))])
+	}
+	return b.String(), nil
+}
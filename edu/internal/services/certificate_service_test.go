@@ -0,0 +1,57 @@
+package services
+
+import (
+	"course2/internal/models"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestStudentName(t *testing.T) {
+	first, last, blank := "Анна", "Иванова", "  "
+
+	tests := []struct {
+		name string
+		user *models.User
+		want string
+	}{
+		{"имя и фамилия", &models.User{FirstName: &first, LastName: &last}, "Анна Иванова"},
+		{"только имя", &models.User{FirstName: &first}, "Анна"},
+		{"только фамилия", &models.User{LastName: &last}, "Иванова"},
+		{"без имени", &models.User{Email: "anna@example.com"}, models.CertificateAnonymousName},
+		{"пустое имя", &models.User{Email: "anna@example.com", FirstName: &blank, LastName: &blank}, models.CertificateAnonymousName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := studentName(tt.user); got != tt.want {
+				t.Errorf("studentName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerificationHidesEmail(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "anna@example.com"}
+	cert := &models.Certificate{
+		ID:          uuid.New(),
+		Serial:      "EDU-7K3M-QX9P-2D4F",
+		UserID:      user.ID,
+		CourseID:    uuid.New(),
+		StudentName: studentName(user),
+		CourseTitle: "Основы Go",
+		CompletedAt: time.Now(),
+		IssuedAt:    time.Now(),
+	}
+
+	body, err := json.Marshal(newVerification(cert))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(body), user.Email) {
+		t.Errorf("публичная проверка сертификата содержит email: %s", body)
+	}
+}
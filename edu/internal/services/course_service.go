@@ -4,47 +4,105 @@ import (
 	"context"
 	"course2/internal/models"
 	"course2/internal/repositories"
+	"platform/pagination"
 
 	"github.com/google/uuid"
 )
 
 type CourseService struct {
-	courseRepo *repositories.CourseRepository
-	lessonRepo *repositories.LessonRepository
-	testRepo   *repositories.TestRepository
-	reviewRepo *repositories.ReviewRepository
+	courseRepo   *repositories.CourseRepository
+	lessonRepo   *repositories.LessonRepository
+	testRepo     *repositories.TestRepository
+	purchaseRepo *repositories.PurchaseRepository
 }
 
 func NewCourseService(
 	courseRepo *repositories.CourseRepository,
 	lessonRepo *repositories.LessonRepository,
 	testRepo *repositories.TestRepository,
-	reviewRepo *repositories.ReviewRepository,
+	purchaseRepo *repositories.PurchaseRepository,
 ) *CourseService {
 	return &CourseService{
-		courseRepo: courseRepo,
-		lessonRepo: lessonRepo,
-		testRepo:   testRepo,
-		reviewRepo: reviewRepo,
+		courseRepo:   courseRepo,
+		lessonRepo:   lessonRepo,
+		testRepo:     testRepo,
+		purchaseRepo: purchaseRepo,
 	}
 }
 
+// GetCourse возвращает опубликованный курс. Неопубликованные курсы видны только в кабинете автора.
 func (s *CourseService) GetCourse(ctx context.Context, id uuid.UUID) (*models.Course, error) {
-	return s.courseRepo.GetByID(ctx, id)
+	course, err := s.courseRepo.GetByID(ctx, id)
+	if err != nil || course == nil {
+		return nil, err
+	}
+	if course.Status != models.CourseStatusPublished {
+		return nil, nil
+	}
+	return course, nil
+}
+
+// GetCourseForStudent возвращает курс, доступный студенту: опубликованный или купленный им раньше.
+// Курс, снятый с публикации, остается доступен купившим его.
+// Для гостя userID равен uuid.Nil.
+func (s *CourseService) GetCourseForStudent(ctx context.Context, userID, courseID uuid.UUID) (*models.Course, error) {
+	course, err := s.courseRepo.GetByID(ctx, courseID)
+	if err != nil || course == nil {
+		return nil, err
+	}
+
+	purchased := false
+	if course.Status != models.CourseStatusPublished && userID != uuid.Nil {
+		purchased, err = s.purchaseRepo.HasPurchased(ctx, userID, courseID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !visibleToStudent(course, purchased) {
+		return nil, nil
+	}
+	return course, nil
 }
 
-func (s *CourseService) ListCourses(ctx context.Context, page, limit int) ([]*models.Course, error) {
-	offset := (page - 1) * limit
-	return s.courseRepo.List(ctx, offset, limit)
+// SearchCourses ищет опубликованные курсы в каталоге. Фасеты для боковой панели фильтров
+// считаются только для первой страницы: на следующих страницах они не меняются.
+func (s *CourseService) SearchCourses(ctx context.Context, filter *models.CourseFilter, sort models.CourseSort, page pagination.Params) (*models.CatalogPage, error) {
+	courses, err := s.courseRepo.Search(ctx, models.CourseStatusPublished, filter, sort, page)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.CatalogPage{Page: *courses}
+	if page.First() {
+		result.Facets, err = s.courseRepo.Facets(ctx, models.CourseStatusPublished, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
 }
 
-func (s *CourseService) ListCoursesByCategory(ctx context.Context, categoryID uuid.UUID, page, limit int) ([]*models.Course, error) {
-	offset := (page - 1) * limit
-	return s.courseRepo.ListByCategory(ctx, categoryID, offset, limit)
+// ListCoursesByCategory возвращает опубликованные курсы категории, сначала новые
+func (s *CourseService) ListCoursesByCategory(ctx context.Context, categoryID uuid.UUID, page pagination.Params) (*pagination.Page[*models.Course], error) {
+	filter := &models.CourseFilter{CategoryIDs: []uuid.UUID{categoryID}}
+	return s.courseRepo.Search(ctx, models.CourseStatusPublished, filter, models.CourseSortNewest, page)
 }
 
 // Дополнительные методы для работы с уроками
+
+// AddLesson добавляет урок в курс. Без указанного номера урок добавляется в конец курса.
 func (s *CourseService) AddLesson(ctx context.Context, lesson *models.Lesson) error {
+	if lesson.OrderNum == 0 {
+		lessons, err := s.lessonRepo.ListByCourse(ctx, lesson.CourseID)
+		if err != nil {
+			return err
+		}
+		for _, existing := range lessons {
+			lesson.OrderNum = max(lesson.OrderNum, existing.OrderNum)
+		}
+		lesson.OrderNum++
+	}
+
 	lesson.ID = uuid.New()
 	return s.lessonRepo.Create(ctx, lesson)
 }
@@ -100,3 +158,8 @@ func (s *CourseService) GetLessons(ctx context.Context, courseID uuid.UUID) ([]*
 func (s *CourseService) GetLesson(ctx context.Context, lessonID uuid.UUID) (*models.Lesson, error) {
 	return s.lessonRepo.GetByID(ctx, lessonID)
 }
+
+// visibleToStudent сообщает, виден ли курс студенту: опубликованный курс виден всем, остальные - только купившим
+func visibleToStudent(course *models.Course, purchased bool) bool {
+	return course.Status == models.CourseStatusPublished || purchased
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"course2/internal/models"
+	"testing"
+)
+
+func TestVisibleToStudent(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		purchased bool
+		want      bool
+	}{
+		{"опубликованный курс виден всем", models.CourseStatusPublished, false, true},
+		{"опубликованный купленный курс", models.CourseStatusPublished, true, true},
+		{"архивный купленный курс остается доступен", models.CourseStatusArchived, true, true},
+		{"архивный курс не виден без покупки", models.CourseStatusArchived, false, false},
+		{"черновик не виден", models.CourseStatusDraft, false, false},
+		{"отклоненный курс не виден", models.CourseStatusRejected, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			course := &models.Course{Status: tt.status}
+			if got := visibleToStudent(course, tt.purchased); got != tt.want {
+				t.Errorf("visibleToStudent(%s, %v) = %v, want %v", tt.status, tt.purchased, got, tt.want)
+			}
+		})
+	}
+}
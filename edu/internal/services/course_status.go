@@ -0,0 +1,27 @@
+package services
+
+import (
+	"course2/internal/models"
+	"fmt"
+	"slices"
+)
+
+// courseTransitions - допустимые переходы между статусами курса:
+//
+//	draft -> pending_review -> published -> archived
+//	                        -> rejected  -> pending_review
+//	                                     -> archived
+var courseTransitions = map[string][]string{
+	models.CourseStatusDraft:         {models.CourseStatusPendingReview},
+	models.CourseStatusPendingReview: {models.CourseStatusPublished, models.CourseStatusRejected},
+	models.CourseStatusRejected:      {models.CourseStatusPendingReview, models.CourseStatusArchived},
+	models.CourseStatusPublished:     {models.CourseStatusArchived},
+}
+
+// checkTransition возвращает ErrInvalidStatusTransition, если курс нельзя перевести из from в to
+func checkTransition(from, to string) error {
+	if !slices.Contains(courseTransitions[from], to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, from, to)
+	}
+	return nil
+}
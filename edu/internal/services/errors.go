@@ -7,4 +7,32 @@ var (
 	ErrCourseAlreadyPurchased  = errors.New("курс уже куплен")
 	ErrInsufficientPermissions = errors.New("недостаточно прав")
 	ErrInvalidTestScore        = errors.New("неверный результат теста")
+	ErrLessonNotFound          = errors.New("урок не найден")
+	ErrTestNotFound            = errors.New("тест не найден")
+	ErrQuestionNotFound        = errors.New("вопрос не найден")
+	ErrTestAlreadyExists       = errors.New("у урока уже есть тест")
+	ErrCourseNotEditable       = errors.New("курс нельзя изменить в текущем статусе")
+	ErrCourseHasNoLessons      = errors.New("в курсе нет уроков")
+	ErrInvalidStatusTransition = errors.New("недопустимая смена статуса курса")
+	ErrReviewerNotFound        = errors.New("проверяющий не найден")
+	ErrCourseAssigned          = errors.New("курс назначен другому проверяющему")
+	ErrCourseNotPurchased      = errors.New("курс не куплен")
+	ErrReviewNotFound          = errors.New("отзыв не найден")
+	ErrReviewAlreadyExists     = errors.New("отзыв на курс уже оставлен")
+	ErrReviewProgressRequired  = errors.New("курс пройден недостаточно для отзыва")
+	ErrOwnReview               = errors.New("действие недоступно для своего отзыва")
+	ErrCourseNotCompleted      = errors.New("курс не завершен")
+	ErrCertificateNotFound     = errors.New("сертификат не найден")
+	ErrCertificateRevoked      = errors.New("сертификат отозван")
+	ErrLessonLocked            = errors.New("урок заблокирован")
+	ErrLessonNotViewed         = errors.New("урок не просмотрен")
+	ErrInvalidPrerequisite     = errors.New("недопустимый предварительный урок")
+	ErrPrerequisiteCycle       = errors.New("предварительные уроки образуют цикл")
+	ErrInvalidTestSettings     = errors.New("неверные настройки теста")
+	ErrAttemptNotOpen          = errors.New("нет незавершенной попытки теста")
+	ErrAttemptExpired          = errors.New("время на попытку истекло")
+	ErrAttemptNotFound         = errors.New("попытка не найдена")
+	ErrOrderNotFound           = errors.New("заказ не найден")
+	ErrUnknownPaymentProvider  = errors.New("неизвестный платежный провайдер")
+	ErrPaymentMismatch         = errors.New("сумма оплаты не совпадает с заказом")
 )
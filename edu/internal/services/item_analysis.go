@@ -0,0 +1,137 @@
+package services
+
+import (
+	"course2/internal/models"
+	"math"
+	"slices"
+	"sort"
+)
+
+// discriminationGroup - доля лучших и худших попыток для индекса различения
+const discriminationGroup = 0.27
+
+// itemAnalysisAttempts - сколько последних проверенных попыток берется в анализ вопросов.
+// Ограничение держит в памяти не больше этого числа попыток с ответами.
+const itemAnalysisAttempts = 2000
+
+// analyzeItems считает показатели вопросов questions по проверенным попыткам attempts
+// из total проверенных попыток теста. Вопросы, удаленные из теста, в анализ не попадают.
+func analyzeItems(test *models.Test, questions []*models.Question, attempts []*models.TestAttempt, total int) *models.ItemAnalysis {
+	analysis := &models.ItemAnalysis{
+		TestID:        test.ID,
+		Attempts:      len(attempts),
+		TotalAttempts: total,
+		Questions:     make([]*models.QuestionAnalysis, len(questions)),
+	}
+
+	// Попытки по убыванию результата: первые group - лучшие, последние group - худшие
+	ranked := slices.Clone(attempts)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return attemptScore(ranked[i]) > attemptScore(ranked[j])
+	})
+	group := int(math.Round(discriminationGroup * float64(len(ranked))))
+
+	for i, question := range questions {
+		item := &models.QuestionAnalysis{Question: question}
+		chosen := make([]int, len(question.Options))
+		choice := question.Type == models.QuestionSingleChoice || question.Type == models.QuestionMultipleChoice
+
+		var total, upper, lower credits
+		for rank, attempt := range ranked {
+			answer := findAnswer(attempt, question)
+			if answer == nil {
+				continue
+			}
+
+			item.Responses++
+			if answer.Answer == nil {
+				item.Unanswered++
+			}
+			credit := answerCredit(answer)
+			total.add(credit)
+			if rank < group {
+				upper.add(credit)
+			}
+			if rank >= len(ranked)-group {
+				lower.add(credit)
+			}
+
+			if choice && answer.Answer != nil {
+				for _, option := range slices.Compact(slices.Sorted(slices.Values(answer.Answer.Choices))) {
+					if option >= 0 && option < len(chosen) {
+						chosen[option]++
+					}
+				}
+			}
+		}
+
+		item.Difficulty = total.mean()
+		if upper.count > 0 && lower.count > 0 {
+			discrimination := round3(*upper.mean() - *lower.mean())
+			item.Discrimination = &discrimination
+		}
+
+		if choice {
+			answered := item.Responses - item.Unanswered
+			item.Options = make([]*models.OptionAnalysis, len(question.Options))
+			for index, text := range question.Options {
+				option := &models.OptionAnalysis{
+					Index:   index,
+					Text:    text,
+					Correct: question.Key != nil && slices.Contains(question.Key.Choices, index),
+					Chosen:  chosen[index],
+				}
+				if answered > 0 {
+					option.Share = round3(float64(option.Chosen) / float64(answered))
+				}
+				item.Options[index] = option
+			}
+		}
+		analysis.Questions[i] = item
+	}
+	return analysis
+}
+
+// credits накапливает доли баллов за вопрос
+type credits struct {
+	sum   float64
+	count int
+}
+
+func (c *credits) add(credit float64) {
+	c.sum += credit
+	c.count++
+}
+
+func (c *credits) mean() *float64 {
+	if c.count == 0 {
+		return nil
+	}
+	mean := round3(c.sum / float64(c.count))
+	return &mean
+}
+
+func findAnswer(attempt *models.TestAttempt, question *models.Question) *models.AttemptAnswer {
+	index := slices.IndexFunc(attempt.Answers, func(answer *models.AttemptAnswer) bool {
+		return answer.QuestionID == question.ID
+	})
+	if index < 0 {
+		return nil
+	}
+	return attempt.Answers[index]
+}
+
+// answerCredit возвращает долю баллов за ответ. Вопрос без баллов засчитывается по верности ответа.
+func answerCredit(answer *models.AttemptAnswer) float64 {
+	if answer.MaxPoints > 0 {
+		return answer.Points / float64(answer.MaxPoints)
+	}
+	if answer.Correct {
+		return 1
+	}
+	return 0
+}
+
+func round3(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
@@ -4,82 +4,250 @@ import (
 	"context"
 	"course2/internal/models"
 	"course2/internal/repositories"
+	"fmt"
+	"platform/pagination"
 
 	"github.com/google/uuid"
 )
 
 type ModerationService struct {
-	courseRepo *repositories.CourseRepository
+	courseRepo     *repositories.CourseRepository
+	moderationRepo *repositories.ModerationRepository
+	userRepo       *repositories.UserRepository
 }
 
-func NewModerationService(courseRepo *repositories.CourseRepository) *ModerationService {
+func NewModerationService(
+	courseRepo *repositories.CourseRepository,
+	moderationRepo *repositories.ModerationRepository,
+	userRepo *repositories.UserRepository,
+) *ModerationService {
 	return &ModerationService{
-		courseRepo: courseRepo,
+		courseRepo:     courseRepo,
+		moderationRepo: moderationRepo,
+		userRepo:       userRepo,
 	}
 }
 
-func (s *ModerationService) ApproveCourse(ctx context.Context, courseID uuid.UUID) error {
-	course, err := s.courseRepo.GetByID(ctx, courseID)
+// ReviewQueueFilter отбирает курсы в очереди модерации.
+// Без условий возвращаются все курсы, ожидающие проверки.
+type ReviewQueueFilter struct {
+	// ReviewerID - только курсы, назначенные этому проверяющему
+	ReviewerID *uuid.UUID
+	// Unassigned - только курсы без проверяющего
+	Unassigned bool
+}
+
+// ListPendingCourses возвращает курсы в статусе pending_review, сначала давно ожидающие
+func (s *ModerationService) ListPendingCourses(ctx context.Context, filter ReviewQueueFilter, page pagination.Params) (*pagination.Page[*models.Course], error) {
+	return s.courseRepo.ListForReview(ctx, models.CourseStatusPendingReview, filter.ReviewerID, filter.Unassigned, page)
+}
+
+// SubmitCourse отправляет курс на проверку. Повторно отправленный курс
+// возвращается к проверяющему, который его отклонил.
+func (s *ModerationService) SubmitCourse(ctx context.Context, course *models.Course, actorID uuid.UUID) error {
+	event := &models.ModerationEvent{
+		Action:     models.ModerationActionSubmitted,
+		ToStatus:   models.CourseStatusPendingReview,
+		ReviewerID: course.ReviewerID,
+	}
+	return s.transition(ctx, course, actorID, event, nil)
+}
+
+// AssignReviewer назначает проверяющего на курс в очереди модерации
+func (s *ModerationService) AssignReviewer(ctx context.Context, courseID, reviewerID, actorID uuid.UUID) (*models.Course, error) {
+	course, err := s.getCourse(ctx, courseID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if course == nil {
-		return ErrCourseNotFound
+	if course.Status != models.CourseStatusPendingReview {
+		return nil, fmt.Errorf("%w: курс в статусе %s", ErrInvalidStatusTransition, course.Status)
 	}
 
-	course.Status = "published"
-	return s.courseRepo.Update(ctx, course)
+	reviewer, err := s.userRepo.GetByID(ctx, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	if reviewer == nil || reviewer.Role != "admin" {
+		return nil, ErrReviewerNotFound
+	}
+
+	event := &models.ModerationEvent{
+		Action:     models.ModerationActionAssigned,
+		ToStatus:   models.CourseStatusPendingReview,
+		ReviewerID: &reviewerID,
+	}
+	if err := s.record(ctx, course, actorID, event, nil); err != nil {
+		return nil, err
+	}
+	course.ReviewerID = &reviewerID
+	return course, nil
 }
 
-func (s *ModerationService) RejectCourse(ctx context.Context, courseID uuid.UUID, reason string) error {
-	course, err := s.courseRepo.GetByID(ctx, courseID)
+// ApproveCourse публикует курс и уведомляет автора
+func (s *ModerationService) ApproveCourse(ctx context.Context, courseID, reviewerID uuid.UUID, note string) error {
+	course, err := s.reviewedCourse(ctx, courseID, reviewerID)
 	if err != nil {
 		return err
 	}
-	if course == nil {
-		return ErrCourseNotFound
+
+	event := &models.ModerationEvent{
+		Action:     models.ModerationActionApproved,
+		ToStatus:   models.CourseStatusPublished,
+		ReviewerID: &reviewerID,
+		Note:       note,
 	}
+	notification := s.notify(course, reviewerID, models.NotificationCourseApproved,
+		fmt.Sprintf("Курс «%s» опубликован", course.Title), "")
+	return s.transition(ctx, course, reviewerID, event, notification)
+}
 
-	course.Status = "rejected"
-	return s.courseRepo.Update(ctx, course)
+// RejectCourse отклоняет курс. Причину получает автор, заметка остается в истории для проверяющих.
+func (s *ModerationService) RejectCourse(ctx context.Context, courseID, reviewerID uuid.UUID, reason, note string) error {
+	course, err := s.reviewedCourse(ctx, courseID, reviewerID)
+	if err != nil {
+		return err
+	}
+
+	event := &models.ModerationEvent{
+		Action:     models.ModerationActionRejected,
+		ToStatus:   models.CourseStatusRejected,
+		ReviewerID: &reviewerID,
+		Reason:     reason,
+		Note:       note,
+	}
+	notification := s.notify(course, reviewerID, models.NotificationCourseRejected,
+		fmt.Sprintf("Курс «%s» отклонен", course.Title), reason)
+	return s.transition(ctx, course, reviewerID, event, notification)
 }
 
-func (s *ModerationService) ListPendingCourses(ctx context.Context, page, limit int) ([]*models.Course, error) {
-	offset := (page - 1) * limit
-	return s.courseRepo.List(ctx, offset, limit)
+// ArchiveCourse снимает курс с публикации и уведомляет автора
+func (s *ModerationService) ArchiveCourse(ctx context.Context, courseID, actorID uuid.UUID, reason string) error {
+	course, err := s.getCourse(ctx, courseID)
+	if err != nil {
+		return err
+	}
+
+	event := &models.ModerationEvent{
+		Action:     models.ModerationActionArchived,
+		ToStatus:   models.CourseStatusArchived,
+		ReviewerID: course.ReviewerID,
+		Reason:     reason,
+	}
+	notification := s.notify(course, actorID, models.NotificationCourseArchived,
+		fmt.Sprintf("Курс «%s» снят с публикации", course.Title), reason)
+	return s.transition(ctx, course, actorID, event, notification)
 }
 
-// CreateCourse создает новый курс
-func (s *ModerationService) CreateCourse(ctx context.Context, course *models.Course) error {
+// ListEvents возвращает историю модерации курса
+func (s *ModerationService) ListEvents(ctx context.Context, courseID uuid.UUID, page pagination.Params) (*pagination.Page[*models.ModerationEvent], error) {
+	if _, err := s.getCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+	return s.moderationRepo.ListEvents(ctx, courseID, page)
+}
+
+// CreateCourse создает курс от имени администратора и сразу отправляет его на проверку
+func (s *ModerationService) CreateCourse(ctx context.Context, course *models.Course, actorID uuid.UUID) error {
 	course.ID = uuid.New()
-	course.Status = "pending" // Новые курсы создаются со статусом "pending"
-	return s.courseRepo.Create(ctx, course)
+	course.CreatedBy = actorID
+	course.Status = models.CourseStatusDraft
+	course.ReviewerID = nil
+	if err := s.courseRepo.Create(ctx, course); err != nil {
+		return err
+	}
+	return s.SubmitCourse(ctx, course, actorID)
 }
 
 // UpdateCourse обновляет существующий курс
 func (s *ModerationService) UpdateCourse(ctx context.Context, course *models.Course) error {
-	existing, err := s.courseRepo.GetByID(ctx, course.ID)
+	existing, err := s.getCourse(ctx, course.ID)
 	if err != nil {
 		return err
 	}
-	if existing == nil {
-		return ErrCourseNotFound
-	}
 
-	// Сохраняем текущий статус курса
+	// Статус и проверяющий меняются только через модерацию
 	course.Status = existing.Status
+	course.CreatedBy = existing.CreatedBy
+	course.ReviewerID = existing.ReviewerID
 	return s.courseRepo.Update(ctx, course)
 }
 
 // DeleteCourse удаляет курс
 func (s *ModerationService) DeleteCourse(ctx context.Context, courseID uuid.UUID) error {
-	existing, err := s.courseRepo.GetByID(ctx, courseID)
+	if _, err := s.getCourse(ctx, courseID); err != nil {
+		return err
+	}
+
+	return s.courseRepo.Delete(ctx, courseID)
+}
+
+func (s *ModerationService) getCourse(ctx context.Context, courseID uuid.UUID) (*models.Course, error) {
+	course, err := s.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+	return course, nil
+}
+
+// reviewedCourse возвращает курс, решение по которому может принять reviewerID:
+// курс не назначен никому или назначен ему
+func (s *ModerationService) reviewedCourse(ctx context.Context, courseID, reviewerID uuid.UUID) (*models.Course, error) {
+	course, err := s.getCourse(ctx, courseID)
 	if err != nil {
+		return nil, err
+	}
+	if course.ReviewerID != nil && *course.ReviewerID != reviewerID {
+		return nil, ErrCourseAssigned
+	}
+	return course, nil
+}
+
+// transition проверяет переход по таблице courseTransitions и сохраняет его
+func (s *ModerationService) transition(ctx context.Context, course *models.Course, actorID uuid.UUID, event *models.ModerationEvent, notification *models.Notification) error {
+	if err := checkTransition(course.Status, event.ToStatus); err != nil {
 		return err
 	}
-	if existing == nil {
-		return ErrCourseNotFound
+	if err := s.record(ctx, course, actorID, event, notification); err != nil {
+		return err
 	}
+	course.Status = event.ToStatus
+	course.ReviewerID = event.ReviewerID
+	return nil
+}
 
-	return s.courseRepo.Delete(ctx, courseID)
+// record сохраняет событие модерации вместе со сменой статуса курса.
+// Если статус курса успел измениться в другом запросе, возвращает ErrInvalidStatusTransition.
+func (s *ModerationService) record(ctx context.Context, course *models.Course, actorID uuid.UUID, event *models.ModerationEvent, notification *models.Notification) error {
+	event.ID = uuid.New()
+	event.CourseID = course.ID
+	event.ActorID = actorID
+	event.FromStatus = course.Status
+
+	changed, err := s.moderationRepo.ChangeStatus(ctx, event, notification)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return fmt.Errorf("%w: статус курса изменился", ErrInvalidStatusTransition)
+	}
+	return nil
+}
+
+// notify готовит уведомление автору курса о решении. Автор не получает уведомлений о своих действиях.
+func (s *ModerationService) notify(course *models.Course, actorID uuid.UUID, kind, title, message string) *models.Notification {
+	if course.CreatedBy == uuid.Nil || course.CreatedBy == actorID {
+		return nil
+	}
+	courseID := course.ID
+	return &models.Notification{
+		ID:       uuid.New(),
+		UserID:   course.CreatedBy,
+		Type:     kind,
+		Title:    title,
+		Message:  message,
+		CourseID: &courseID,
+	}
 }
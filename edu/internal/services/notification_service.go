@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"course2/internal/models"
+	"course2/internal/repositories"
+	"fmt"
+	"platform/pagination"
+
+	"github.com/google/uuid"
+)
+
+type NotificationService struct {
+	notificationRepo *repositories.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo *repositories.NotificationRepository) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+// ListNotifications возвращает уведомления пользователя, сначала новые
+func (s *NotificationService) ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, page pagination.Params) (*pagination.Page[*models.Notification], error) {
+	return s.notificationRepo.ListByUser(ctx, userID, unreadOnly, page)
+}
+
+// MarkRead отмечает уведомление прочитанным
+func (s *NotificationService) MarkRead(ctx context.Context, id, userID uuid.UUID) error {
+	return s.notificationRepo.MarkRead(ctx, id, userID)
+}
+
+// CourseCompleted уведомляет студента о завершении курса. Подписывается на ProgressService.OnCourseCompleted.
+func (s *NotificationService) CourseCompleted(ctx context.Context, completion *models.CourseCompletion) error {
+	courseID := completion.CourseID
+	notification := &models.Notification{
+		ID:       uuid.New(),
+		UserID:   completion.UserID,
+		Type:     models.NotificationCourseCompleted,
+		Title:    fmt.Sprintf("Курс «%s» пройден", completion.CourseTitle),
+		CourseID: &courseID,
+	}
+	if completion.XPAwarded > 0 {
+		notification.Message = fmt.Sprintf("Начислено %d XP за завершение курса", completion.XPAwarded)
+	}
+	return s.notificationRepo.Create(ctx, notification)
+}
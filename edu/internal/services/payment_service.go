@@ -2,45 +2,193 @@ package services
 
 import (
 	"context"
+	"course2/internal/metrics"
+	"course2/internal/models"
+	"course2/internal/payment"
 	"course2/internal/repositories"
+	"log/slog"
+	"math"
+	"net/http"
 
 	"github.com/google/uuid"
 )
 
+// PaymentService продает курсы через платежного провайдера. Курс выдается только по подписанному
+// уведомлению провайдера об оплате; бесплатный курс выдается сразу.
 type PaymentService struct {
 	courseRepo   *repositories.CourseRepository
 	purchaseRepo *repositories.PurchaseRepository
+	orderRepo    *repositories.OrderRepository
+	provider     payment.Provider
+	// currency - валюта цен курсов
+	currency string
 }
 
-func NewPaymentService(courseRepo *repositories.CourseRepository, purchaseRepo *repositories.PurchaseRepository) *PaymentService {
+func NewPaymentService(
+	courseRepo *repositories.CourseRepository,
+	purchaseRepo *repositories.PurchaseRepository,
+	orderRepo *repositories.OrderRepository,
+	provider payment.Provider,
+	currency string,
+) *PaymentService {
 	return &PaymentService{
 		courseRepo:   courseRepo,
 		purchaseRepo: purchaseRepo,
+		orderRepo:    orderRepo,
+		provider:     provider,
+		currency:     currency,
 	}
 }
 
-func (s *PaymentService) PurchaseCourse(ctx context.Context, userID, courseID uuid.UUID) error {
-	// Проверяем, что курс существует
+// Checkout создает заказ курса и платежную сессию у провайдера. Если заказ курса уже ждет оплаты,
+// возвращает его. Бесплатный курс выдается сразу, заказ получает статус paid.
+// Заказать можно только опубликованный курс.
+func (s *PaymentService) Checkout(ctx context.Context, userID, courseID uuid.UUID) (*models.Order, error) {
+	// Проверяем, что курс существует и продается
 	course, err := s.courseRepo.GetByID(ctx, courseID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if course == nil {
-		return ErrCourseNotFound
+	if !purchasable(course) {
+		return nil, ErrCourseNotFound
 	}
 
 	// Проверяем, не куплен ли уже курс
 	purchased, err := s.purchaseRepo.HasPurchased(ctx, userID, courseID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if purchased {
-		return ErrCourseAlreadyPurchased
+		return nil, ErrCourseAlreadyPurchased
+	}
+
+	order := &models.Order{
+		ID:       uuid.New(),
+		UserID:   userID,
+		CourseID: courseID,
+		// Цена хранится в рублях с копейками, провайдеру передается в копейках
+		Amount:   int64(math.Round(course.Price * 100)),
+		Currency: s.currency,
+	}
+
+	if order.Amount == 0 {
+		if err := s.orderRepo.CreatePaid(ctx, order); err != nil {
+			return nil, err
+		}
+		metrics.CoursePurchases.Inc()
+		return order, nil
+	}
+
+	created, err := s.orderRepo.CreateOpen(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		// Заказ уже создан: повторный запрос или параллельная покупка
+		order, err = s.orderRepo.GetOpen(ctx, userID, courseID)
+		if err != nil || order == nil {
+			return nil, err
+		}
+		if order.Status == models.OrderPending && order.CheckoutURL != "" {
+			return order, nil
+		}
+	}
+
+	session, err := s.provider.CreateSession(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.Payment{
+		ID:          uuid.New(),
+		Provider:    s.provider.Name(),
+		SessionID:   session.ID,
+		CheckoutURL: session.URL,
+	}
+	added, err := s.orderRepo.AddPayment(ctx, order, record)
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		// Заказ оплачен или отменен, пока создавалась сессия
+		return s.orderRepo.GetByID(ctx, order.ID)
+	}
+	return order, nil
+}
+
+// GetOrder возвращает заказ пользователя
+func (s *PaymentService) GetOrder(ctx context.Context, userID, orderID uuid.UUID) (*models.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil || order.UserID != userID {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// GetOrderBySession возвращает заказ пользователя по платежной сессии текущего провайдера
+func (s *PaymentService) GetOrderBySession(ctx context.Context, userID uuid.UUID, sessionID string) (*models.Order, error) {
+	order, _, err := s.orderRepo.GetBySession(ctx, s.provider.Name(), sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil || order.UserID != userID {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// HandleWebhook проверяет подпись уведомления провайдера providerName и применяет его к заказу.
+// Повторное уведомление и переход, недопустимый в текущем статусе заказа, ничего не меняют.
+func (s *PaymentService) HandleWebhook(ctx context.Context, providerName string, header http.Header, body []byte) error {
+	if providerName != s.provider.Name() {
+		return ErrUnknownPaymentProvider
+	}
+
+	event, err := s.provider.ParseWebhook(header, body)
+	if err != nil {
+		metrics.PaymentEvents.WithLabelValues(metrics.PaymentEventRejected).Inc()
+		return err
+	}
+
+	order, record, err := s.orderRepo.GetBySession(ctx, providerName, event.SessionID)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		metrics.PaymentEvents.WithLabelValues(metrics.PaymentEventRejected).Inc()
+		return ErrOrderNotFound
+	}
+	if event.Type == models.OrderPaid && (event.Amount != order.Amount || event.Currency != order.Currency) {
+		slog.WarnContext(ctx, "сумма оплаты не совпадает с заказом",
+			slog.String("order_id", order.ID.String()),
+			slog.String("event_id", event.ID),
+			slog.Int64("amount", event.Amount),
+			slog.String("currency", event.Currency))
+		metrics.PaymentEvents.WithLabelValues(metrics.PaymentEventRejected).Inc()
+		return ErrPaymentMismatch
+	}
+
+	applied, err := s.orderRepo.ApplyEvent(ctx, record, event)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		metrics.PaymentEvents.WithLabelValues(metrics.PaymentEventSkipped).Inc()
+		return nil
 	}
 
-	// Здесь должна быть интеграция с платежной системой
-	// ...
+	metrics.PaymentEvents.WithLabelValues(metrics.PaymentEventApplied).Inc()
+	if event.Type == models.OrderPaid {
+		metrics.CoursePurchases.Inc()
+	}
+	return nil
+}
 
-	// Записываем информацию о покупке
-	return s.purchaseRepo.CreatePurchase(ctx, userID, courseID)
+// purchasable сообщает, можно ли заказать курс. Черновики, курсы на проверке, отклоненные
+// и снятые с публикации не продаются, иначе бесплатный курс выдавался бы в обход модерации.
+func purchasable(course *models.Course) bool {
+	return course != nil && course.Status == models.CourseStatusPublished
 }
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"course2/internal/models"
+	"testing"
+)
+
+func TestPurchasable(t *testing.T) {
+	tests := []struct {
+		name   string
+		course *models.Course
+		want   bool
+	}{
+		{"опубликованный курс", &models.Course{Status: models.CourseStatusPublished, Price: 990}, true},
+		{"бесплатный опубликованный курс", &models.Course{Status: models.CourseStatusPublished}, true},
+		{"бесплатный черновик", &models.Course{Status: models.CourseStatusDraft}, false},
+		{"курс на проверке", &models.Course{Status: models.CourseStatusPendingReview, Price: 990}, false},
+		{"отклоненный курс", &models.Course{Status: models.CourseStatusRejected, Price: 990}, false},
+		{"снятый с публикации курс", &models.Course{Status: models.CourseStatusArchived, Price: 990}, false},
+		{"курс не найден", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := purchasable(tt.course); got != tt.want {
+				t.Errorf("purchasable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
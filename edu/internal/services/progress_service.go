@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"course2/internal/metrics"
+	"course2/internal/models"
+	"course2/internal/repositories"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CourseCompletedHandler обрабатывает завершение курса, например выдает сертификат или отправляет уведомление.
+// Вызывается после сохранения завершения, поэтому ошибка обработчика не отменяет завершение.
+type CourseCompletedHandler func(ctx context.Context, completion *models.CourseCompletion) error
+
+type ProgressService struct {
+	courseRepo   *repositories.CourseRepository
+	lessonRepo   *repositories.LessonRepository
+	purchaseRepo *repositories.PurchaseRepository
+	progressRepo *repositories.ProgressRepository
+	// completionXP - бонус XP за завершение курса
+	completionXP int
+	onCompleted  []CourseCompletedHandler
+}
+
+func NewProgressService(
+	courseRepo *repositories.CourseRepository,
+	lessonRepo *repositories.LessonRepository,
+	purchaseRepo *repositories.PurchaseRepository,
+	progressRepo *repositories.ProgressRepository,
+	completionXP int,
+) *ProgressService {
+	return &ProgressService{
+		courseRepo:   courseRepo,
+		lessonRepo:   lessonRepo,
+		purchaseRepo: purchaseRepo,
+		progressRepo: progressRepo,
+		completionXP: completionXP,
+	}
+}
+
+// OnCourseCompleted подписывает handler на событие завершения курса.
+// Подписка выполняется при сборке приложения, до обработки запросов.
+func (s *ProgressService) OnCourseCompleted(handler CourseCompletedHandler) {
+	s.onCompleted = append(s.onCompleted, handler)
+}
+
+// testPassXP - XP за первое прохождение теста урока
+const testPassXP = 10
+
+// TestLessonResult - изменения прогресса после отправки ответов на тест урока
+type TestLessonResult struct {
+	Progress *models.LessonProgress
+	// XPAwarded - XP за первое прохождение урока, 0 - тест не сдан или урок был пройден раньше
+	XPAwarded int
+	// Completion - завершение курса, если урок был последним непройденным
+	Completion *models.CourseCompletion
+}
+
+// RecordTestResult сохраняет результат теста урока lesson: testScore по правилу подсчета теста
+// и passed - сдан ли тест. current - прогресс урока до отправки ответов. Пройденный урок остается
+// пройденным, даже если по правилу last результат станет ниже проходного. Первое прохождение
+// приносит XP в той же транзакции, что и сохранение прогресса. Завершение курса проверяется
+// при каждой отправке по пройденному уроку, поэтому повтор после ошибки завершает курс.
+func (s *ProgressService) RecordTestResult(
+	ctx context.Context,
+	userID uuid.UUID,
+	lesson *models.Lesson,
+	current *models.LessonProgress,
+	testScore int,
+	passed bool,
+) (*TestLessonResult, error) {
+	firstPass := passed && !current.IsCompleted
+
+	now := time.Now()
+	progress := &models.LessonProgress{
+		ID:            current.ID,
+		UserID:        userID,
+		LessonID:      lesson.ID,
+		ViewedAt:      current.ViewedAt,
+		TestScore:     &testScore,
+		PassedTest:    passed,
+		LastAttemptAt: &now,
+		IsCompleted:   current.IsCompleted || passed,
+		CompletedAt:   current.CompletedAt,
+		AttemptsCount: current.AttemptsCount + 1,
+	}
+
+	var xp *models.XPEntry
+	if firstPass {
+		progress.CompletedAt = &now
+		xp = &models.XPEntry{
+			ID:       uuid.New(),
+			UserID:   userID,
+			CourseID: lesson.CourseID,
+			LessonID: lesson.ID,
+			Type:     "test_pass",
+			Amount:   testPassXP,
+		}
+	}
+
+	if err := s.progressRepo.SaveTestResult(ctx, progress, xp); err != nil {
+		return nil, err
+	}
+
+	result := &TestLessonResult{Progress: progress}
+	if xp != nil {
+		result.XPAwarded = xp.Amount
+	}
+
+	if progress.IsCompleted {
+		completion, err := s.CompleteCourse(ctx, userID, lesson.CourseID)
+		if err != nil {
+			return nil, err
+		}
+		result.Completion = completion
+	}
+
+	return result, nil
+}
+
+// CompleteCourse вызывается после прохождения урока. Если пройдены все уроки курса, отмечает
+// покупку завершенной, начисляет бонус XP и сообщает о завершении подписчикам.
+// Возвращает nil, если курс еще не пройден или уже был завершен раньше.
+func (s *ProgressService) CompleteCourse(ctx context.Context, userID, courseID uuid.UUID) (*models.CourseCompletion, error) {
+	var xp *models.XPEntry
+	if s.completionXP > 0 {
+		xp = &models.XPEntry{
+			ID:       uuid.New(),
+			UserID:   userID,
+			CourseID: courseID,
+			Type:     "course_complete",
+			Amount:   s.completionXP,
+		}
+	}
+
+	completedAt, err := s.purchaseRepo.CompleteCourse(ctx, userID, courseID, xp)
+	if err != nil || completedAt == nil {
+		return nil, err
+	}
+	metrics.CoursesCompleted.Inc()
+
+	completion := &models.CourseCompletion{
+		UserID:      userID,
+		CourseID:    courseID,
+		CompletedAt: *completedAt,
+	}
+	if xp != nil {
+		completion.XPAwarded = xp.Amount
+	}
+
+	course, err := s.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course != nil {
+		completion.CourseTitle = course.Title
+	}
+
+	for _, handler := range s.onCompleted {
+		if err := handler(ctx, completion); err != nil {
+			slog.ErrorContext(ctx, "ошибка обработки завершения курса",
+				slog.String("user_id", userID.String()),
+				slog.String("course_id", courseID.String()),
+				slog.Any("error", err))
+		}
+	}
+
+	return completion, nil
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"course2/internal/models"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// LessonLockedError - урок заблокирован правилами прохождения курса.
+// Сопоставляется с ErrLessonLocked через errors.Is.
+type LessonLockedError struct {
+	Reason    string
+	BlockedBy []uuid.UUID
+}
+
+func (e *LessonLockedError) Error() string {
+	return ErrLessonLocked.Error() + ": " + e.Reason
+}
+
+func (e *LessonLockedError) Is(target error) bool {
+	return target == ErrLessonLocked
+}
+
+// ApplyLocks отмечает заблокированные уроки курса по правилам прохождения и прогрессу пользователя.
+// lessons - все уроки курса в порядке order_num. uuid.Nil вместо userID - гость, у которого нет
+// пройденных уроков. Содержимое заблокированных уроков не отдается.
+func (s *ProgressService) ApplyLocks(ctx context.Context, course *models.Course, lessons []*models.Lesson, userID uuid.UUID) error {
+	if course.Progression == models.ProgressionFree || course.Progression == "" {
+		return nil
+	}
+
+	completed := map[uuid.UUID]bool{}
+	if userID != uuid.Nil {
+		var err error
+		completed, err = s.progressRepo.CompletedLessons(ctx, userID, course.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	var prerequisites map[uuid.UUID][]uuid.UUID
+	if course.Progression == models.ProgressionPrerequisites {
+		var err error
+		prerequisites, err = s.lessonRepo.ListPrerequisites(ctx, course.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	lockLessons(course.Progression, lessons, completed, prerequisites)
+	for _, lesson := range lessons {
+		if lesson.Locked {
+			lesson.Content = ""
+		}
+	}
+	return nil
+}
+
+// CheckLessonAccess проверяет, что пользователь купил курс урока и урок не заблокирован
+func (s *ProgressService) CheckLessonAccess(ctx context.Context, userID uuid.UUID, lesson *models.Lesson) error {
+	purchased, err := s.purchaseRepo.HasPurchased(ctx, userID, lesson.CourseID)
+	if err != nil {
+		return err
+	}
+	if !purchased {
+		return ErrCourseNotPurchased
+	}
+	return s.checkLock(ctx, userID, lesson)
+}
+
+// CheckPreviewAccess проверяет, что урок открыт гостю: при свободном порядке открыты все уроки,
+// иначе - только уроки без требований
+func (s *ProgressService) CheckPreviewAccess(ctx context.Context, lesson *models.Lesson) error {
+	return s.checkLock(ctx, uuid.Nil, lesson)
+}
+
+func (s *ProgressService) checkLock(ctx context.Context, userID uuid.UUID, lesson *models.Lesson) error {
+	course, err := s.courseRepo.GetByID(ctx, lesson.CourseID)
+	if err != nil {
+		return err
+	}
+	if course == nil {
+		return ErrCourseNotFound
+	}
+	if course.Progression == models.ProgressionFree || course.Progression == "" {
+		return nil
+	}
+
+	lessons, err := s.lessonRepo.ListByCourse(ctx, course.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.ApplyLocks(ctx, course, lessons, userID); err != nil {
+		return err
+	}
+
+	for _, l := range lessons {
+		if l.ID == lesson.ID && l.Locked {
+			return &LessonLockedError{Reason: l.LockReason, BlockedBy: l.BlockedBy}
+		}
+	}
+	return nil
+}
+
+// lockLessons заполняет Locked, LockReason и BlockedBy уроков. completed - пройденные уроки,
+// prerequisites - предварительные уроки (используются при ProgressionPrerequisites).
+func lockLessons(progression string, lessons []*models.Lesson, completed map[uuid.UUID]bool, prerequisites map[uuid.UUID][]uuid.UUID) {
+	switch progression {
+	case models.ProgressionSequential:
+		var pending []uuid.UUID
+		for _, lesson := range lessons {
+			if len(pending) > 0 {
+				lesson.Locked = true
+				lesson.LockReason = models.LockReasonPreviousLessons
+				lesson.BlockedBy = slices.Clone(pending)
+			}
+			if !completed[lesson.ID] {
+				pending = append(pending, lesson.ID)
+			}
+		}
+
+	case models.ProgressionPrerequisites:
+		for _, lesson := range lessons {
+			lesson.Prerequisites = prerequisites[lesson.ID]
+			var missing []uuid.UUID
+			for _, id := range lesson.Prerequisites {
+				if !completed[id] {
+					missing = append(missing, id)
+				}
+			}
+			if len(missing) > 0 {
+				lesson.Locked = true
+				lesson.LockReason = models.LockReasonPrerequisites
+				lesson.BlockedBy = missing
+			}
+		}
+	}
+}
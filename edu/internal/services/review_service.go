@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"course2/internal/models"
+	"course2/internal/repositories"
+	"fmt"
+	"platform/pagination"
+
+	"github.com/google/uuid"
+)
+
+type ReviewService struct {
+	courseRepo   *repositories.CourseRepository
+	reviewRepo   *repositories.ReviewRepository
+	purchaseRepo *repositories.PurchaseRepository
+	progressRepo *repositories.ProgressRepository
+	// minProgress - процент прохождения курса, после которого можно оставить отзыв
+	minProgress float64
+}
+
+func NewReviewService(
+	courseRepo *repositories.CourseRepository,
+	reviewRepo *repositories.ReviewRepository,
+	purchaseRepo *repositories.PurchaseRepository,
+	progressRepo *repositories.ProgressRepository,
+	minProgress float64,
+) *ReviewService {
+	return &ReviewService{
+		courseRepo:   courseRepo,
+		reviewRepo:   reviewRepo,
+		purchaseRepo: purchaseRepo,
+		progressRepo: progressRepo,
+		minProgress:  minProgress,
+	}
+}
+
+// ListReviews возвращает видимые отзывы опубликованного курса
+func (s *ReviewService) ListReviews(ctx context.Context, courseID uuid.UUID, sort models.ReviewSort, page pagination.Params) (*pagination.Page[*models.Review], error) {
+	if _, err := s.publishedCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	reviews, err := s.reviewRepo.ListByCourse(ctx, courseID, sort, page)
+	if err != nil {
+		return nil, err
+	}
+	for _, review := range reviews.Items {
+		review.ReportsCount = 0
+	}
+	return reviews, nil
+}
+
+// GetRating возвращает рейтинг опубликованного курса и распределение оценок
+func (s *ReviewService) GetRating(ctx context.Context, courseID uuid.UUID) (*models.RatingSummary, error) {
+	if _, err := s.publishedCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+	return s.reviewRepo.GetRating(ctx, courseID)
+}
+
+// CreateReview сохраняет отзыв пользователя о курсе. Отзыв может оставить только купивший курс
+// и, если задан minProgress, прошедший не меньше minProgress процентов курса.
+func (s *ReviewService) CreateReview(ctx context.Context, review *models.Review) error {
+	if _, err := s.publishedCourse(ctx, review.CourseID); err != nil {
+		return err
+	}
+
+	purchased, err := s.purchaseRepo.HasPurchased(ctx, review.UserID, review.CourseID)
+	if err != nil {
+		return err
+	}
+	if !purchased {
+		return ErrCourseNotPurchased
+	}
+
+	if s.minProgress > 0 {
+		progress, err := s.progressRepo.GetCourseProgress(ctx, review.UserID, review.CourseID)
+		if err != nil {
+			return err
+		}
+		if progress == nil || progress.Percentage < s.minProgress {
+			return fmt.Errorf("%w: нужно пройти %.0f%% курса", ErrReviewProgressRequired, s.minProgress)
+		}
+	}
+
+	review.ID = uuid.New()
+	created, err := s.reviewRepo.Create(ctx, review)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return ErrReviewAlreadyExists
+	}
+	return nil
+}
+
+// UpdateReview изменяет оценку и текст отзыва. Изменить отзыв может только его автор.
+func (s *ReviewService) UpdateReview(ctx context.Context, reviewID, userID uuid.UUID, rating int, text string) (*models.Review, error) {
+	review, err := s.ownReview(ctx, reviewID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Rating = rating
+	review.Text = text
+	if err := s.reviewRepo.Update(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// DeleteReview удаляет отзыв. Удалить отзыв может только его автор.
+func (s *ReviewService) DeleteReview(ctx context.Context, reviewID, userID uuid.UUID) error {
+	review, err := s.ownReview(ctx, reviewID, userID)
+	if err != nil {
+		return err
+	}
+	return s.reviewRepo.Delete(ctx, review)
+}
+
+// VoteHelpful отмечает чужой отзыв полезным
+func (s *ReviewService) VoteHelpful(ctx context.Context, reviewID, userID uuid.UUID) error {
+	if _, err := s.othersReview(ctx, reviewID, userID); err != nil {
+		return err
+	}
+	return s.reviewRepo.Vote(ctx, reviewID, userID)
+}
+
+// UnvoteHelpful снимает отметку «полезный отзыв»
+func (s *ReviewService) UnvoteHelpful(ctx context.Context, reviewID, userID uuid.UUID) error {
+	if _, err := s.othersReview(ctx, reviewID, userID); err != nil {
+		return err
+	}
+	return s.reviewRepo.Unvote(ctx, reviewID, userID)
+}
+
+// ReportReview сохраняет жалобу на чужой отзыв. Отзыв с жалобами попадает в очередь администраторов.
+func (s *ReviewService) ReportReview(ctx context.Context, reviewID, userID uuid.UUID, reason string) error {
+	if _, err := s.othersReview(ctx, reviewID, userID); err != nil {
+		return err
+	}
+	return s.reviewRepo.Report(ctx, reviewID, userID, reason)
+}
+
+// ListReported возвращает видимые отзывы с жалобами, сначала с наибольшим числом жалоб
+func (s *ReviewService) ListReported(ctx context.Context, page pagination.Params) (*pagination.Page[*models.Review], error) {
+	return s.reviewRepo.ListReported(ctx, page)
+}
+
+// ListReports возвращает жалобы на отзыв
+func (s *ReviewService) ListReports(ctx context.Context, reviewID uuid.UUID, page pagination.Params) (*pagination.Page[*models.ReviewReport], error) {
+	if _, err := s.getReview(ctx, reviewID); err != nil {
+		return nil, err
+	}
+	return s.reviewRepo.ListReports(ctx, reviewID, page)
+}
+
+// HideReview скрывает отзыв из списка и рейтинга курса и уведомляет автора отзыва о причине
+func (s *ReviewService) HideReview(ctx context.Context, reviewID, adminID uuid.UUID, reason string) (*models.Review, error) {
+	review, err := s.getReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.HiddenAt != nil {
+		return review, nil
+	}
+
+	course, err := s.courseRepo.GetByID(ctx, review.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, ErrCourseNotFound
+	}
+
+	courseID := course.ID
+	notification := &models.Notification{
+		ID:       uuid.New(),
+		UserID:   review.UserID,
+		Type:     models.NotificationReviewHidden,
+		Title:    fmt.Sprintf("Ваш отзыв о курсе «%s» скрыт", course.Title),
+		Message:  reason,
+		CourseID: &courseID,
+	}
+	if _, err := s.reviewRepo.Hide(ctx, review, adminID, notification); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// UnhideReview возвращает скрытый отзыв и снимает жалобы на него
+func (s *ReviewService) UnhideReview(ctx context.Context, reviewID uuid.UUID) (*models.Review, error) {
+	review, err := s.getReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.reviewRepo.Unhide(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (s *ReviewService) publishedCourse(ctx context.Context, courseID uuid.UUID) (*models.Course, error) {
+	course, err := s.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil || course.Status != models.CourseStatusPublished {
+		return nil, ErrCourseNotFound
+	}
+	return course, nil
+}
+
+func (s *ReviewService) getReview(ctx context.Context, reviewID uuid.UUID) (*models.Review, error) {
+	review, err := s.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, ErrReviewNotFound
+	}
+	return review, nil
+}
+
+// ownReview возвращает отзыв, автор которого userID
+func (s *ReviewService) ownReview(ctx context.Context, reviewID, userID uuid.UUID) (*models.Review, error) {
+	review, err := s.getReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != userID {
+		return nil, ErrInsufficientPermissions
+	}
+	return review, nil
+}
+
+// othersReview возвращает видимый отзыв другого пользователя
+func (s *ReviewService) othersReview(ctx context.Context, reviewID, userID uuid.UUID) (*models.Review, error) {
+	review, err := s.getReview(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.HiddenAt != nil {
+		return nil, ErrReviewNotFound
+	}
+	if review.UserID == userID {
+		return nil, ErrOwnReview
+	}
+	return review, nil
+}
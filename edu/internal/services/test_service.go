@@ -0,0 +1,459 @@
+package services
+
+import (
+	"context"
+	"course2/internal/grading"
+	"course2/internal/models"
+	"course2/internal/repositories"
+	"fmt"
+	"math/rand/v2"
+	"platform/pagination"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TestService выдает студентам попытки теста и проверяет ответы по вопросам попытки
+type TestService struct {
+	testRepo    *repositories.TestRepository
+	attemptRepo *repositories.AttemptRepository
+}
+
+func NewTestService(testRepo *repositories.TestRepository, attemptRepo *repositories.AttemptRepository) *TestService {
+	return &TestService{
+		testRepo:    testRepo,
+		attemptRepo: attemptRepo,
+	}
+}
+
+const (
+	// deadlineGrace - запас на доставку ответов, отправленных к концу времени на попытку
+	deadlineGrace = 15 * time.Second
+	// maxCooldown - верхняя граница паузы между попытками после удвоений
+	maxCooldown = 7 * 24 * time.Hour
+)
+
+// StartAttempt возвращает незавершенную попытку студента, а если ее нет - начинает новую:
+// выбирает вопросы из пула теста и перемешивает их по настройкам теста. Попытка с истекшим
+// временем завершается с результатом 0. Если новую попытку начать нельзя (попытки закончились
+// или действует пауза), попытка равна nil, а причину показывает сводка.
+func (s *TestService) StartAttempt(ctx context.Context, userID uuid.UUID, test *models.Test) (*models.TestAttempt, *models.AttemptStatus, error) {
+	now := time.Now()
+
+	attempt, err := s.attemptRepo.GetOpen(ctx, userID, test.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attempt != nil && expired(attempt, now) {
+		if _, err := s.attemptRepo.Submit(ctx, expiredAttempt(attempt)); err != nil {
+			return nil, nil, err
+		}
+		attempt = nil
+	}
+
+	submitted, err := s.attemptRepo.ListSubmitted(ctx, userID, test.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attempt != nil {
+		return attempt, attemptStatus(test, submitted, true, now), nil
+	}
+
+	status := attemptStatus(test, submitted, false, now)
+	if !status.CanStart() {
+		return nil, status, nil
+	}
+
+	pool, err := s.testRepo.GetQuestions(ctx, test.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attempt = &models.TestAttempt{
+		ID:     uuid.New(),
+		TestID: test.ID,
+		UserID: userID,
+		Items:  drawItems(test, pool),
+	}
+
+	created, err := s.attemptRepo.CreateOpen(ctx, attempt, test.TimeLimitSeconds)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !created {
+		// Попытку параллельно начал другой запрос студента
+		attempt, err = s.attemptRepo.GetOpen(ctx, userID, test.ID)
+		if err != nil || attempt == nil {
+			return nil, nil, err
+		}
+	}
+	return attempt, attemptStatus(test, submitted, true, now), nil
+}
+
+// SubmitAttempt проверяет ответы по вопросам незавершенной попытки и завершает ее.
+// Если attemptID задан, он должен совпадать с незавершенной попыткой. Ответы после истечения
+// времени проверяются или отклоняются с ErrAttemptExpired - по настройке теста.
+func (s *TestService) SubmitAttempt(
+	ctx context.Context,
+	userID uuid.UUID,
+	test *models.Test,
+	attemptID *uuid.UUID,
+	answers map[uuid.UUID]models.Answer,
+) (*models.TestAttempt, *grading.Result, error) {
+	attempt, err := s.attemptRepo.GetOpen(ctx, userID, test.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attempt == nil || (attemptID != nil && *attemptID != attempt.ID) {
+		return nil, nil, ErrAttemptNotOpen
+	}
+
+	late := expired(attempt, time.Now())
+	if late && test.LateSubmission != models.LateGrade {
+		if _, err := s.attemptRepo.Submit(ctx, expiredAttempt(attempt)); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrAttemptExpired
+	}
+
+	result, original := grading.GradeAttempt(attempt, answers)
+	attempt.Score = &result.Score
+	attempt.Points = &result.Points
+	attempt.MaxPoints = &result.MaxPoints
+	attempt.Late = late
+	attempt.Answers = make([]*models.AttemptAnswer, len(result.Questions))
+	for i, question := range result.Questions {
+		attempt.Answers[i] = &models.AttemptAnswer{
+			QuestionID: question.QuestionID,
+			Position:   i,
+			Points:     question.Points,
+			MaxPoints:  question.MaxPoints,
+			Correct:    question.Correct,
+		}
+		if answer, ok := original[question.QuestionID]; ok {
+			attempt.Answers[i].Answer = &answer
+		}
+	}
+
+	submitted, err := s.attemptRepo.Submit(ctx, attempt)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !submitted {
+		return nil, nil, ErrAttemptNotOpen
+	}
+	return attempt, result, nil
+}
+
+// ListAttempts возвращает страницу завершенных попыток студента, начиная с последней
+func (s *TestService) ListAttempts(ctx context.Context, userID uuid.UUID, test *models.Test, page pagination.Params) (*pagination.Page[*models.AttemptSummary], error) {
+	submitted, err := s.attemptRepo.ListSubmittedPage(ctx, userID, test.ID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := &pagination.Page[*models.AttemptSummary]{
+		Items:         make([]*models.AttemptSummary, len(submitted.Items)),
+		NextCursor:    submitted.NextCursor,
+		TotalEstimate: submitted.TotalEstimate,
+	}
+	for i, attempt := range submitted.Items {
+		summaries.Items[i] = summary(attempt)
+	}
+	return summaries, nil
+}
+
+// ReviewAttempt возвращает разбор завершенной попытки студента. Правильные ответы показываются
+// по review_policy теста: после каждой попытки или после прохождения теста.
+func (s *TestService) ReviewAttempt(ctx context.Context, userID uuid.UUID, test *models.Test, attemptID uuid.UUID) (*models.AttemptReview, error) {
+	attempt, err := s.attemptRepo.GetSubmitted(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt == nil || attempt.UserID != userID || attempt.TestID != test.ID {
+		return nil, ErrAttemptNotFound
+	}
+
+	revealed := test.ReviewPolicy == models.ReviewAfterSubmit
+	if test.ReviewPolicy == models.ReviewAfterPass {
+		status, err := s.Status(ctx, userID, test)
+		if err != nil {
+			return nil, err
+		}
+		revealed = status.Score != nil && *status.Score >= test.PassingScore
+	}
+
+	answers := make(map[uuid.UUID]*models.AttemptAnswer, len(attempt.Answers))
+	for _, answer := range attempt.Answers {
+		answers[answer.QuestionID] = answer
+	}
+
+	review := &models.AttemptReview{
+		Attempt:         summary(attempt),
+		AnswersRevealed: revealed,
+		Questions:       make([]*models.ReviewQuestion, len(attempt.Items)),
+	}
+	for i, item := range attempt.Items {
+		question := item.Served()
+		question.Explanation = item.Question.Explanation
+		if revealed {
+			question.Key = grading.ServedKey(item)
+		}
+
+		reviewed := &models.ReviewQuestion{Question: question, MaxPoints: item.Question.Points}
+		if answer, ok := answers[item.Question.ID]; ok {
+			reviewed.Points = answer.Points
+			reviewed.MaxPoints = answer.MaxPoints
+			reviewed.Correct = answer.Correct
+			if answer.Answer != nil {
+				served := grading.ToServed(item, *answer.Answer)
+				reviewed.Answer = &served
+			}
+		}
+		review.Questions[i] = reviewed
+	}
+	return review, nil
+}
+
+// Status возвращает сводку по попыткам студента
+func (s *TestService) Status(ctx context.Context, userID uuid.UUID, test *models.Test) (*models.AttemptStatus, error) {
+	submitted, err := s.attemptRepo.ListSubmitted(ctx, userID, test.ID)
+	if err != nil {
+		return nil, err
+	}
+	return attemptStatus(test, submitted, false, time.Now()), nil
+}
+
+// attemptStatus считает сводку по завершенным попыткам submitted. open - есть незавершенная попытка.
+func attemptStatus(test *models.Test, submitted []*models.TestAttempt, open bool, now time.Time) *models.AttemptStatus {
+	status := &models.AttemptStatus{
+		AttemptsUsed: len(submitted),
+		Score:        policyScore(test.ScoringPolicy, submitted),
+	}
+	if open {
+		status.AttemptsUsed++
+	}
+
+	if test.MaxAttempts > 0 {
+		left := max(test.MaxAttempts-status.AttemptsUsed, 0)
+		status.AttemptsLeft = &left
+	}
+
+	if !open && len(submitted) > 0 && test.CooldownSeconds > 0 {
+		last := submitted[len(submitted)-1]
+		next := last.SubmittedAt.Add(cooldown(test, submitted))
+		if next.After(now) {
+			status.NextAttemptAt = &next
+		}
+	}
+	return status
+}
+
+// cooldown возвращает паузу после последней попытки: базовая пауза теста удваивается
+// за каждую неудачную попытку подряд, начиная со второй
+func cooldown(test *models.Test, submitted []*models.TestAttempt) time.Duration {
+	wait := time.Duration(test.CooldownSeconds) * time.Second
+	for i := len(submitted) - 2; i >= 0 && wait < maxCooldown; i-- {
+		if failed(test, submitted[i]) && failed(test, submitted[i+1]) {
+			wait *= 2
+			continue
+		}
+		break
+	}
+	return min(wait, maxCooldown)
+}
+
+func failed(test *models.Test, attempt *models.TestAttempt) bool {
+	return attempt.Score == nil || *attempt.Score < test.PassingScore
+}
+
+// policyScore возвращает результат теста по правилу подсчета или nil, если попыток нет
+func policyScore(policy string, submitted []*models.TestAttempt) *int {
+	if len(submitted) == 0 {
+		return nil
+	}
+
+	var score int
+	switch policy {
+	case models.ScoringLast:
+		score = attemptScore(submitted[len(submitted)-1])
+	case models.ScoringAverage:
+		sum := 0
+		for _, attempt := range submitted {
+			sum += attemptScore(attempt)
+		}
+		score = sum / len(submitted)
+	default:
+		for _, attempt := range submitted {
+			score = max(score, attemptScore(attempt))
+		}
+	}
+	return &score
+}
+
+func attemptScore(attempt *models.TestAttempt) int {
+	if attempt.Score == nil {
+		return 0
+	}
+	return *attempt.Score
+}
+
+// expiredAttempt готовит попытку с истекшим временем к завершению с результатом 0 и без ответов
+func expiredAttempt(attempt *models.TestAttempt) *models.TestAttempt {
+	score, points, maxPoints := 0, 0.0, 0
+	for _, item := range attempt.Items {
+		maxPoints += item.Question.Points
+	}
+	attempt.Score = &score
+	attempt.Points = &points
+	attempt.MaxPoints = &maxPoints
+	attempt.Late = true
+	return attempt
+}
+
+func summary(attempt *models.TestAttempt) *models.AttemptSummary {
+	return &models.AttemptSummary{
+		TestAttempt:     attempt,
+		DurationSeconds: int(attempt.Duration().Seconds()),
+	}
+}
+
+// expired сообщает, что время на попытку истекло к моменту now
+func expired(attempt *models.TestAttempt, now time.Time) bool {
+	return attempt.ExpiresAt != nil && now.After(attempt.ExpiresAt.Add(deadlineGrace))
+}
+
+// TestSettingsError - неверная настройка теста. Field - поле запроса, Rule - нарушенное правило
+// в терминах правил проверки запросов, Reason - описание для журнала.
+type TestSettingsError struct {
+	Field  string
+	Rule   string
+	Reason string
+}
+
+func (e *TestSettingsError) Error() string {
+	return ErrInvalidTestSettings.Error() + ": " + e.Field + " " + e.Reason
+}
+
+func (e *TestSettingsError) Is(target error) bool {
+	return target == ErrInvalidTestSettings
+}
+
+// ValidateTestSettings проверяет настройки выборки вопросов, ограничения попыток и правила теста.
+// Ошибка - *TestSettingsError с неверным полем.
+func ValidateTestSettings(test *models.Test) error {
+	switch {
+	case test.QuestionCount < 0:
+		return &TestSettingsError{Field: "question_count", Rule: "min", Reason: "не может быть отрицательным"}
+	case test.MaxAttempts < 0:
+		return &TestSettingsError{Field: "max_attempts", Rule: "min", Reason: "не может быть отрицательным"}
+	case test.CooldownSeconds < 0:
+		return &TestSettingsError{Field: "cooldown_seconds", Rule: "min", Reason: "не может быть отрицательным"}
+	case test.TimeLimitSeconds < 0:
+		return &TestSettingsError{Field: "time_limit_seconds", Rule: "min", Reason: "не может быть отрицательным"}
+	}
+	switch test.ScoringPolicy {
+	case models.ScoringBest, models.ScoringLast, models.ScoringAverage:
+	default:
+		return &TestSettingsError{Field: "scoring_policy", Rule: "oneof", Reason: fmt.Sprintf("неизвестное значение %q", test.ScoringPolicy)}
+	}
+	if test.LateSubmission != models.LateReject && test.LateSubmission != models.LateGrade {
+		return &TestSettingsError{Field: "late_submission", Rule: "oneof", Reason: fmt.Sprintf("неизвестное значение %q", test.LateSubmission)}
+	}
+	switch test.ReviewPolicy {
+	case models.ReviewNever, models.ReviewAfterSubmit, models.ReviewAfterPass:
+	default:
+		return &TestSettingsError{Field: "review_policy", Rule: "oneof", Reason: fmt.Sprintf("неизвестное значение %q", test.ReviewPolicy)}
+	}
+	if len(test.TagQuotas) == 0 {
+		return nil
+	}
+	if test.QuestionCount == 0 {
+		return &TestSettingsError{Field: "question_count", Rule: "required_with", Reason: "обязателен при tag_quotas"}
+	}
+
+	total := 0
+	seen := make(map[string]bool, len(test.TagQuotas))
+	for _, quota := range test.TagQuotas {
+		tag := strings.TrimSpace(quota.Tag)
+		switch {
+		case tag == "":
+			return &TestSettingsError{Field: "tag_quotas", Rule: "required", Reason: "пустой тег"}
+		case quota.Count <= 0:
+			return &TestSettingsError{Field: "tag_quotas", Rule: "gt", Reason: fmt.Sprintf("квота тега %q должна быть больше 0", tag)}
+		case seen[tag]:
+			return &TestSettingsError{Field: "tag_quotas", Rule: "unique", Reason: fmt.Sprintf("тег %q повторяется", tag)}
+		}
+		seen[tag] = true
+		total += quota.Count
+	}
+	if total > test.QuestionCount {
+		return &TestSettingsError{Field: "tag_quotas", Rule: "lte", Reason: "сумма квот больше question_count"}
+	}
+	return nil
+}
+
+// drawItems выбирает вопросы попытки. Сначала набираются квоты по тегам, затем оставшиеся места
+// заполняются случайными вопросами пула. Если вопросов с тегом меньше квоты, берутся все.
+// Без перемешивания вопросы идут в порядке пула.
+func drawItems(test *models.Test, pool []*models.Question) []*models.AttemptItem {
+	count := test.QuestionCount
+	if count <= 0 || count > len(pool) {
+		count = len(pool)
+	}
+
+	candidates := rand.Perm(len(pool))
+	taken := make([]bool, len(pool))
+	picked := make([]int, 0, count)
+
+	take := func(match func(q *models.Question) bool, limit int) {
+		for _, index := range candidates {
+			if limit == 0 || len(picked) == count {
+				return
+			}
+			if !taken[index] && match(pool[index]) {
+				taken[index] = true
+				picked = append(picked, index)
+				limit--
+			}
+		}
+	}
+
+	for _, quota := range test.TagQuotas {
+		take(func(q *models.Question) bool { return slices.Contains(q.Tags, quota.Tag) }, quota.Count)
+	}
+	take(func(*models.Question) bool { return true }, count)
+
+	if test.ShuffleQuestions {
+		rand.Shuffle(len(picked), func(i, j int) { picked[i], picked[j] = picked[j], picked[i] })
+	} else {
+		slices.Sort(picked)
+	}
+
+	items := make([]*models.AttemptItem, len(picked))
+	for i, index := range picked {
+		question := pool[index]
+		item := &models.AttemptItem{Question: question}
+		if test.ShuffleOptions && shufflesOptions(question.Type) {
+			item.OptionOrder = rand.Perm(len(question.Options))
+			if question.Type == models.QuestionMatching {
+				item.MatchOrder = rand.Perm(len(question.Matches))
+			}
+		}
+		items[i] = item
+	}
+	return items
+}
+
+// shufflesOptions сообщает, можно ли перемешивать варианты вопроса. У "верно/неверно" порядок
+// вариантов фиксирован, у числовых и текстовых вопросов вариантов нет.
+func shufflesOptions(questionType string) bool {
+	switch questionType {
+	case models.QuestionSingleChoice, models.QuestionMultipleChoice,
+		models.QuestionOrdering, models.QuestionMatching:
+		return true
+	}
+	return false
+}
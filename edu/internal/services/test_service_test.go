@@ -0,0 +1,63 @@
+package services
+
+import (
+	"course2/internal/models"
+	"errors"
+	"testing"
+)
+
+func TestValidateTestSettings(t *testing.T) {
+	valid := func() *models.Test {
+		return &models.Test{
+			ScoringPolicy:  models.ScoringBest,
+			LateSubmission: models.LateReject,
+			ReviewPolicy:   models.ReviewNever,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(test *models.Test)
+		field  string
+		rule   string
+	}{
+		{"верные настройки", func(test *models.Test) {}, "", ""},
+		{"отрицательный лимит попыток", func(test *models.Test) { test.MaxAttempts = -1 }, "max_attempts", "min"},
+		{"отрицательное время", func(test *models.Test) { test.TimeLimitSeconds = -5 }, "time_limit_seconds", "min"},
+		{"неизвестное правило подсчета", func(test *models.Test) { test.ScoringPolicy = "median" }, "scoring_policy", "oneof"},
+		{"неизвестное правило опоздания", func(test *models.Test) { test.LateSubmission = "" }, "late_submission", "oneof"},
+		{"квоты без числа вопросов", func(test *models.Test) {
+			test.TagQuotas = []models.TagQuota{{Tag: "go", Count: 1}}
+		}, "question_count", "required_with"},
+		{"повтор тега", func(test *models.Test) {
+			test.QuestionCount = 5
+			test.TagQuotas = []models.TagQuota{{Tag: "go", Count: 1}, {Tag: " go ", Count: 1}}
+		}, "tag_quotas", "unique"},
+		{"сумма квот больше числа вопросов", func(test *models.Test) {
+			test.QuestionCount = 2
+			test.TagQuotas = []models.TagQuota{{Tag: "go", Count: 2}, {Tag: "sql", Count: 1}}
+		}, "tag_quotas", "lte"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := valid()
+			tt.change(test)
+			err := ValidateTestSettings(test)
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("ValidateTestSettings() error = %v", err)
+				}
+				return
+			}
+
+			var settings *TestSettingsError
+			if !errors.As(err, &settings) || !errors.Is(err, ErrInvalidTestSettings) {
+				t.Fatalf("ValidateTestSettings() error = %v, want TestSettingsError", err)
+			}
+			if settings.Field != tt.field || settings.Rule != tt.rule {
+				t.Errorf("ValidateTestSettings() field = %s/%s, want %s/%s", settings.Field, settings.Rule, tt.field, tt.rule)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"course2/internal/models"
 	"course2/internal/repositories"
+	"platform/pagination"
 
 	"github.com/google/uuid"
 )
@@ -47,8 +48,8 @@ func (s *UserService) UpdateProfile(ctx context.Context, profile *models.UserPro
 	return s.userRepo.UpdateProfile(ctx, profile)
 }
 
-func (s *UserService) GetPurchasedCourses(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
-	return s.purchaseRepo.GetPurchasedCourses(ctx, userID)
+func (s *UserService) GetPurchasedCourses(ctx context.Context, userID uuid.UUID, page pagination.Params) (*pagination.Page[*models.PurchasedCourse], error) {
+	return s.purchaseRepo.GetPurchasedCourses(ctx, userID, page)
 }
 
 func (s *UserService) GetTotalXP(ctx context.Context, userID uuid.UUID) (int, error) {
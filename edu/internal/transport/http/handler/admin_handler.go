@@ -3,9 +3,10 @@ package handler
 import (
 	"course2/internal/models"
 	"course2/internal/services"
-	"course2/internal/transport/http/middleware"
 	"net/http"
-	"strconv"
+	"platform/auth"
+	"platform/pagination"
+	"platform/problem"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -15,7 +16,7 @@ type AdminHandler struct {
 	moderationService *services.ModerationService
 }
 
-func NewAdminHandler(router *gin.Engine, moderationService *services.ModerationService, authMiddleware *middleware.RolesMiddleware) {
+func NewAdminHandler(router *gin.Engine, moderationService *services.ModerationService, authMiddleware *auth.Middleware) {
 	handler := &AdminHandler{
 		moderationService: moderationService,
 	}
@@ -23,9 +24,13 @@ func NewAdminHandler(router *gin.Engine, moderationService *services.ModerationS
 	admin := router.Group("/api/v1/edu/admin")
 	admin.Use(authMiddleware.RequireRoles("admin"))
 	{
+		// Модерация
 		admin.GET("/courses/pending", handler.ListPendingCourses)
+		admin.GET("/courses/:id/events", handler.ListCourseEvents)
+		admin.POST("/courses/:id/assign", handler.AssignReviewer)
 		admin.POST("/courses/:id/approve", handler.ApproveCourse)
 		admin.POST("/courses/:id/reject", handler.RejectCourse)
+		admin.POST("/courses/:id/archive", handler.ArchiveCourse)
 
 		// Управление курсами
 		admin.POST("/courses", handler.CreateCourse)
@@ -34,47 +39,163 @@ func NewAdminHandler(router *gin.Engine, moderationService *services.ModerationS
 	}
 }
 
-// @Summary Список ожидающих модерации курсов
-// @Description Получить список курсов, ожидающих модерации
+// AssignReviewerRequest модель запроса для назначения проверяющего.
+// Без reviewer_id курс назначается текущему администратору.
+type AssignReviewerRequest struct {
+	ReviewerID *uuid.UUID `json:"reviewer_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+// ApproveCourseRequest модель запроса для одобрения курса
+type ApproveCourseRequest struct {
+	// Note - внутренняя заметка проверяющего, автор ее не видит
+	Note string `json:"note"`
+}
+
+// RejectCourseRequest модель запроса для отклонения курса
+type RejectCourseRequest struct {
+	// Reason - причина отклонения, ее получает автор
+	Reason string `json:"reason" binding:"required" example:"Добавьте тесты к урокам"`
+	// Note - внутренняя заметка проверяющего, автор ее не видит
+	Note string `json:"note"`
+}
+
+// ArchiveCourseRequest модель запроса для снятия курса с публикации
+type ArchiveCourseRequest struct {
+	Reason string `json:"reason" binding:"required" example:"Материал устарел"`
+}
+
+// @Summary Очередь модерации
+// @Description Получить курсы в статусе pending_review, сначала давно ожидающие
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param page query int false "Номер страницы"
-// @Param limit query int false "Количество элементов на странице"
-// @Success 200 {array} models.Course
+// @Param reviewer query string false "me - назначенные мне, unassigned - без проверяющего, либо ID проверяющего"
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество элементов на странице, не больше 100"
+// @Success 200 {object} pagination.Page[models.Course]
 // @Router /admin/courses/pending [get]
 func (h *AdminHandler) ListPendingCourses(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, ok := pageParams(c, pagination.DefaultLimits)
+	if !ok {
+		return
+	}
 
-	courses, err := h.moderationService.ListPendingCourses(c.Request.Context(), page, limit)
+	var filter services.ReviewQueueFilter
+	switch reviewer := c.Query("reviewer"); reviewer {
+	case "":
+	case "me":
+		userID := auth.MustUserID(c)
+		filter.ReviewerID = &userID
+	case "unassigned":
+		filter.Unassigned = true
+	default:
+		reviewerID, err := uuid.Parse(reviewer)
+		if err != nil {
+			abort(c, problem.InvalidID)
+			return
+		}
+		filter.ReviewerID = &reviewerID
+	}
+
+	courses, err := h.moderationService.ListPendingCourses(c.Request.Context(), filter, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, courses)
 }
 
+// @Summary История модерации курса
+// @Description Получить события модерации курса с причинами и заметками проверяющих
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество элементов на странице, не больше 100"
+// @Success 200 {object} pagination.Page[models.ModerationEvent]
+// @Router /admin/courses/{id}/events [get]
+func (h *AdminHandler) ListCourseEvents(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	page, ok := pageParams(c, pagination.DefaultLimits)
+	if !ok {
+		return
+	}
+
+	events, err := h.moderationService.ListEvents(c.Request.Context(), id, page)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// @Summary Назначить проверяющего
+// @Description Назначить курс из очереди модерации проверяющему
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param request body AssignReviewerRequest false "Проверяющий"
+// @Success 200 {object} models.Course
+// @Router /admin/courses/{id}/assign [post]
+func (h *AdminHandler) AssignReviewer(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var request AssignReviewerRequest
+	if !bindOptionalJSON(c, &request) {
+		return
+	}
+
+	actorID := auth.MustUserID(c)
+	reviewerID := actorID
+	if request.ReviewerID != nil {
+		reviewerID = *request.ReviewerID
+	}
+
+	course, err := h.moderationService.AssignReviewer(c.Request.Context(), id, reviewerID, actorID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, course)
+}
+
 // @Summary Одобрить курс
-// @Description Одобрить курс для публикации
+// @Description Опубликовать курс из очереди модерации. Автор получает уведомление.
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "ID курса"
+// @Param request body ApproveCourseRequest false "Заметка проверяющего"
 // @Success 200 {object} map[string]string
 // @Router /admin/courses/{id}/approve [post]
 func (h *AdminHandler) ApproveCourse(c *gin.Context) {
-	id, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат ID"})
+	id, ok := pathID(c, "id")
+	if !ok {
 		return
 	}
 
-	if err := h.moderationService.ApproveCourse(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	var request ApproveCourseRequest
+	if !bindOptionalJSON(c, &request) {
+		return
+	}
+
+	if err := h.moderationService.ApproveCourse(c.Request.Context(), id, auth.MustUserID(c), request.Note); err != nil {
+		abort(c, err)
 		return
 	}
 
@@ -82,40 +203,67 @@ func (h *AdminHandler) ApproveCourse(c *gin.Context) {
 }
 
 // @Summary Отклонить курс
-// @Description Отклонить курс с указанием причины
+// @Description Отклонить курс с указанием причины. Автор получает уведомление с причиной.
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "ID курса"
-// @Param reason body map[string]string true "Причина отклонения"
+// @Param request body RejectCourseRequest true "Причина отклонения"
 // @Success 200 {object} map[string]string
 // @Router /admin/courses/{id}/reject [post]
 func (h *AdminHandler) RejectCourse(c *gin.Context) {
-	id, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат ID"})
+	id, ok := pathID(c, "id")
+	if !ok {
 		return
 	}
 
-	var body struct {
-		Reason string `json:"reason" binding:"required"`
-	}
-	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var request RejectCourseRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
 		return
 	}
 
-	if err := h.moderationService.RejectCourse(c.Request.Context(), id, body.Reason); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.moderationService.RejectCourse(c.Request.Context(), id, auth.MustUserID(c), request.Reason, request.Note); err != nil {
+		abort(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Курс отклонен"})
 }
 
+// @Summary Снять курс с публикации
+// @Description Перевести опубликованный или отклоненный курс в архив. Автор получает уведомление с причиной.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param request body ArchiveCourseRequest true "Причина"
+// @Success 200 {object} map[string]string
+// @Router /admin/courses/{id}/archive [post]
+func (h *AdminHandler) ArchiveCourse(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var request ArchiveCourseRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	if err := h.moderationService.ArchiveCourse(c.Request.Context(), id, auth.MustUserID(c), request.Reason); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Курс снят с публикации"})
+}
+
 // @Summary Создать курс
-// @Description Создать новый курс
+// @Description Создать курс от имени администратора и сразу отправить его на проверку
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -126,12 +274,12 @@ func (h *AdminHandler) RejectCourse(c *gin.Context) {
 func (h *AdminHandler) CreateCourse(c *gin.Context) {
 	var course models.Course
 	if err := c.ShouldBindJSON(&course); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abort(c, problem.Validation(err))
 		return
 	}
 
-	if err := h.moderationService.CreateCourse(c.Request.Context(), &course); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.moderationService.CreateCourse(c.Request.Context(), &course, auth.MustUserID(c)); err != nil {
+		abort(c, err)
 		return
 	}
 
@@ -151,19 +299,19 @@ func (h *AdminHandler) CreateCourse(c *gin.Context) {
 func (h *AdminHandler) UpdateCourse(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат ID"})
+		abort(c, problem.InvalidID)
 		return
 	}
 
 	var course models.Course
 	if err := c.ShouldBindJSON(&course); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abort(c, problem.Validation(err))
 		return
 	}
 
 	course.ID = id
 	if err := h.moderationService.UpdateCourse(c.Request.Context(), &course); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
@@ -182,12 +330,12 @@ func (h *AdminHandler) UpdateCourse(c *gin.Context) {
 func (h *AdminHandler) DeleteCourse(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат ID"})
+		abort(c, problem.InvalidID)
 		return
 	}
 
 	if err := h.moderationService.DeleteCourse(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
@@ -0,0 +1,852 @@
+package handler
+
+import (
+	"course2/internal/models"
+	"course2/internal/services"
+	"net/http"
+	"platform/auth"
+	"platform/pagination"
+	"platform/problem"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AuthorHandler struct {
+	authoringService    *services.AuthoringService
+	notificationService *services.NotificationService
+}
+
+func NewAuthorHandler(router *gin.Engine, authoringService *services.AuthoringService, notificationService *services.NotificationService, authMiddleware *auth.Middleware) {
+	handler := &AuthorHandler{
+		authoringService:    authoringService,
+		notificationService: notificationService,
+	}
+
+	author := router.Group("/api/v1/edu/author")
+	author.Use(authMiddleware.RequireRoles("author", "admin"))
+	{
+		// Курсы автора
+		author.GET("/courses", handler.ListCourses)
+		author.POST("/courses", handler.CreateCourse)
+		author.GET("/courses/:id", handler.GetCourse)
+		author.PUT("/courses/:id", handler.UpdateCourse)
+		author.DELETE("/courses/:id", handler.DeleteCourse)
+		author.POST("/courses/:id/submit", handler.SubmitCourse)
+		author.GET("/courses/:id/events", handler.ListCourseEvents)
+
+		// Уроки
+		author.GET("/courses/:id/lessons", handler.ListLessons)
+		author.POST("/courses/:id/lessons", handler.AddLesson)
+		author.PUT("/courses/:id/lessons/:lessonId", handler.UpdateLesson)
+		author.DELETE("/courses/:id/lessons/:lessonId", handler.DeleteLesson)
+		author.PUT("/courses/:id/lessons/:lessonId/prerequisites", handler.SetPrerequisites)
+
+		// Тест урока и его вопросы
+		author.GET("/courses/:id/lessons/:lessonId/test", handler.GetTest)
+		author.GET("/courses/:id/lessons/:lessonId/test/analysis", handler.ItemAnalysis)
+		author.POST("/courses/:id/lessons/:lessonId/test", handler.AddTest)
+		author.PUT("/courses/:id/lessons/:lessonId/test", handler.UpdateTest)
+		author.DELETE("/courses/:id/lessons/:lessonId/test", handler.DeleteTest)
+		author.POST("/courses/:id/lessons/:lessonId/test/questions", handler.AddQuestion)
+		author.PUT("/courses/:id/lessons/:lessonId/test/questions/:questionId", handler.UpdateQuestion)
+		author.DELETE("/courses/:id/lessons/:lessonId/test/questions/:questionId", handler.DeleteQuestion)
+
+		// Уведомления о решениях модерации
+		author.GET("/notifications", handler.ListNotifications)
+		author.POST("/notifications/:id/read", handler.MarkNotificationRead)
+	}
+}
+
+// CourseRequest модель запроса для создания и изменения курса
+type CourseRequest struct {
+	Title       string    `json:"title" binding:"required,max=255" example:"Основы Go"`
+	Description string    `json:"description" example:"Курс для начинающих"`
+	CategoryID  uuid.UUID `json:"category_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Level       string    `json:"level" binding:"max=50" example:"beginner"`
+	Duration    int       `json:"duration" binding:"gte=0" example:"120"`
+	Thumbnail   string    `json:"thumbnail" binding:"max=255"`
+	Price       float64   `json:"price" binding:"gte=0" example:"990"`
+	// Progression - порядок прохождения уроков. Если не указан, при создании - free, при изменении - прежний.
+	Progression string `json:"progression" binding:"omitempty,oneof=free sequential prerequisites" example:"sequential"`
+}
+
+// LessonRequest модель запроса для создания и изменения урока.
+// Если order_num не указан, новый урок добавляется в конец курса.
+type LessonRequest struct {
+	Title        string `json:"title" binding:"required,max=255" example:"Переменные и типы"`
+	Content      string `json:"content"`
+	OrderNum     int    `json:"order_num" binding:"gte=0" example:"1"`
+	RequiresTest bool   `json:"requires_test"`
+}
+
+// PrerequisitesRequest модель запроса для предварительных уроков
+type PrerequisitesRequest struct {
+	LessonIDs []uuid.UUID `json:"lesson_ids" binding:"max=100"`
+}
+
+// TestRequest модель запроса для создания и изменения теста урока
+// Вопросы попытки выбираются из пула: question_count вопросов (0 - все), из них по tag_quotas
+// вопросов с заданными тегами. Нулевые max_attempts, cooldown_seconds и time_limit_seconds
+// снимают ограничение. Пустые scoring_policy, late_submission и review_policy при изменении теста не меняются.
+type TestRequest struct {
+	PassingScore     int               `json:"passing_score" binding:"gte=0,lte=100" example:"70"`
+	QuestionCount    int               `json:"question_count" binding:"gte=0,lte=200" example:"10"`
+	TagQuotas        []models.TagQuota `json:"tag_quotas" binding:"omitempty,max=20,dive"`
+	ShuffleQuestions bool              `json:"shuffle_questions" example:"true"`
+	ShuffleOptions   bool              `json:"shuffle_options" example:"true"`
+	MaxAttempts      int               `json:"max_attempts" binding:"gte=0,lte=100" example:"3"`
+	CooldownSeconds  int               `json:"cooldown_seconds" binding:"gte=0,lte=604800" example:"600"`
+	TimeLimitSeconds int               `json:"time_limit_seconds" binding:"gte=0,lte=86400" example:"1800"`
+	ScoringPolicy    string            `json:"scoring_policy" binding:"omitempty,oneof=best last average" example:"best"`
+	LateSubmission   string            `json:"late_submission" binding:"omitempty,oneof=reject grade" example:"reject"`
+	ReviewPolicy     string            `json:"review_policy" binding:"omitempty,oneof=never after_submit after_pass" example:"after_pass"`
+}
+
+// QuestionRequest модель запроса для создания и изменения вопроса. Правильный ответ задается в key,
+// набор полей key зависит от типа вопроса. Для single_choice вместо key можно указать correct_answer -
+// номер правильного варианта, начиная с 0.
+type QuestionRequest struct {
+	Type          string            `json:"type" binding:"omitempty,oneof=single_choice multiple_choice true_false numeric short_text ordering matching" example:"single_choice"`
+	QuestionText  string            `json:"question_text" binding:"required" example:"Какой тип у литерала 1.5?"`
+	Options       []string          `json:"options" binding:"omitempty,dive,required"`
+	Matches       []string          `json:"matches" binding:"omitempty,dive,required"`
+	Points        int               `json:"points" binding:"gte=0,lte=100" example:"1"`
+	Explanation   string            `json:"explanation" binding:"max=5000" example:"Литералы с точкой имеют тип float64"`
+	Key           *models.AnswerKey `json:"key"`
+	CorrectAnswer *int              `json:"correct_answer" binding:"omitempty,gte=0" example:"0"`
+	Tags          []string          `json:"tags" binding:"omitempty,max=20,dive,required,max=50"`
+}
+
+// @Summary Мои курсы
+// @Description Получить курсы автора во всех статусах
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество элементов на странице, не больше 100"
+// @Success 200 {object} pagination.Page[models.Course]
+// @Router /author/courses [get]
+func (h *AuthorHandler) ListCourses(c *gin.Context) {
+	page, ok := pageParams(c, pagination.DefaultLimits)
+	if !ok {
+		return
+	}
+
+	courses, err := h.authoringService.ListCourses(c.Request.Context(), editor(c), page)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, courses)
+}
+
+// @Summary Создать черновик курса
+// @Description Создать курс в статусе draft. Автором курса становится текущий пользователь.
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param course body CourseRequest true "Данные курса"
+// @Success 201 {object} models.Course
+// @Router /author/courses [post]
+func (h *AuthorHandler) CreateCourse(c *gin.Context) {
+	var request CourseRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	course := request.course()
+	if err := h.authoringService.CreateDraft(c.Request.Context(), course, editor(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, course)
+}
+
+// @Summary Получить свой курс
+// @Description Получить курс автора в любом статусе
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Success 200 {object} models.Course
+// @Router /author/courses/{id} [get]
+func (h *AuthorHandler) GetCourse(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	course, err := h.authoringService.GetCourse(c.Request.Context(), id, editor(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, course)
+}
+
+// @Summary Изменить курс
+// @Description Изменить черновик или отклоненный курс. Администратор может изменить курс в любом статусе.
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param course body CourseRequest true "Данные курса"
+// @Success 200 {object} models.Course
+// @Router /author/courses/{id} [put]
+func (h *AuthorHandler) UpdateCourse(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var request CourseRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	course := request.course()
+	course.ID = id
+	if err := h.authoringService.UpdateCourse(c.Request.Context(), course, editor(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, course)
+}
+
+// @Summary Удалить курс
+// @Description Удалить черновик или отклоненный курс
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Success 204 "No Content"
+// @Router /author/courses/{id} [delete]
+func (h *AuthorHandler) DeleteCourse(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.authoringService.DeleteCourse(c.Request.Context(), id, editor(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Отправить курс на проверку
+// @Description Перевести черновик или отклоненный курс в статус pending_review. В курсе должен быть хотя бы один урок.
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Success 200 {object} models.Course
+// @Router /author/courses/{id}/submit [post]
+func (h *AuthorHandler) SubmitCourse(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	course, err := h.authoringService.SubmitForReview(c.Request.Context(), id, editor(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, course)
+}
+
+// @Summary История модерации
+// @Description Получить события модерации своего курса: отправки на проверку, решения и причины отклонения
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество элементов на странице, не больше 100"
+// @Success 200 {object} pagination.Page[models.ModerationEvent]
+// @Router /author/courses/{id}/events [get]
+func (h *AuthorHandler) ListCourseEvents(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	page, ok := pageParams(c, pagination.DefaultLimits)
+	if !ok {
+		return
+	}
+
+	events, err := h.authoringService.ListEvents(c.Request.Context(), id, editor(c), page)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// @Summary Уроки курса
+// @Description Получить уроки своего курса
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Success 200 {array} models.Lesson
+// @Router /author/courses/{id}/lessons [get]
+func (h *AuthorHandler) ListLessons(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	lessons, err := h.authoringService.ListLessons(c.Request.Context(), id, editor(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, lessons)
+}
+
+// @Summary Добавить урок
+// @Description Добавить урок в курс
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param lesson body LessonRequest true "Данные урока"
+// @Success 201 {object} models.Lesson
+// @Router /author/courses/{id}/lessons [post]
+func (h *AuthorHandler) AddLesson(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var request LessonRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	lesson := request.lesson(courseID)
+	if err := h.authoringService.AddLesson(c.Request.Context(), lesson, editor(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, lesson)
+}
+
+// @Summary Изменить урок
+// @Description Изменить урок курса
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param lessonId path string true "ID урока"
+// @Param lesson body LessonRequest true "Данные урока"
+// @Success 200 {object} models.Lesson
+// @Router /author/courses/{id}/lessons/{lessonId} [put]
+func (h *AuthorHandler) UpdateLesson(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	lessonID, ok := pathID(c, "lessonId")
+	if !ok {
+		return
+	}
+
+	var request LessonRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	lesson := request.lesson(courseID)
+	lesson.ID = lessonID
+	if err := h.authoringService.UpdateLesson(c.Request.Context(), lesson, editor(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, lesson)
+}
+
+// @Summary Удалить урок
+// @Description Удалить урок курса вместе с его тестом
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param lessonId path string true "ID урока"
+// @Success 204 "No Content"
+// @Router /author/courses/{id}/lessons/{lessonId} [delete]
+func (h *AuthorHandler) DeleteLesson(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	lessonID, ok := pathID(c, "lessonId")
+	if !ok {
+		return
+	}
+
+	if err := h.authoringService.DeleteLesson(c.Request.Context(), courseID, lessonID, editor(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Предварительные уроки
+// @Description Задать уроки, которые нужно пройти до этого урока. Список заменяет прежний, пустой список снимает требования.
+// @Description Требования действуют, если у курса порядок прохождения prerequisites.
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param lessonId path string true "ID урока"
+// @Param request body PrerequisitesRequest true "ID предварительных уроков"
+// @Success 200 {object} PrerequisitesRequest
+// @Router /author/courses/{id}/lessons/{lessonId}/prerequisites [put]
+func (h *AuthorHandler) SetPrerequisites(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	lessonID, ok := pathID(c, "lessonId")
+	if !ok {
+		return
+	}
+
+	var request PrerequisitesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	ids, err := h.authoringService.SetPrerequisites(c.Request.Context(), courseID, lessonID, request.LessonIDs, editor(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, PrerequisitesRequest{LessonIDs: ids})
+}
+
+// @Summary Тест урока
+// @Description Получить тест урока вместе с правильными ответами
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param lessonId path string true "ID урока"
+// @Success 200 {object} models.TestContent
+// @Router /author/courses/{id}/lessons/{lessonId}/test [get]
+func (h *AuthorHandler) GetTest(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	lessonID, ok := pathID(c, "lessonId")
+	if !ok {
+		return
+	}
+
+	test, err := h.authoringService.GetTest(c.Request.Context(), courseID, lessonID, editor(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, test)
+}
+
+// @Summary Анализ вопросов теста
+// @Description Показатели вопросов по проверенным попыткам всех студентов: индекс трудности (средняя доля баллов),
+// @Description индекс различения (разница между 27% лучших и 27% худших попыток) и частота выбора вариантов
+// @Description для вопросов с выбором ответа. Попытки, отклоненные по времени, не учитываются.
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param lessonId path string true "ID урока"
+// @Success 200 {object} models.ItemAnalysis
+// @Router /author/courses/{id}/lessons/{lessonId}/test/analysis [get]
+func (h *AuthorHandler) ItemAnalysis(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	lessonID, ok := pathID(c, "lessonId")
+	if !ok {
+		return
+	}
+
+	analysis, err := h.authoringService.ItemAnalysis(c.Request.Context(), courseID, lessonID, editor(c))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analysis)
+}
+
+// @Summary Добавить тест
+// @Description Добавить тест к уроку. У урока может быть только один тест.
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param lessonId path string true "ID урока"
+// @Param test body TestRequest true "Данные теста"
+// @Success 201 {object} models.Test
+// @Router /author/courses/{id}/lessons/{lessonId}/test [post]
+func (h *AuthorHandler) AddTest(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	lessonID, ok := pathID(c, "lessonId")
+	if !ok {
+		return
+	}
+
+	var request TestRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	test := request.test(lessonID)
+	if err := h.authoringService.AddTest(c.Request.Context(), courseID, test, editor(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, test)
+}
+
+// @Summary Изменить тест
+// @Description Изменить проходной балл теста урока
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param lessonId path string true "ID урока"
+// @Param test body TestRequest true "Данные теста"
+// @Success 200 {object} models.Test
+// @Router /author/courses/{id}/lessons/{lessonId}/test [put]
+func (h *AuthorHandler) UpdateTest(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	lessonID, ok := pathID(c, "lessonId")
+	if !ok {
+		return
+	}
+
+	var request TestRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	test := request.test(lessonID)
+	if err := h.authoringService.UpdateTest(c.Request.Context(), courseID, test, editor(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, test)
+}
+
+// @Summary Удалить тест
+// @Description Удалить тест урока вместе с вопросами
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param lessonId path string true "ID урока"
+// @Success 204 "No Content"
+// @Router /author/courses/{id}/lessons/{lessonId}/test [delete]
+func (h *AuthorHandler) DeleteTest(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	lessonID, ok := pathID(c, "lessonId")
+	if !ok {
+		return
+	}
+
+	if err := h.authoringService.DeleteTest(c.Request.Context(), courseID, lessonID, editor(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Добавить вопрос
+// @Description Добавить вопрос в тест урока
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param lessonId path string true "ID урока"
+// @Param question body QuestionRequest true "Данные вопроса"
+// @Success 201 {object} models.Question
+// @Router /author/courses/{id}/lessons/{lessonId}/test/questions [post]
+func (h *AuthorHandler) AddQuestion(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	lessonID, ok := pathID(c, "lessonId")
+	if !ok {
+		return
+	}
+
+	var request QuestionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	question := request.question()
+	if err := h.authoringService.AddQuestion(c.Request.Context(), courseID, lessonID, question, editor(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, question)
+}
+
+// @Summary Изменить вопрос
+// @Description Изменить вопрос теста урока
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param lessonId path string true "ID урока"
+// @Param questionId path string true "ID вопроса"
+// @Param question body QuestionRequest true "Данные вопроса"
+// @Success 200 {object} models.Question
+// @Router /author/courses/{id}/lessons/{lessonId}/test/questions/{questionId} [put]
+func (h *AuthorHandler) UpdateQuestion(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	lessonID, ok := pathID(c, "lessonId")
+	if !ok {
+		return
+	}
+	questionID, ok := pathID(c, "questionId")
+	if !ok {
+		return
+	}
+
+	var request QuestionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	question := request.question()
+	question.ID = questionID
+	if err := h.authoringService.UpdateQuestion(c.Request.Context(), courseID, lessonID, question, editor(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, question)
+}
+
+// @Summary Удалить вопрос
+// @Description Удалить вопрос из теста урока
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param lessonId path string true "ID урока"
+// @Param questionId path string true "ID вопроса"
+// @Success 204 "No Content"
+// @Router /author/courses/{id}/lessons/{lessonId}/test/questions/{questionId} [delete]
+func (h *AuthorHandler) DeleteQuestion(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	lessonID, ok := pathID(c, "lessonId")
+	if !ok {
+		return
+	}
+	questionID, ok := pathID(c, "questionId")
+	if !ok {
+		return
+	}
+
+	if err := h.authoringService.DeleteQuestion(c.Request.Context(), courseID, lessonID, questionID, editor(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Уведомления
+// @Description Получить уведомления о решениях модерации, сначала новые
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "Только непрочитанные"
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество элементов на странице, не больше 100"
+// @Success 200 {object} pagination.Page[models.Notification]
+// @Router /author/notifications [get]
+func (h *AuthorHandler) ListNotifications(c *gin.Context) {
+	page, ok := pageParams(c, pagination.DefaultLimits)
+	if !ok {
+		return
+	}
+	unread, _ := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+
+	notifications, err := h.notificationService.ListNotifications(c.Request.Context(), auth.MustUserID(c), unread, page)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// @Summary Прочитать уведомление
+// @Description Отметить уведомление прочитанным
+// @Tags author
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID уведомления"
+// @Success 204 "No Content"
+// @Router /author/notifications/{id}/read [post]
+func (h *AuthorHandler) MarkNotificationRead(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.MarkRead(c.Request.Context(), id, auth.MustUserID(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// editor возвращает пользователя запроса. Администратор может изменять любые курсы.
+func editor(c *gin.Context) services.Editor {
+	return services.Editor{
+		UserID: auth.MustUserID(c),
+		Admin:  auth.Role(c) == "admin",
+	}
+}
+
+func (r *CourseRequest) course() *models.Course {
+	return &models.Course{
+		Title:       r.Title,
+		Description: r.Description,
+		CategoryID:  r.CategoryID,
+		Level:       r.Level,
+		Duration:    r.Duration,
+		Thumbnail:   r.Thumbnail,
+		Price:       r.Price,
+		Progression: r.Progression,
+	}
+}
+
+func (r *LessonRequest) lesson(courseID uuid.UUID) *models.Lesson {
+	return &models.Lesson{
+		CourseID:     courseID,
+		Title:        r.Title,
+		Content:      r.Content,
+		OrderNum:     r.OrderNum,
+		RequiresTest: r.RequiresTest,
+	}
+}
+
+func (r *TestRequest) test(lessonID uuid.UUID) *models.Test {
+	return &models.Test{
+		LessonID:         lessonID,
+		PassingScore:     r.PassingScore,
+		QuestionCount:    r.QuestionCount,
+		TagQuotas:        r.TagQuotas,
+		ShuffleQuestions: r.ShuffleQuestions,
+		ShuffleOptions:   r.ShuffleOptions,
+		MaxAttempts:      r.MaxAttempts,
+		CooldownSeconds:  r.CooldownSeconds,
+		TimeLimitSeconds: r.TimeLimitSeconds,
+		ScoringPolicy:    r.ScoringPolicy,
+		LateSubmission:   r.LateSubmission,
+		ReviewPolicy:     r.ReviewPolicy,
+	}
+}
+
+func (r *QuestionRequest) question() *models.Question {
+	question := &models.Question{
+		Type:         r.Type,
+		QuestionText: r.QuestionText,
+		Options:      r.Options,
+		Matches:      r.Matches,
+		Points:       r.Points,
+		Explanation:  r.Explanation,
+		Key:          r.Key,
+		Tags:         r.Tags,
+	}
+	if question.Type == "" {
+		question.Type = models.QuestionSingleChoice
+	}
+	if question.Points == 0 {
+		question.Points = 1
+	}
+	if question.Key == nil && r.CorrectAnswer != nil && question.Type == models.QuestionSingleChoice {
+		question.Key = &models.AnswerKey{Choices: []int{*r.CorrectAnswer}}
+	}
+	return question
+}
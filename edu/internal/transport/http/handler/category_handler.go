@@ -3,6 +3,7 @@ package handler
 import (
 	"course2/internal/services"
 	"net/http"
+	"platform/pagination"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,16 +24,23 @@ func NewCategoryHandler(router *gin.Engine, categoryService *services.CategorySe
 }
 
 // @Summary Список категорий
-// @Description Получить список всех категорий курсов
+// @Description Получить категории курсов по алфавиту
 // @Tags categories
 // @Accept json
 // @Produce json
-// @Success 200 {array} models.Category
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество элементов на странице, не больше 100"
+// @Success 200 {object} pagination.Page[models.Category]
 // @Router /categories [get]
 func (h *CategoryHandler) ListCategories(c *gin.Context) {
-	categories, err := h.categoryService.ListCategories(c.Request.Context())
+	page, ok := pageParams(c, pagination.DefaultLimits)
+	if !ok {
+		return
+	}
+
+	categories, err := h.categoryService.ListCategories(c.Request.Context(), page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
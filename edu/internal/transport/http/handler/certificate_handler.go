@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"bytes"
+	"course2/internal/services"
+	"fmt"
+	"net/http"
+	"platform/auth"
+	"platform/pagination"
+	"platform/problem"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CertificateHandler struct {
+	certificateService *services.CertificateService
+}
+
+func NewCertificateHandler(router *gin.Engine, certificateService *services.CertificateService, authMiddleware *auth.Middleware) {
+	handler := &CertificateHandler{
+		certificateService: certificateService,
+	}
+
+	certificates := router.Group("/api/v1/edu/certificates")
+	{
+		// Публичная проверка подлинности
+		certificates.GET("/:serial", handler.VerifyCertificate)
+
+		authorized := certificates.Group("")
+		authorized.Use(authMiddleware.RequireRoles())
+		{
+			authorized.GET("/:serial/pdf", handler.DownloadCertificate)
+		}
+	}
+
+	courses := router.Group("/api/v1/edu/courses")
+	courses.Use(authMiddleware.RequireRoles("student", "admin"))
+	{
+		courses.GET("/:id/certificate", handler.GetCourseCertificate)
+	}
+
+	profile := router.Group("/api/v1/edu/profile")
+	profile.Use(authMiddleware.RequireRoles("student", "admin"))
+	{
+		profile.GET("/certificates", handler.ListCertificates)
+	}
+
+	admin := router.Group("/api/v1/edu/admin/certificates")
+	admin.Use(authMiddleware.RequireRoles("admin"))
+	{
+		admin.POST("/:serial/revoke", handler.RevokeCertificate)
+	}
+}
+
+// RevokeCertificateRequest модель запроса для отзыва сертификата
+type RevokeCertificateRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000" example:"Нарушение правил прохождения тестов"`
+}
+
+// @Summary Проверить сертификат
+// @Description Проверить подлинность сертификата по серийному номеру. Отозванный сертификат возвращается с valid=false.
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Param serial path string true "Серийный номер" example(EDU-7K3M-QX9P-2D4F)
+// @Success 200 {object} models.CertificateVerification
+// @Router /certificates/{serial} [get]
+func (h *CertificateHandler) VerifyCertificate(c *gin.Context) {
+	verification, err := h.certificateService.Verify(c.Request.Context(), c.Param("serial"))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, verification)
+}
+
+// @Summary Скачать сертификат
+// @Description Скачать PDF сертификата. Доступно владельцу сертификата и администратору.
+// @Tags certificates
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param serial path string true "Серийный номер"
+// @Success 200 {file} file
+// @Router /certificates/{serial}/pdf [get]
+func (h *CertificateHandler) DownloadCertificate(c *gin.Context) {
+	serial := strings.ToUpper(c.Param("serial"))
+
+	var buf bytes.Buffer
+	if err := h.certificateService.WritePDF(c.Request.Context(), serial, auth.MustUserID(c), auth.Role(c), &buf); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "certificate-"+serial+".pdf"))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// @Summary Сертификат курса
+// @Description Получить сертификат о прохождении курса. Если курс завершен, а сертификат еще не выдан, он выдается при запросе.
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Success 200 {object} models.Certificate
+// @Router /courses/{id}/certificate [get]
+func (h *CertificateHandler) GetCourseCertificate(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	certificate, err := h.certificateService.GetForCourse(c.Request.Context(), auth.MustUserID(c), courseID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, certificate)
+}
+
+// @Summary Мои сертификаты
+// @Description Получить сертификаты текущего пользователя, сначала новые
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество элементов на странице, не больше 100"
+// @Success 200 {object} pagination.Page[models.Certificate]
+// @Router /profile/certificates [get]
+func (h *CertificateHandler) ListCertificates(c *gin.Context) {
+	page, ok := pageParams(c, pagination.DefaultLimits)
+	if !ok {
+		return
+	}
+
+	certificates, err := h.certificateService.ListMine(c.Request.Context(), auth.MustUserID(c), page)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, certificates)
+}
+
+// @Summary Отозвать сертификат
+// @Description Отозвать сертификат. Проверка по серийному номеру будет показывать причину и дату отзыва.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param serial path string true "Серийный номер"
+// @Param request body RevokeCertificateRequest true "Причина"
+// @Success 200 {object} models.Certificate
+// @Router /admin/certificates/{serial}/revoke [post]
+func (h *CertificateHandler) RevokeCertificate(c *gin.Context) {
+	var request RevokeCertificateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	certificate, err := h.certificateService.Revoke(c.Request.Context(), c.Param("serial"), auth.MustUserID(c), strings.TrimSpace(request.Reason))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, certificate)
+}
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"course2/internal/models"
 	"course2/internal/services"
-	"course2/internal/transport/http/middleware"
 	"net/http"
-	"strconv"
+	"platform/auth"
+	"platform/pagination"
+	"platform/problem"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,7 +17,7 @@ type CourseHandler struct {
 	courseService *services.CourseService
 }
 
-func NewCourseHandler(router *gin.Engine, courseService *services.CourseService, authMiddleware *middleware.RolesMiddleware) {
+func NewCourseHandler(router *gin.Engine, courseService *services.CourseService, authMiddleware *auth.Middleware) {
 	handler := &CourseHandler{
 		courseService: courseService,
 	}
@@ -25,34 +28,81 @@ func NewCourseHandler(router *gin.Engine, courseService *services.CourseService,
 		courses.GET("", handler.ListCourses)
 		courses.GET("/:id", handler.GetCourse)
 		courses.GET("/category/:categoryId", handler.ListCoursesByCategory)
-
-		// Защищенные эндпоинты
-		/* authorized := courses.Group("")
-		authorized.Use(authMiddleware.RequireRoles("admin"))
-		{
-			authorized.POST("", handler.CreateCourse)
-			authorized.PUT("/:id", handler.UpdateCourse)
-			authorized.DELETE("/:id", handler.DeleteCourse)
-		} */
 	}
 }
 
-// @Summary Список курсов
-// @Description Получить список всех курсов
+// CatalogQuery - параметры поиска по каталогу курсов
+type CatalogQuery struct {
+	// Query - полнотекстовый поиск по названию и описанию
+	Query       string   `form:"q" binding:"max=200"`
+	CategoryIDs []string `form:"category_id" binding:"dive,uuid"`
+	Levels      []string `form:"level" binding:"dive,max=50"`
+	MinPrice    *float64 `form:"min_price" binding:"omitempty,gte=0"`
+	MaxPrice    *float64 `form:"max_price" binding:"omitempty,gte=0"`
+	Free        bool     `form:"free"`
+	MinRating   *float64 `form:"min_rating" binding:"omitempty,gte=0,lte=5"`
+	MinDuration *int     `form:"min_duration" binding:"omitempty,gte=0"`
+	MaxDuration *int     `form:"max_duration" binding:"omitempty,gte=0"`
+	Sort        string   `form:"sort" binding:"omitempty,oneof=relevance popular rating price_asc price_desc newest"`
+}
+
+// catalogLimits - размер страницы каталога
+var catalogLimits = pagination.Limits{Default: 10, Max: 100}
+
+// @Summary Каталог курсов
+// @Description Поиск по опубликованным курсам с фильтрами, сортировкой и фасетами для боковой панели.
+// @Description Фасеты возвращаются только на первой странице (без cursor). Курсор действителен только для той же сортировки.
+// @Description Параметры category_id и level можно повторять. Без sort курсы с запросом q сортируются по релевантности, без запроса - по популярности.
 // @Tags courses
 // @Accept json
 // @Produce json
-// @Param page query int false "Номер страницы"
-// @Param limit query int false "Количество элементов на странице"
-// @Success 200 {array} models.Course
+// @Param q query string false "Поиск по названию и описанию"
+// @Param category_id query []string false "ID категории" collectionFormat(multi)
+// @Param level query []string false "Уровень" collectionFormat(multi)
+// @Param min_price query number false "Минимальная цена"
+// @Param max_price query number false "Максимальная цена"
+// @Param free query bool false "Только бесплатные"
+// @Param min_rating query number false "Минимальный рейтинг"
+// @Param min_duration query int false "Минимальная длительность"
+// @Param max_duration query int false "Максимальная длительность"
+// @Param sort query string false "Сортировка" Enums(relevance, popular, rating, price_asc, price_desc, newest)
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество элементов на странице, не больше 100"
+// @Success 200 {object} models.CatalogPage
 // @Router /courses [get]
 func (h *CourseHandler) ListCourses(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	var query CatalogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+	page, ok := pageParams(c, catalogLimits)
+	if !ok {
+		return
+	}
 
-	courses, err := h.courseService.ListCourses(c.Request.Context(), page, limit)
+	filter := &models.CourseFilter{
+		Query:       strings.TrimSpace(query.Query),
+		Levels:      query.Levels,
+		MinPrice:    query.MinPrice,
+		MaxPrice:    query.MaxPrice,
+		FreeOnly:    query.Free,
+		MinRating:   query.MinRating,
+		MinDuration: query.MinDuration,
+		MaxDuration: query.MaxDuration,
+	}
+	for _, raw := range query.CategoryIDs {
+		filter.CategoryIDs = append(filter.CategoryIDs, uuid.MustParse(raw))
+	}
+
+	sort := models.CourseSort(query.Sort)
+	if sort == "" {
+		sort = models.CourseSortRelevance
+	}
+
+	courses, err := h.courseService.SearchCourses(c.Request.Context(), filter, sort, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
@@ -70,18 +120,18 @@ func (h *CourseHandler) ListCourses(c *gin.Context) {
 func (h *CourseHandler) GetCourse(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат ID"})
+		abort(c, problem.InvalidID)
 		return
 	}
 
 	course, err := h.courseService.GetCourse(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
 	if course == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Курс не найден"})
+		abort(c, errCourseNotFound)
 		return
 	}
 
@@ -89,28 +139,30 @@ func (h *CourseHandler) GetCourse(c *gin.Context) {
 }
 
 // @Summary Список курсов по категории
-// @Description Получить список курсов в указанной категории
+// @Description Получить опубликованные курсы в указанной категории, сначала новые
 // @Tags courses
 // @Accept json
 // @Produce json
 // @Param categoryId path string true "ID категории"
-// @Param page query int false "Номер страницы"
-// @Param limit query int false "Количество элементов на странице"
-// @Success 200 {array} models.Course
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество элементов на странице, не больше 100"
+// @Success 200 {object} pagination.Page[models.Course]
 // @Router /courses/category/{categoryId} [get]
 func (h *CourseHandler) ListCoursesByCategory(c *gin.Context) {
 	categoryID, err := uuid.Parse(c.Param("categoryId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат ID категории"})
+		abort(c, problem.InvalidID)
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, ok := pageParams(c, catalogLimits)
+	if !ok {
+		return
+	}
 
-	courses, err := h.courseService.ListCoursesByCategory(c.Request.Context(), categoryID, page, limit)
+	courses, err := h.courseService.ListCoursesByCategory(c.Request.Context(), categoryID, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
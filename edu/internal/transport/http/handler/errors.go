@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"course2/internal/grading"
+	"course2/internal/payment"
+	"course2/internal/services"
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Коды ошибок образовательного сервиса
+var (
+	errCourseNotFound = problem.Define("COURSE_NOT_FOUND", http.StatusNotFound, problem.Messages{
+		"ru": "Курс не найден",
+		"en": "Course not found",
+	})
+	errLessonNotFound = problem.Define("LESSON_NOT_FOUND", http.StatusNotFound, problem.Messages{
+		"ru": "Урок не найден",
+		"en": "Lesson not found",
+	})
+	errTestNotFound = problem.Define("TEST_NOT_FOUND", http.StatusNotFound, problem.Messages{
+		"ru": "Тест не найден",
+		"en": "Test not found",
+	})
+	errQuestionNotFound = problem.Define("QUESTION_NOT_FOUND", http.StatusNotFound, problem.Messages{
+		"ru": "Вопрос не найден",
+		"en": "Question not found",
+	})
+	errTestAlreadyExists = problem.Define("TEST_ALREADY_EXISTS", http.StatusConflict, problem.Messages{
+		"ru": "У урока уже есть тест",
+		"en": "Lesson already has a test",
+	})
+	errCourseNotEditable = problem.Define("COURSE_NOT_EDITABLE", http.StatusConflict, problem.Messages{
+		"ru": "Курс нельзя изменить: он на проверке или опубликован",
+		"en": "Course cannot be changed while it is under review or published",
+	})
+	errCourseHasNoLessons = problem.Define("COURSE_HAS_NO_LESSONS", http.StatusUnprocessableEntity, problem.Messages{
+		"ru": "Добавьте в курс хотя бы один урок",
+		"en": "Course must have at least one lesson",
+	})
+	errInvalidCorrectAnswer = problem.Define("INVALID_CORRECT_ANSWER", http.StatusBadRequest, problem.Messages{
+		"ru": "Номер правильного ответа должен указывать на один из вариантов",
+		"en": "Correct answer must point to one of the options",
+	})
+	errInvalidQuestion = problem.Define("INVALID_QUESTION", http.StatusBadRequest, problem.Messages{
+		"ru": "Варианты или правильный ответ не подходят для типа вопроса",
+		"en": "Options or answer key do not match the question type",
+	})
+	errInvalidAnswers = problem.Define("INVALID_ANSWERS", http.StatusBadRequest, problem.Messages{
+		"ru": "Ответы на тест не соответствуют формату",
+		"en": "Test answers do not match the answer schema",
+	})
+	errUnsupportedAnswerVersion = problem.Define("UNSUPPORTED_ANSWER_VERSION", http.StatusBadRequest, problem.Messages{
+		"ru": "Неподдерживаемая версия формата ответов",
+		"en": "Unsupported answer schema version",
+	})
+	errInvalidStatusTransition = problem.Define("INVALID_STATUS_TRANSITION", http.StatusConflict, problem.Messages{
+		"ru": "Недопустимая смена статуса курса",
+		"en": "Invalid course status transition",
+	})
+	errReviewerNotFound = problem.Define("REVIEWER_NOT_FOUND", http.StatusUnprocessableEntity, problem.Messages{
+		"ru": "Проверяющий не найден",
+		"en": "Reviewer not found",
+	})
+	errCourseAssigned = problem.Define("COURSE_ASSIGNED_TO_ANOTHER_REVIEWER", http.StatusConflict, problem.Messages{
+		"ru": "Курс назначен другому проверяющему",
+		"en": "Course is assigned to another reviewer",
+	})
+	errCourseAlreadyPurchased = problem.Define("COURSE_ALREADY_PURCHASED", http.StatusConflict, problem.Messages{
+		"ru": "Курс уже куплен",
+		"en": "Course already purchased",
+	})
+	errCourseNotPurchased = problem.Define("COURSE_NOT_PURCHASED", http.StatusForbidden, problem.Messages{
+		"ru": "Необходимо купить курс",
+		"en": "Course purchase required",
+	})
+	errReviewNotFound = problem.Define("REVIEW_NOT_FOUND", http.StatusNotFound, problem.Messages{
+		"ru": "Отзыв не найден",
+		"en": "Review not found",
+	})
+	errReviewAlreadyExists = problem.Define("REVIEW_ALREADY_EXISTS", http.StatusConflict, problem.Messages{
+		"ru": "Вы уже оставили отзыв на этот курс",
+		"en": "You have already reviewed this course",
+	})
+	errReviewProgressRequired = problem.Define("REVIEW_PROGRESS_REQUIRED", http.StatusForbidden, problem.Messages{
+		"ru": "Пройдите большую часть курса, чтобы оставить отзыв",
+		"en": "Complete more of the course to leave a review",
+	})
+	errOwnReview = problem.Define("OWN_REVIEW", http.StatusConflict, problem.Messages{
+		"ru": "Нельзя оценить свой отзыв или пожаловаться на него",
+		"en": "You cannot vote for or report your own review",
+	})
+	errCourseNotCompleted = problem.Define("COURSE_NOT_COMPLETED", http.StatusConflict, problem.Messages{
+		"ru": "Сертификат выдается после прохождения всех уроков курса",
+		"en": "Certificate is issued after all course lessons are completed",
+	})
+	errCertificateNotFound = problem.Define("CERTIFICATE_NOT_FOUND", http.StatusNotFound, problem.Messages{
+		"ru": "Сертификат не найден",
+		"en": "Certificate not found",
+	})
+	errCertificateRevoked = problem.Define("CERTIFICATE_REVOKED", http.StatusGone, problem.Messages{
+		"ru": "Сертификат отозван",
+		"en": "Certificate has been revoked",
+	})
+	errLessonLocked = problem.Define("LESSON_LOCKED", http.StatusForbidden, problem.Messages{
+		"ru": "Урок откроется после прохождения предыдущих уроков",
+		"en": "Lesson unlocks after the required lessons are completed",
+	})
+	errLessonNotViewed = problem.Define("LESSON_NOT_VIEWED", http.StatusConflict, problem.Messages{
+		"ru": "Сначала откройте урок, затем проходите тест",
+		"en": "View the lesson before taking its test",
+	})
+	errInvalidPrerequisite = problem.Define("INVALID_PREREQUISITE", http.StatusUnprocessableEntity, problem.Messages{
+		"ru": "Предварительным уроком может быть только другой урок этого курса",
+		"en": "Prerequisite must be another lesson of the same course",
+	})
+	errPrerequisiteCycle = problem.Define("PREREQUISITE_CYCLE", http.StatusUnprocessableEntity, problem.Messages{
+		"ru": "Предварительные уроки образуют цикл",
+		"en": "Prerequisites form a cycle",
+	})
+	errInvalidTestSettings = problem.Define("INVALID_TEST_SETTINGS", http.StatusBadRequest, problem.Messages{
+		"ru": "Неверные настройки теста: поле с ошибкой указано в errors",
+		"en": "Invalid test settings: the failing field is listed in errors",
+	})
+	errAttemptNotOpen = problem.Define("ATTEMPT_NOT_OPEN", http.StatusConflict, problem.Messages{
+		"ru": "Попытка уже завершена или не начата. Откройте тест, чтобы начать новую попытку",
+		"en": "Attempt is already submitted or was not started. Open the test to start a new attempt",
+	})
+	errAttemptExpired = problem.Define("ATTEMPT_EXPIRED", http.StatusConflict, problem.Messages{
+		"ru": "Время на попытку истекло, ответы не приняты",
+		"en": "Time for the attempt is over, answers were not accepted",
+	})
+	errAttemptNotFound = problem.Define("ATTEMPT_NOT_FOUND", http.StatusNotFound, problem.Messages{
+		"ru": "Попытка не найдена",
+		"en": "Attempt not found",
+	})
+	errOrderNotFound = problem.Define("ORDER_NOT_FOUND", http.StatusNotFound, problem.Messages{
+		"ru": "Заказ не найден",
+		"en": "Order not found",
+	})
+	errUnknownPaymentProvider = problem.Define("UNKNOWN_PAYMENT_PROVIDER", http.StatusNotFound, problem.Messages{
+		"ru": "Неизвестный платежный провайдер",
+		"en": "Unknown payment provider",
+	})
+	errInvalidSignature = problem.Define("INVALID_SIGNATURE", http.StatusUnauthorized, problem.Messages{
+		"ru": "Неверная подпись уведомления",
+		"en": "Invalid webhook signature",
+	})
+	errInvalidPaymentEvent = problem.Define("INVALID_PAYMENT_EVENT", http.StatusBadRequest, problem.Messages{
+		"ru": "Неверное уведомление провайдера",
+		"en": "Invalid payment event",
+	})
+	errPaymentMismatch = problem.Define("PAYMENT_MISMATCH", http.StatusUnprocessableEntity, problem.Messages{
+		"ru": "Сумма оплаты не совпадает с заказом",
+		"en": "Payment amount does not match the order",
+	})
+	errInvalidTestScore = problem.Define("INVALID_TEST_SCORE", http.StatusBadRequest, problem.Messages{
+		"ru": "Неверный результат теста",
+		"en": "Invalid test score",
+	})
+)
+
+// errorMapping сопоставляет ошибки сервисов и репозиториев кодам ответов.
+// Ошибки, которых нет в таблице, отдаются как INTERNAL_ERROR и пишутся в журнал.
+var errorMapping = problem.Mapping{
+	problem.Map(errCourseNotFound, services.ErrCourseNotFound),
+	problem.Map(errCourseAlreadyPurchased, services.ErrCourseAlreadyPurchased),
+	problem.Map(problem.Forbidden, services.ErrInsufficientPermissions),
+	problem.Map(errInvalidTestScore, services.ErrInvalidTestScore),
+	problem.Map(errLessonNotFound, services.ErrLessonNotFound),
+	problem.Map(errTestNotFound, services.ErrTestNotFound),
+	problem.Map(errQuestionNotFound, services.ErrQuestionNotFound),
+	problem.Map(errTestAlreadyExists, services.ErrTestAlreadyExists),
+	problem.Map(errCourseNotEditable, services.ErrCourseNotEditable),
+	problem.Map(errCourseHasNoLessons, services.ErrCourseHasNoLessons),
+	problem.Map(errInvalidCorrectAnswer, grading.ErrCorrectAnswerOutOfRange),
+	problem.Map(errInvalidQuestion, grading.ErrInvalidQuestion),
+	problem.Map(errInvalidAnswers, grading.ErrInvalidSubmission),
+	problem.Map(errUnsupportedAnswerVersion, grading.ErrUnsupportedVersion),
+	problem.Map(errInvalidStatusTransition, services.ErrInvalidStatusTransition),
+	problem.Map(errReviewerNotFound, services.ErrReviewerNotFound),
+	problem.Map(errCourseAssigned, services.ErrCourseAssigned),
+	problem.Map(errCourseNotPurchased, services.ErrCourseNotPurchased),
+	problem.Map(errReviewNotFound, services.ErrReviewNotFound),
+	problem.Map(errReviewAlreadyExists, services.ErrReviewAlreadyExists),
+	problem.Map(errReviewProgressRequired, services.ErrReviewProgressRequired),
+	problem.Map(errOwnReview, services.ErrOwnReview),
+	problem.Map(errCourseNotCompleted, services.ErrCourseNotCompleted),
+	problem.Map(errCertificateNotFound, services.ErrCertificateNotFound),
+	problem.Map(errCertificateRevoked, services.ErrCertificateRevoked),
+	problem.Map(errLessonLocked, services.ErrLessonLocked),
+	problem.Map(errLessonNotViewed, services.ErrLessonNotViewed),
+	problem.Map(errInvalidPrerequisite, services.ErrInvalidPrerequisite),
+	problem.Map(errPrerequisiteCycle, services.ErrPrerequisiteCycle),
+	problem.Map(errInvalidTestSettings, services.ErrInvalidTestSettings),
+	problem.Map(errAttemptNotOpen, services.ErrAttemptNotOpen),
+	problem.Map(errAttemptExpired, services.ErrAttemptExpired),
+	problem.Map(errAttemptNotFound, services.ErrAttemptNotFound),
+	problem.Map(errOrderNotFound, services.ErrOrderNotFound),
+	problem.Map(errUnknownPaymentProvider, services.ErrUnknownPaymentProvider),
+	problem.Map(errInvalidSignature, payment.ErrInvalidSignature),
+	problem.Map(errInvalidPaymentEvent, payment.ErrInvalidEvent),
+	problem.Map(errPaymentMismatch, services.ErrPaymentMismatch),
+	problem.Map(problem.NotFound, sql.ErrNoRows),
+}
+
+// abort отвечает ошибкой в формате application/problem+json и прерывает обработку запроса.
+// Для заблокированного урока в ответ добавляются причина и уроки, которые нужно пройти,
+// для неверных настроек теста - поле с ошибкой.
+func abort(c *gin.Context, err error) {
+	var locked *services.LessonLockedError
+	if errors.As(err, &locked) {
+		_ = c.Error(err)
+		problem.AbortWith(c, problem.New(c.Request, errLessonLocked).
+			With("lock_reason", locked.Reason).
+			With("blocked_by", locked.BlockedBy))
+		return
+	}
+
+	// Неверная настройка теста: в ответе поле и нарушенное правило, как в INVALID_REQUEST
+	var settings *services.TestSettingsError
+	if errors.As(err, &settings) {
+		_ = c.Error(err)
+		p := problem.New(c.Request, errInvalidTestSettings)
+		p.Errors = []problem.FieldError{{Field: settings.Field, Rule: settings.Rule}}
+		problem.AbortWith(c, p)
+		return
+	}
+
+	errorMapping.Abort(c, err)
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"platform/pagination"
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// pathID разбирает UUID из параметра пути и при ошибке отвечает INVALID_ID
+func pathID(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		abort(c, problem.InvalidID)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// pageParams читает параметры страницы списка и при ошибке отвечает INVALID_LIMIT
+func pageParams(c *gin.Context, limits pagination.Limits) (pagination.Params, bool) {
+	page, err := pagination.FromRequest(c, limits)
+	if err != nil {
+		abort(c, err)
+		return pagination.Params{}, false
+	}
+	return page, true
+}
+
+// bindOptionalJSON разбирает тело запроса, если оно есть
+func bindOptionalJSON(c *gin.Context, obj any) bool {
+	if c.Request.ContentLength == 0 {
+		return true
+	}
+	if err := c.ShouldBindJSON(obj); err != nil {
+		abort(c, problem.Validation(err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"course2/internal/models"
+	"course2/internal/payment"
+	"course2/internal/services"
+	"fmt"
+	"net/http"
+	"platform/auth"
+	"platform/problem"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PaymentHandler struct {
+	paymentService *services.PaymentService
+	fake           *payment.Fake
+}
+
+// fakeOutcomes - результаты, которыми студент может завершить оплату на странице fake.
+// Возврат приходит только уведомлением провайдера.
+var fakeOutcomes = []string{models.OrderPaid, models.OrderFailed}
+
+// NewPaymentHandler регистрирует уведомления провайдера и статус заказа. fake - провайдер для разработки:
+// если он задан, регистрируется страница оплаты, на которой студент завершает оплату своего заказа.
+func NewPaymentHandler(router *gin.Engine, paymentService *services.PaymentService, fake *payment.Fake, authMiddleware *auth.Middleware) {
+	handler := &PaymentHandler{
+		paymentService: paymentService,
+		fake:           fake,
+	}
+
+	payments := router.Group("/api/v1/edu/payments")
+	{
+		// Уведомления провайдера приходят без токена, их подлинность проверяется подписью
+		payments.POST("/webhook/:provider", handler.Webhook)
+	}
+
+	if fake != nil {
+		sessions := router.Group("/api/v1/edu/payments/fake/sessions")
+		sessions.Use(authMiddleware.RequireRoles("student"))
+		{
+			sessions.GET("/:sessionId", handler.FakeCheckout)
+			sessions.POST("/:sessionId/:outcome", handler.FakeComplete)
+		}
+	}
+
+	orders := router.Group("/api/v1/edu/student/orders")
+	orders.Use(authMiddleware.RequireRoles("student"))
+	{
+		orders.GET("/:orderId", handler.GetOrder)
+	}
+}
+
+// @Summary Уведомление платежного провайдера
+// @Description Принимает подписанное уведомление провайдера о платеже. Подпись проверяется (INVALID_SIGNATURE),
+// @Description оплата выдает курс, возврат отзывает его. Повторная доставка того же события ничего не меняет.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param provider path string true "Имя провайдера" example(fake)
+// @Success 200 {object} map[string]interface{}
+// @Router /payments/webhook/{provider} [post]
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	if err := h.paymentService.HandleWebhook(c.Request.Context(), c.Param("provider"), c.Request.Header, body); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
+
+// @Summary Статус заказа
+// @Description Заказ текущего студента: created, pending (ждет оплаты на checkout_url), paid, failed или refunded
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param orderId path string true "ID заказа"
+// @Success 200 {object} models.Order
+// @Router /student/orders/{orderId} [get]
+func (h *PaymentHandler) GetOrder(c *gin.Context) {
+	orderID, ok := pathID(c, "orderId")
+	if !ok {
+		return
+	}
+
+	order, err := h.paymentService.GetOrder(c.Request.Context(), auth.MustUserID(c), orderID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// FakeCheckoutResponse - страница оплаты провайдера для разработки
+type FakeCheckoutResponse struct {
+	SessionID string        `json:"session_id"`
+	Order     *models.Order `json:"order"`
+	// Outcomes - адреса, POST на которые завершает оплату с этим результатом
+	Outcomes map[string]string `json:"outcomes"`
+}
+
+// @Summary Страница оплаты (для разработки)
+// @Description Есть только при PAYMENT_FAKE_CHECKOUT=true. Показывает заказ сессии текущего студента
+// @Description и адреса, которые завершают оплату.
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param sessionId path string true "ID платежной сессии"
+// @Success 200 {object} FakeCheckoutResponse
+// @Router /payments/fake/sessions/{sessionId} [get]
+func (h *PaymentHandler) FakeCheckout(c *gin.Context) {
+	sessionID := c.Param("sessionId")
+
+	order, err := h.paymentService.GetOrderBySession(c.Request.Context(), auth.MustUserID(c), sessionID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	outcomes := make(map[string]string)
+	for _, outcome := range fakeOutcomes {
+		outcomes[outcome] = c.Request.URL.Path + "/" + outcome
+	}
+
+	c.JSON(http.StatusOK, &FakeCheckoutResponse{
+		SessionID: sessionID,
+		Order:     order,
+		Outcomes:  outcomes,
+	})
+}
+
+// @Summary Завершить оплату (для разработки)
+// @Description Есть только при PAYMENT_FAKE_CHECKOUT=true. Создает подписанное уведомление с результатом outcome
+// @Description для заказа текущего студента и обрабатывает его так же, как уведомление от провайдера.
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param sessionId path string true "ID платежной сессии"
+// @Param outcome path string true "Результат оплаты" Enums(paid, failed)
+// @Success 200 {object} models.Order
+// @Router /payments/fake/sessions/{sessionId}/{outcome} [post]
+func (h *PaymentHandler) FakeComplete(c *gin.Context) {
+	sessionID := c.Param("sessionId")
+	userID := auth.MustUserID(c)
+
+	outcome := c.Param("outcome")
+	if !slices.Contains(fakeOutcomes, outcome) {
+		abort(c, problem.Validation(fmt.Errorf("неизвестный результат оплаты %q", outcome)))
+		return
+	}
+
+	order, err := h.paymentService.GetOrderBySession(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	header, body, err := h.fake.Sign(&payment.Event{
+		Type:      outcome,
+		SessionID: sessionID,
+		PaymentID: "pay_fake_" + uuid.NewString(),
+		Amount:    order.Amount,
+		Currency:  order.Currency,
+	})
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	if err := h.paymentService.HandleWebhook(c.Request.Context(), h.fake.Name(), header, body); err != nil {
+		abort(c, err)
+		return
+	}
+
+	order, err = h.paymentService.GetOrderBySession(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
@@ -3,20 +3,24 @@ package handler
 import (
 	"course2/internal/models"
 	"course2/internal/services"
-	"course2/internal/transport/http/middleware"
 	"net/http"
+	"platform/auth"
+	"platform/pagination"
+	"platform/problem"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 type ProfileHandler struct {
-	userService *services.UserService
+	userService         *services.UserService
+	notificationService *services.NotificationService
 }
 
-func NewProfileHandler(router *gin.Engine, userService *services.UserService, authMiddleware *middleware.RolesMiddleware) {
+func NewProfileHandler(router *gin.Engine, userService *services.UserService, notificationService *services.NotificationService, authMiddleware *auth.Middleware) {
 	handler := &ProfileHandler{
-		userService: userService,
+		userService:         userService,
+		notificationService: notificationService,
 	}
 
 	profile := router.Group("/api/v1/edu/profile")
@@ -26,6 +30,8 @@ func NewProfileHandler(router *gin.Engine, userService *services.UserService, au
 		profile.PUT("", handler.UpdateProfile)
 		profile.GET("/courses", handler.GetPurchasedCourses)
 		profile.GET("/xp", handler.GetTotalXP)
+		profile.GET("/notifications", handler.ListNotifications)
+		profile.POST("/notifications/:id/read", handler.MarkNotificationRead)
 	}
 }
 
@@ -38,15 +44,15 @@ func NewProfileHandler(router *gin.Engine, userService *services.UserService, au
 // @Success 200 {object} models.UserProfile
 // @Router /profile [get]
 func (h *ProfileHandler) GetProfile(c *gin.Context) {
-	userID, exists := c.Get("user_id")
+	userID, exists := auth.UserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+		abort(c, problem.Unauthorized)
 		return
 	}
 
-	profile, err := h.userService.GetProfile(c.Request.Context(), userID.(uuid.UUID))
+	profile, err := h.userService.GetProfile(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
@@ -63,21 +69,21 @@ func (h *ProfileHandler) GetProfile(c *gin.Context) {
 // @Success 200 {object} models.UserProfile
 // @Router /profile [put]
 func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
-	userID, exists := c.Get("user_id")
+	userID, exists := auth.UserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+		abort(c, problem.Unauthorized)
 		return
 	}
 
 	var profile models.UserProfile
 	if err := c.ShouldBindJSON(&profile); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abort(c, problem.Validation(err))
 		return
 	}
 
-	profile.ID = userID.(uuid.UUID)
+	profile.ID = userID
 	if err := h.userService.UpdateProfile(c.Request.Context(), &profile); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
@@ -85,23 +91,30 @@ func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 }
 
 // @Summary Получить купленные курсы
-// @Description Получить список купленных курсов текущего пользователя
+// @Description Получить купленные курсы текущего пользователя, сначала новые
 // @Tags profile
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} models.Course
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество элементов на странице, не больше 100"
+// @Success 200 {object} pagination.Page[models.PurchasedCourse]
 // @Router /profile/courses [get]
 func (h *ProfileHandler) GetPurchasedCourses(c *gin.Context) {
-	userID, exists := c.Get("user_id")
+	userID, exists := auth.UserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+		abort(c, problem.Unauthorized)
 		return
 	}
 
-	courses, err := h.userService.GetPurchasedCourses(c.Request.Context(), userID.(uuid.UUID))
+	page, ok := pageParams(c, pagination.DefaultLimits)
+	if !ok {
+		return
+	}
+
+	courses, err := h.userService.GetPurchasedCourses(c.Request.Context(), userID, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
@@ -117,17 +130,67 @@ func (h *ProfileHandler) GetPurchasedCourses(c *gin.Context) {
 // @Success 200 {object} map[string]int
 // @Router /profile/xp [get]
 func (h *ProfileHandler) GetTotalXP(c *gin.Context) {
-	userID, exists := c.Get("user_id")
+	userID, exists := auth.UserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+		abort(c, problem.Unauthorized)
 		return
 	}
 
-	xp, err := h.userService.GetTotalXP(c.Request.Context(), userID.(uuid.UUID))
+	xp, err := h.userService.GetTotalXP(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"total_xp": xp})
 }
+
+// @Summary Уведомления
+// @Description Получить уведомления студента, например о завершении курса, сначала новые
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "Только непрочитанные"
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество элементов на странице, не больше 100"
+// @Success 200 {object} pagination.Page[models.Notification]
+// @Router /profile/notifications [get]
+func (h *ProfileHandler) ListNotifications(c *gin.Context) {
+	page, ok := pageParams(c, pagination.DefaultLimits)
+	if !ok {
+		return
+	}
+	unread, _ := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+
+	notifications, err := h.notificationService.ListNotifications(c.Request.Context(), auth.MustUserID(c), unread, page)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// @Summary Прочитать уведомление
+// @Description Отметить уведомление прочитанным
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID уведомления"
+// @Success 204 "No Content"
+// @Router /profile/notifications/{id}/read [post]
+func (h *ProfileHandler) MarkNotificationRead(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.MarkRead(c.Request.Context(), id, auth.MustUserID(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"course2/internal/grading"
+	"course2/internal/metrics"
 	"course2/internal/models"
 	"course2/internal/repositories"
 	"course2/internal/services"
-	"course2/internal/transport/http/middleware"
 	"net/http"
+	"platform/auth"
+	"platform/pagination"
+	"platform/problem"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -13,19 +17,25 @@ import (
 )
 
 type ProgressHandler struct {
-	courseService *services.CourseService
-	progressRepo  *repositories.ProgressRepository
+	courseService   *services.CourseService
+	progressService *services.ProgressService
+	testService     *services.TestService
+	progressRepo    *repositories.ProgressRepository
 }
 
 func NewProgressHandler(
 	router *gin.Engine,
 	courseService *services.CourseService,
+	progressService *services.ProgressService,
+	testService *services.TestService,
 	progressRepo *repositories.ProgressRepository,
-	authMiddleware *middleware.RolesMiddleware,
+	authMiddleware *auth.Middleware,
 ) {
 	handler := &ProgressHandler{
-		courseService: courseService,
-		progressRepo:  progressRepo,
+		courseService:   courseService,
+		progressService: progressService,
+		testService:     testService,
+		progressRepo:    progressRepo,
 	}
 
 	progress := router.Group("/api/v1/edu/progress")
@@ -34,6 +44,8 @@ func NewProgressHandler(
 		progress.GET("/courses/:courseId", handler.GetCourseProgress)
 		progress.POST("/lessons/:lessonId/view", handler.MarkLessonViewed)
 		progress.POST("/lessons/:lessonId/test", handler.SubmitTest)
+		progress.GET("/lessons/:lessonId/test/attempts", handler.ListAttempts)
+		progress.GET("/lessons/:lessonId/test/attempts/:attemptId", handler.ReviewAttempt)
 	}
 }
 
@@ -47,21 +59,21 @@ func NewProgressHandler(
 // @Success 200 {object} models.CourseProgress
 // @Router /progress/courses/{courseId} [get]
 func (h *ProgressHandler) GetCourseProgress(c *gin.Context) {
-	userID, exists := c.Get("user_id")
+	userID, exists := auth.UserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+		abort(c, problem.Unauthorized)
 		return
 	}
 
 	courseID, err := uuid.Parse(c.Param("courseId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат ID курса"})
+		abort(c, problem.InvalidID)
 		return
 	}
 
-	progress, err := h.progressRepo.GetCourseProgress(c.Request.Context(), userID.(uuid.UUID), courseID)
+	progress, err := h.progressRepo.GetCourseProgress(c.Request.Context(), userID, courseID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
@@ -69,7 +81,9 @@ func (h *ProgressHandler) GetCourseProgress(c *gin.Context) {
 }
 
 // @Summary Отметить урок как просмотренный
-// @Description Отметить урок как просмотренный и начислить XP
+// @Description Отметить урок как просмотренный и начислить XP. Заблокированный урок отметить нельзя (LESSON_LOCKED).
+// @Description Если урок без теста был последним непройденным уроком курса, в ответе есть course_completion
+// @Description с бонусом за завершение курса.
 // @Tags progress
 // @Accept json
 // @Produce json
@@ -78,34 +92,46 @@ func (h *ProgressHandler) GetCourseProgress(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Router /progress/lessons/{lessonId}/view [post]
 func (h *ProgressHandler) MarkLessonViewed(c *gin.Context) {
-	userID, exists := c.Get("user_id")
+	userID, exists := auth.UserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+		abort(c, problem.Unauthorized)
 		return
 	}
 
 	lessonID, err := uuid.Parse(c.Param("lessonId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат ID урока"})
+		abort(c, problem.InvalidID)
 		return
 	}
 
 	// Получаем информацию об уроке
 	lesson, err := h.courseService.GetLesson(c.Request.Context(), lessonID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
+		return
+	}
+	if lesson == nil {
+		abort(c, errLessonNotFound)
+		return
+	}
+
+	// Проверяем, куплен ли курс и открыт ли урок
+	if err := h.progressService.CheckLessonAccess(c.Request.Context(), userID, lesson); err != nil {
+		abort(c, err)
 		return
 	}
 
 	// Проверяем существующий прогресс
-	existingProgress, err := h.progressRepo.GetLessonProgress(c.Request.Context(), userID.(uuid.UUID), lessonID)
+	existingProgress, err := h.progressRepo.GetLessonProgress(c.Request.Context(), userID, lessonID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
 	now := time.Now()
 
+	firstView := existingProgress == nil || existingProgress.ViewedAt == nil
+
 	if existingProgress != nil {
 		// Обновляем существующую запись
 		if existingProgress.ViewedAt == nil {
@@ -117,14 +143,14 @@ func (h *ProgressHandler) MarkLessonViewed(c *gin.Context) {
 		}
 
 		if err := h.progressRepo.UpdateLessonProgress(c.Request.Context(), existingProgress); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			abort(c, err)
 			return
 		}
 	} else {
 		// Создаем новую запись
 		progress := &models.LessonProgress{
 			ID:          uuid.New(),
-			UserID:      userID.(uuid.UUID),
+			UserID:      userID,
 			LessonID:    lessonID,
 			ViewedAt:    &now,
 			IsCompleted: !lesson.RequiresTest,
@@ -136,14 +162,14 @@ func (h *ProgressHandler) MarkLessonViewed(c *gin.Context) {
 		}
 
 		if err := h.progressRepo.CreateLessonProgress(c.Request.Context(), progress); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			abort(c, err)
 			return
 		}
 
 		// Начисляем XP за просмотр урока только при первом просмотре
 		xpEntry := &models.XPEntry{
 			ID:       uuid.New(),
-			UserID:   userID.(uuid.UUID),
+			UserID:   userID,
 			CourseID: lesson.CourseID,
 			LessonID: lessonID,
 			Type:     "lesson_view",
@@ -151,149 +177,276 @@ func (h *ProgressHandler) MarkLessonViewed(c *gin.Context) {
 		}
 
 		if err := h.progressRepo.AddXPEntry(c.Request.Context(), xpEntry); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			abort(c, err)
 			return
 		}
 	}
 
+	if firstView {
+		metrics.LessonsViewed.Inc()
+	}
+
 	xpAwarded := 0
 	if existingProgress == nil {
 		xpAwarded = 5
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"message":    "Урок отмечен как просмотренный",
 		"xp_awarded": xpAwarded,
-	})
+	}
+
+	// Урок без теста пройден просмотром: проверяем, не завершен ли курс
+	if !lesson.RequiresTest {
+		completion, err := h.progressService.CompleteCourse(c.Request.Context(), userID, lesson.CourseID)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+		if completion != nil {
+			response["course_completion"] = completion
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // @Summary Отправить ответы на тест
-// @Description Отправить ответы на тест и получить результат
+// @Description Отправить ответы на тест и получить результат. Тест можно сдать после покупки курса и просмотра урока
+// @Description (LESSON_NOT_VIEWED), если урок не заблокирован (LESSON_LOCKED). Если урок был последним
+// @Description непройденным уроком курса, в ответе есть course_completion с бонусом за завершение курса.
 // @Tags progress
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param lessonId path string true "ID урока"
-// @Param answers body map[string]int true "Ответы на вопросы"
+// @Description Ответы проверяются по вопросам попытки, начатой при открытии теста; номера вариантов - в порядке,
+// @Description показанном в попытке. Без незавершенной попытки или при несовпадении attempt_id - ATTEMPT_NOT_OPEN.
+// @Description Формат ответов версии 2: {"version": 2, "attempt_id": "...", "answers": {"<id вопроса>": {...}}}, поля ответа зависят от типа вопроса.
+// @Description Тело без version считается версией 1: {"<id вопроса>": <номер варианта>}.
+// @Description В ответе results - баллы и пояснение по каждому вопросу, score - результат этой попытки,
+// @Description test_score - результат урока по правилу подсчета теста (scoring_policy). Ответы после истечения
+// @Description времени отклоняются с ATTEMPT_EXPIRED или проверяются с late=true - по настройке late_submission.
+// @Param answers body grading.Submission true "Ответы на вопросы"
 // @Success 200 {object} map[string]interface{}
 // @Router /progress/lessons/{lessonId}/test [post]
 func (h *ProgressHandler) SubmitTest(c *gin.Context) {
-	userID, exists := c.Get("user_id")
+	userID, exists := auth.UserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Пользователь не авторизован"})
+		abort(c, problem.Unauthorized)
 		return
 	}
 
 	lessonID, err := uuid.Parse(c.Param("lessonId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат ID урока"})
+		abort(c, problem.InvalidID)
 		return
 	}
 
-	var answers map[string]int
-	if err := c.ShouldBindJSON(&answers); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	body, err := c.GetRawData()
+	if err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+	submission, err := grading.ParseSubmission(body)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	// Получаем информацию об уроке для course_id
+	lesson, err := h.courseService.GetLesson(c.Request.Context(), lessonID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	if lesson == nil {
+		abort(c, errLessonNotFound)
+		return
+	}
+
+	// Проверяем, куплен ли курс и открыт ли урок
+	if err := h.progressService.CheckLessonAccess(c.Request.Context(), userID, lesson); err != nil {
+		abort(c, err)
+		return
+	}
+
+	// Получаем текущий прогресс: тест сдается после просмотра урока
+	currentProgress, err := h.progressRepo.GetLessonProgress(c.Request.Context(), userID, lessonID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	if currentProgress == nil || currentProgress.ViewedAt == nil {
+		abort(c, errLessonNotViewed)
 		return
 	}
 
 	// Получаем тест для урока
 	test, err := h.courseService.GetTestByLessonID(c.Request.Context(), lessonID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
 	if test == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Тест не найден"})
+		abort(c, errTestNotFound)
 		return
 	}
 
-	// Получаем информацию об уроке для course_id
-	lesson, err := h.courseService.GetLesson(c.Request.Context(), lessonID)
+	// Проверяем ответы по вопросам попытки и завершаем ее
+	attempt, graded, err := h.testService.SubmitAttempt(c.Request.Context(), userID, test, submission.AttemptID, submission.Answers)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	// Результат урока считается по всем попыткам согласно правилу подсчета теста
+	status, err := h.testService.Status(c.Request.Context(), userID, test)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
+	score := graded.Score
+	testScore := *status.Score
+	passed := testScore >= test.PassingScore
 
-	// Проверяем ответы и вычисляем результат
-	questions, err := h.courseService.GetTestQuestions(c.Request.Context(), test.ID)
+	// Сохраняем результат урока, начисляем XP и проверяем завершение курса
+	recorded, err := h.progressService.RecordTestResult(c.Request.Context(), userID, lesson, currentProgress, testScore, passed)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
-	correctAnswers := 0
-	for _, q := range questions {
-		if answer, ok := answers[q.ID.String()]; ok && answer == q.CorrectAnswer {
-			correctAnswers++
-		}
+	result := metrics.ResultFailed
+	if score >= test.PassingScore {
+		result = metrics.ResultPassed
+	}
+	metrics.TestsSubmitted.WithLabelValues(result).Inc()
+
+	response := gin.H{
+		"message":         "Тест не пройден",
+		"score":           score,
+		"late":            attempt.Late,
+		"test_score":      testScore,
+		"scoring_policy":  test.ScoringPolicy,
+		"attempts_used":   status.AttemptsUsed,
+		"attempt_id":      attempt.ID,
+		"attempts_left":   status.AttemptsLeft,
+		"next_attempt_at": status.NextAttemptAt,
+		"points":          graded.Points,
+		"max_points":      graded.MaxPoints,
+		"results":         graded.Questions,
+	}
+	if passed {
+		response["message"] = "Тест успешно пройден"
+	}
+	if recorded.XPAwarded > 0 {
+		response["xp_awarded"] = recorded.XPAwarded
+	}
+	if recorded.Completion != nil {
+		response["course_completion"] = recorded.Completion
 	}
 
-	score := (correctAnswers * 100) / len(questions)
-	passed := score >= test.PassingScore
+	c.JSON(http.StatusOK, response)
+}
 
-	// Получаем текущий прогресс
-	currentProgress, err := h.progressRepo.GetLessonProgress(c.Request.Context(), userID.(uuid.UUID), lessonID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+// @Summary История попыток теста
+// @Description Завершенные попытки студента, начиная с последней: результат, баллы, время прохождения и отметка
+// @Description об опоздании. Попытка, отклоненная по времени, имеет результат 0.
+// @Tags progress
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param lessonId path string true "ID урока"
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество элементов на странице, не больше 100"
+// @Success 200 {object} pagination.Page[models.AttemptSummary]
+// @Router /progress/lessons/{lessonId}/test/attempts [get]
+func (h *ProgressHandler) ListAttempts(c *gin.Context) {
+	userID := auth.MustUserID(c)
+
+	page, ok := pageParams(c, pagination.DefaultLimits)
+	if !ok {
+		return
+	}
+	test, ok := h.lessonTest(c, userID)
+	if !ok {
 		return
 	}
 
-	now := time.Now()
-	progress := &models.LessonProgress{
-		ID:            uuid.New(),
-		UserID:        userID.(uuid.UUID),
-		LessonID:      lessonID,
-		TestScore:     &score,
-		PassedTest:    passed,
-		LastAttemptAt: &now,
-		IsCompleted:   passed,
-		AttemptsCount: 1,
+	attempts, err := h.testService.ListAttempts(c.Request.Context(), userID, test, page)
+	if err != nil {
+		abort(c, err)
+		return
 	}
 
-	if currentProgress != nil {
-		progress.ID = currentProgress.ID
-		progress.AttemptsCount = currentProgress.AttemptsCount + 1
-		progress.ViewedAt = currentProgress.ViewedAt
+	c.JSON(http.StatusOK, attempts)
+}
+
+// @Summary Разбор попытки теста
+// @Description Вопросы завершенной попытки в порядке попытки с ответами студента, баллами и пояснениями.
+// @Description Правильные ответы (question.key) есть, если их разрешает review_policy теста: after_submit - всегда,
+// @Description after_pass - после прохождения теста. Номера вариантов - в порядке, показанном в попытке.
+// @Tags progress
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param lessonId path string true "ID урока"
+// @Param attemptId path string true "ID попытки"
+// @Success 200 {object} models.AttemptReview
+// @Router /progress/lessons/{lessonId}/test/attempts/{attemptId} [get]
+func (h *ProgressHandler) ReviewAttempt(c *gin.Context) {
+	userID := auth.MustUserID(c)
+
+	attemptID, ok := pathID(c, "attemptId")
+	if !ok {
+		return
 	}
 
-	// Если тест пройден, устанавливаем completed_at
-	if passed {
-		progress.CompletedAt = &now
+	test, ok := h.lessonTest(c, userID)
+	if !ok {
+		return
 	}
 
-	if err := h.progressRepo.UpdateLessonProgress(c.Request.Context(), progress); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	review, err := h.testService.ReviewAttempt(c.Request.Context(), userID, test, attemptID)
+	if err != nil {
+		abort(c, err)
 		return
 	}
 
-	// Если тест пройден, начисляем XP
-	if passed {
-		xpEntry := &models.XPEntry{
-			ID:       uuid.New(),
-			UserID:   userID.(uuid.UUID),
-			CourseID: lesson.CourseID,
-			LessonID: lessonID,
-			Type:     "test_pass",
-			Amount:   10,
-		}
+	c.JSON(http.StatusOK, review)
+}
 
-		if err := h.progressRepo.AddXPEntry(c.Request.Context(), xpEntry); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+// lessonTest возвращает тест урока из пути, если студенту открыт урок. Иначе отвечает ошибкой.
+func (h *ProgressHandler) lessonTest(c *gin.Context, userID uuid.UUID) (*models.Test, bool) {
+	lessonID, ok := pathID(c, "lessonId")
+	if !ok {
+		return nil, false
+	}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message":       "Тест успешно пройден",
-			"score":         score,
-			"xp_awarded":    10,
-			"attempts_used": progress.AttemptsCount,
-		})
-	} else {
-		c.JSON(http.StatusOK, gin.H{
-			"message":       "Тест не пройден",
-			"score":         score,
-			"attempts_used": progress.AttemptsCount,
-		})
+	lesson, err := h.courseService.GetLesson(c.Request.Context(), lessonID)
+	if err != nil {
+		abort(c, err)
+		return nil, false
+	}
+	if lesson == nil {
+		abort(c, errLessonNotFound)
+		return nil, false
+	}
+
+	if err := h.progressService.CheckLessonAccess(c.Request.Context(), userID, lesson); err != nil {
+		abort(c, err)
+		return nil, false
+	}
+
+	test, err := h.courseService.GetTestByLessonID(c.Request.Context(), lessonID)
+	if err != nil {
+		abort(c, err)
+		return nil, false
+	}
+	if test == nil {
+		abort(c, errTestNotFound)
+		return nil, false
 	}
+	return test, true
 }
@@ -0,0 +1,417 @@
+package handler
+
+import (
+	"course2/internal/models"
+	"course2/internal/services"
+	"net/http"
+	"platform/auth"
+	"platform/pagination"
+	"platform/problem"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	reviewService *services.ReviewService
+}
+
+func NewReviewHandler(router *gin.Engine, reviewService *services.ReviewService, authMiddleware *auth.Middleware) {
+	handler := &ReviewHandler{
+		reviewService: reviewService,
+	}
+
+	courses := router.Group("/api/v1/edu/courses")
+	{
+		// Публичные эндпоинты
+		courses.GET("/:id/reviews", handler.ListReviews)
+		courses.GET("/:id/rating", handler.GetRating)
+
+		authorized := courses.Group("")
+		authorized.Use(authMiddleware.RequireRoles())
+		{
+			authorized.POST("/:id/reviews", handler.CreateReview)
+		}
+	}
+
+	reviews := router.Group("/api/v1/edu/reviews")
+	reviews.Use(authMiddleware.RequireRoles())
+	{
+		reviews.PUT("/:id", handler.UpdateReview)
+		reviews.DELETE("/:id", handler.DeleteReview)
+		reviews.POST("/:id/helpful", handler.VoteHelpful)
+		reviews.DELETE("/:id/helpful", handler.UnvoteHelpful)
+		reviews.POST("/:id/report", handler.ReportReview)
+	}
+
+	admin := router.Group("/api/v1/edu/admin/reviews")
+	admin.Use(authMiddleware.RequireRoles("admin"))
+	{
+		admin.GET("/reported", handler.ListReported)
+		admin.GET("/:id/reports", handler.ListReports)
+		admin.POST("/:id/hide", handler.HideReview)
+		admin.POST("/:id/unhide", handler.UnhideReview)
+	}
+}
+
+// ReviewsQuery - параметры списка отзывов
+type ReviewsQuery struct {
+	Sort string `form:"sort" binding:"omitempty,oneof=newest helpful"`
+}
+
+// ReviewRequest модель запроса для отзыва о курсе
+type ReviewRequest struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5" example:"5"`
+	Text   string `json:"text" binding:"max=5000" example:"Понятные объяснения и хорошие задачи"`
+}
+
+// ReportReviewRequest модель запроса для жалобы на отзыв
+type ReportReviewRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000" example:"Оскорбления"`
+}
+
+// HideReviewRequest модель запроса для скрытия отзыва
+type HideReviewRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000" example:"Нарушение правил сообщества"`
+}
+
+// @Summary Отзывы о курсе
+// @Description Получить отзывы об опубликованном курсе. Скрытые администратором отзывы не показываются.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "ID курса"
+// @Param sort query string false "Сортировка" Enums(newest, helpful)
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество элементов на странице, не больше 100"
+// @Success 200 {object} pagination.Page[models.Review]
+// @Router /courses/{id}/reviews [get]
+func (h *ReviewHandler) ListReviews(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var query ReviewsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+	sort := models.ReviewSort(query.Sort)
+	if sort == "" {
+		sort = models.ReviewSortNewest
+	}
+
+	page, ok := pageParams(c, pagination.DefaultLimits)
+	if !ok {
+		return
+	}
+
+	reviews, err := h.reviewService.ListReviews(c.Request.Context(), courseID, sort, page)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// @Summary Рейтинг курса
+// @Description Получить средний рейтинг курса и число отзывов с каждой оценкой от 1 до 5
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "ID курса"
+// @Success 200 {object} models.RatingSummary
+// @Router /courses/{id}/rating [get]
+func (h *ReviewHandler) GetRating(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	summary, err := h.reviewService.GetRating(c.Request.Context(), courseID)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// @Summary Оставить отзыв
+// @Description Оставить отзыв о купленном курсе. На один курс можно оставить один отзыв.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID курса"
+// @Param review body ReviewRequest true "Оценка и текст"
+// @Success 201 {object} models.Review
+// @Router /courses/{id}/reviews [post]
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	courseID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var request ReviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	review := &models.Review{
+		CourseID: courseID,
+		UserID:   auth.MustUserID(c),
+		Rating:   request.Rating,
+		Text:     strings.TrimSpace(request.Text),
+	}
+	if err := h.reviewService.CreateReview(c.Request.Context(), review); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// @Summary Изменить отзыв
+// @Description Изменить оценку и текст своего отзыва
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID отзыва"
+// @Param review body ReviewRequest true "Оценка и текст"
+// @Success 200 {object} models.Review
+// @Router /reviews/{id} [put]
+func (h *ReviewHandler) UpdateReview(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var request ReviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	review, err := h.reviewService.UpdateReview(c.Request.Context(), id, auth.MustUserID(c), request.Rating, strings.TrimSpace(request.Text))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// @Summary Удалить отзыв
+// @Description Удалить свой отзыв
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID отзыва"
+// @Success 204 "No Content"
+// @Router /reviews/{id} [delete]
+func (h *ReviewHandler) DeleteReview(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.reviewService.DeleteReview(c.Request.Context(), id, auth.MustUserID(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Отметить отзыв полезным
+// @Description Отметить чужой отзыв полезным. Повторная отметка ничего не меняет.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID отзыва"
+// @Success 204 "No Content"
+// @Router /reviews/{id}/helpful [post]
+func (h *ReviewHandler) VoteHelpful(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.reviewService.VoteHelpful(c.Request.Context(), id, auth.MustUserID(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Снять отметку «полезный»
+// @Description Снять свою отметку «полезный отзыв»
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID отзыва"
+// @Success 204 "No Content"
+// @Router /reviews/{id}/helpful [delete]
+func (h *ReviewHandler) UnvoteHelpful(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.reviewService.UnvoteHelpful(c.Request.Context(), id, auth.MustUserID(c)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Пожаловаться на отзыв
+// @Description Пожаловаться на чужой отзыв. Отзывы с жалобами проверяют администраторы.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID отзыва"
+// @Param request body ReportReviewRequest true "Причина жалобы"
+// @Success 204 "No Content"
+// @Router /reviews/{id}/report [post]
+func (h *ReviewHandler) ReportReview(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var request ReportReviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	if err := h.reviewService.ReportReview(c.Request.Context(), id, auth.MustUserID(c), strings.TrimSpace(request.Reason)); err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Отзывы с жалобами
+// @Description Получить видимые отзывы с жалобами, сначала с наибольшим числом жалоб
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество элементов на странице, не больше 100"
+// @Success 200 {object} pagination.Page[models.Review]
+// @Router /admin/reviews/reported [get]
+func (h *ReviewHandler) ListReported(c *gin.Context) {
+	page, ok := pageParams(c, pagination.DefaultLimits)
+	if !ok {
+		return
+	}
+
+	reviews, err := h.reviewService.ListReported(c.Request.Context(), page)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// @Summary Жалобы на отзыв
+// @Description Получить жалобы на отзыв, сначала новые
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID отзыва"
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Количество элементов на странице, не больше 100"
+// @Success 200 {object} pagination.Page[models.ReviewReport]
+// @Router /admin/reviews/{id}/reports [get]
+func (h *ReviewHandler) ListReports(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	page, ok := pageParams(c, pagination.DefaultLimits)
+	if !ok {
+		return
+	}
+
+	reports, err := h.reviewService.ListReports(c.Request.Context(), id, page)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// @Summary Скрыть отзыв
+// @Description Скрыть отзыв из списка и рейтинга курса. Автор отзыва получает уведомление с причиной.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID отзыва"
+// @Param request body HideReviewRequest true "Причина"
+// @Success 200 {object} models.Review
+// @Router /admin/reviews/{id}/hide [post]
+func (h *ReviewHandler) HideReview(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var request HideReviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abort(c, problem.Validation(err))
+		return
+	}
+
+	review, err := h.reviewService.HideReview(c.Request.Context(), id, auth.MustUserID(c), strings.TrimSpace(request.Reason))
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// @Summary Вернуть отзыв
+// @Description Вернуть скрытый отзыв в список и рейтинг курса. Жалобы на отзыв снимаются.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID отзыва"
+// @Success 200 {object} models.Review
+// @Router /admin/reviews/{id}/unhide [post]
+func (h *ReviewHandler) UnhideReview(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	review, err := h.reviewService.UnhideReview(c.Request.Context(), id)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
@@ -1,36 +1,44 @@
 package handler
 
 import (
+	"course2/internal/grading"
 	"course2/internal/models"
 	"course2/internal/repositories"
 	"course2/internal/services"
-	"course2/internal/transport/http/middleware"
 	"net/http"
+	"platform/auth"
+	"platform/problem"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type StudentHandler struct {
-	courseService  *services.CourseService
-	paymentService *services.PaymentService
-	progressRepo   *repositories.ProgressRepository
-	purchaseRepo   *repositories.PurchaseRepository
+	courseService   *services.CourseService
+	paymentService  *services.PaymentService
+	progressService *services.ProgressService
+	testService     *services.TestService
+	progressRepo    *repositories.ProgressRepository
+	purchaseRepo    *repositories.PurchaseRepository
 }
 
 func NewStudentHandler(
 	router *gin.Engine,
 	courseService *services.CourseService,
 	paymentService *services.PaymentService,
+	progressService *services.ProgressService,
+	testService *services.TestService,
 	progressRepo *repositories.ProgressRepository,
 	purchaseRepo *repositories.PurchaseRepository,
-	authMiddleware *middleware.RolesMiddleware,
+	authMiddleware *auth.Middleware,
 ) {
 	handler := &StudentHandler{
-		courseService:  courseService,
-		paymentService: paymentService,
-		progressRepo:   progressRepo,
-		purchaseRepo:   purchaseRepo,
+		courseService:   courseService,
+		paymentService:  paymentService,
+		progressService: progressService,
+		testService:     testService,
+		progressRepo:    progressRepo,
+		purchaseRepo:    purchaseRepo,
 	}
 
 	student := router.Group("/api/v1/edu/student")
@@ -51,7 +59,9 @@ func NewStudentHandler(
 }
 
 // @Summary Получить уроки курса
-// @Description Получить список уроков для конкретного курса
+// @Description Получить список уроков для конкретного курса. Если у курса задан порядок прохождения,
+// @Description у заблокированных уроков locked=true, причина и уроки, которые нужно пройти, а содержимое не отдается.
+// @Description Купившему курс уроки доступны и после снятия курса с публикации.
 // @Tags courses
 // @Accept json
 // @Produce json
@@ -61,33 +71,34 @@ func NewStudentHandler(
 func (h *StudentHandler) GetCourseLessons(c *gin.Context) {
 	courseID, err := uuid.Parse(c.Param("courseId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат ID курса"})
+		abort(c, problem.InvalidID)
 		return
 	}
 
-	// Проверяем существование курса
-	course, err := h.courseService.GetCourse(c.Request.Context(), courseID)
+	// Проверяем существование курса. Купивший курс видит его уроки и после снятия с публикации.
+	userID, exists := auth.UserID(c)
+	course, err := h.courseService.GetCourseForStudent(c.Request.Context(), userID, courseID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 	if course == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Курс не найден"})
+		abort(c, errCourseNotFound)
 		return
 	}
 
 	lessons, err := h.courseService.GetLessons(c.Request.Context(), courseID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
 	// Если пользователь авторизован, добавляем информацию о прогрессе
-	if userID, exists := c.Get("user_id"); exists {
+	if exists {
 		for _, lesson := range lessons {
-			progress, err := h.progressRepo.GetLessonProgress(c.Request.Context(), userID.(uuid.UUID), lesson.ID)
+			progress, err := h.progressRepo.GetLessonProgress(c.Request.Context(), userID, lesson.ID)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				abort(c, err)
 				return
 			}
 			if progress != nil {
@@ -96,11 +107,17 @@ func (h *StudentHandler) GetCourseLessons(c *gin.Context) {
 		}
 	}
 
+	if err := h.progressService.ApplyLocks(c.Request.Context(), course, lessons, userID); err != nil {
+		abort(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, lessons)
 }
 
 // @Summary Получить урок
-// @Description Получить содержимое конкретного урока
+// @Description Получить содержимое конкретного урока. Авторизованному пользователю урок доступен после покупки курса,
+// @Description если урок не заблокирован порядком прохождения (LESSON_LOCKED). Гостю доступны уроки без требований.
 // @Tags lessons
 // @Accept json
 // @Produce json
@@ -110,31 +127,29 @@ func (h *StudentHandler) GetCourseLessons(c *gin.Context) {
 func (h *StudentHandler) GetLesson(c *gin.Context) {
 	lessonID, err := uuid.Parse(c.Param("lessonId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат ID урока"})
+		abort(c, problem.InvalidID)
 		return
 	}
 
 	lesson, err := h.courseService.GetLesson(c.Request.Context(), lessonID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 	if lesson == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Урок не найден"})
+		abort(c, errLessonNotFound)
 		return
 	}
 
 	// Проверяем доступ к уроку
-	if userID, exists := c.Get("user_id"); exists {
-		purchased, err := h.purchaseRepo.HasPurchased(c.Request.Context(), userID.(uuid.UUID), lesson.CourseID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		if !purchased {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Необходимо купить курс для доступа к уроку"})
-			return
-		}
+	if userID, exists := auth.UserID(c); exists {
+		err = h.progressService.CheckLessonAccess(c.Request.Context(), userID, lesson)
+	} else {
+		err = h.progressService.CheckPreviewAccess(c.Request.Context(), lesson)
+	}
+	if err != nil {
+		abort(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, lesson)
@@ -146,47 +161,40 @@ type PurchaseCourseRequest struct {
 }
 
 // @Summary Купить курс
-// @Description Покупка курса пользователем
+// @Description Оформить заказ курса. Для платного курса создается платежная сессия: студент оплачивает курс
+// @Description на странице checkout_url, а курс выдается, когда провайдер подтвердит оплату уведомлением.
+// @Description Статус заказа - GET /student/orders/{orderId}. Повторный запрос возвращает заказ, ожидающий оплаты.
+// @Description Бесплатный курс выдается сразу, заказ получает статус paid.
+// @Description Заказать можно только опубликованный курс, иначе COURSE_NOT_FOUND.
 // @Tags courses
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body PurchaseCourseRequest true "Данные для покупки курса"
-// @Success 200 {object} map[string]interface{}
+// @Success 201 {object} models.Order
 // @Router /student/courses/purchase [post]
 func (h *StudentHandler) PurchaseCourse(c *gin.Context) {
 	var request PurchaseCourseRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		abort(c, problem.Validation(err))
 		return
 	}
 
-	userID := c.MustGet("user_id").(uuid.UUID)
+	userID := auth.MustUserID(c)
 
-	// Проверяем, не куплен ли уже курс
-	purchased, err := h.purchaseRepo.HasPurchased(c.Request.Context(), userID, request.CourseID)
+	// Оформляем заказ через платежный сервис
+	order, err := h.paymentService.Checkout(c.Request.Context(), userID, request.CourseID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	if purchased {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Курс уже куплен"})
+		abort(c, err)
 		return
 	}
 
-	// Обрабатываем покупку через платежный сервис
-	if err := h.paymentService.PurchaseCourse(c.Request.Context(), userID, request.CourseID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Курс успешно куплен",
-	})
+	c.JSON(http.StatusCreated, order)
 }
 
 // @Summary Получить структуру курса
-// @Description Получить полную структуру курса с уроками и прогрессом для купленного курса
+// @Description Получить полную структуру курса с уроками и прогрессом для купленного курса.
+// @Description Для каждого урока указано, заблокирован ли он (locked), почему (lock_reason) и какие уроки нужно пройти (blocked_by).
 // @Tags courses
 // @Accept json
 // @Produce json
@@ -197,38 +205,38 @@ func (h *StudentHandler) PurchaseCourse(c *gin.Context) {
 func (h *StudentHandler) GetCourseStructure(c *gin.Context) {
 	courseID, err := uuid.Parse(c.Param("courseId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат ID курса"})
+		abort(c, problem.InvalidID)
 		return
 	}
 
-	userID := c.MustGet("user_id").(uuid.UUID)
+	userID := auth.MustUserID(c)
 
 	// Проверяем, куплен ли курс
 	purchased, err := h.purchaseRepo.HasPurchased(c.Request.Context(), userID, courseID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 	if !purchased {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Необходимо купить курс для доступа к структуре"})
+		abort(c, errCourseNotPurchased)
 		return
 	}
 
-	// Получаем курс
-	course, err := h.courseService.GetCourse(c.Request.Context(), courseID)
+	// Получаем курс: купленный курс доступен и после снятия с публикации
+	course, err := h.courseService.GetCourseForStudent(c.Request.Context(), userID, courseID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 	if course == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Курс не найден"})
+		abort(c, errCourseNotFound)
 		return
 	}
 
 	// Получаем уроки
 	lessons, err := h.courseService.GetLessons(c.Request.Context(), courseID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 
@@ -236,7 +244,7 @@ func (h *StudentHandler) GetCourseStructure(c *gin.Context) {
 	for _, lesson := range lessons {
 		progress, err := h.progressRepo.GetLessonProgress(c.Request.Context(), userID, lesson.ID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			abort(c, err)
 			return
 		}
 		if progress != nil {
@@ -250,7 +258,7 @@ func (h *StudentHandler) GetCourseStructure(c *gin.Context) {
 		if lesson.RequiresTest {
 			test, err := h.courseService.GetTestByLessonID(c.Request.Context(), lesson.ID)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				abort(c, err)
 				return
 			}
 			if test != nil {
@@ -259,6 +267,11 @@ func (h *StudentHandler) GetCourseStructure(c *gin.Context) {
 		}
 	}
 
+	if err := h.progressService.ApplyLocks(c.Request.Context(), course, lessons, userID); err != nil {
+		abort(c, err)
+		return
+	}
+
 	// Получаем общий прогресс по курсу
 	totalLessons := len(lessons)
 	completedLessons := 0
@@ -280,7 +293,13 @@ func (h *StudentHandler) GetCourseStructure(c *gin.Context) {
 }
 
 // @Summary Получить тест урока
-// @Description Получить тест, привязанный к уроку (если есть)
+// @Description Получить тест, привязанный к уроку (если есть). Тест заблокированного урока недоступен (LESSON_LOCKED).
+// @Description Тест открывается после просмотра урока, иначе LESSON_NOT_VIEWED.
+// @Description Открытие теста начинает попытку: вопросы выбираются из пула и перемешиваются по настройкам теста.
+// @Description Пока попытка не отправлена, повторные запросы возвращают те же вопросы в том же порядке.
+// @Description Если попытки закончились или действует пауза между попытками, вопросов и attempt_id нет,
+// @Description а attempts показывает оставшиеся попытки и next_attempt_at. Незавершенная попытка с истекшим
+// @Description временем засчитывается с результатом 0.
 // @Tags lessons
 // @Accept json
 // @Produce json
@@ -291,84 +310,84 @@ func (h *StudentHandler) GetCourseStructure(c *gin.Context) {
 func (h *StudentHandler) GetLessonTest(c *gin.Context) {
 	lessonID, err := uuid.Parse(c.Param("lessonId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Неверный формат ID урока"})
+		abort(c, problem.InvalidID)
 		return
 	}
 
-	userID := c.MustGet("user_id").(uuid.UUID)
+	userID := auth.MustUserID(c)
 
 	// Получаем урок для проверки принадлежности к курсу
 	lesson, err := h.courseService.GetLesson(c.Request.Context(), lessonID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 	if lesson == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Урок не найден"})
+		abort(c, errLessonNotFound)
 		return
 	}
 
-	// Проверяем, куплен ли курс
-	purchased, err := h.purchaseRepo.HasPurchased(c.Request.Context(), userID, lesson.CourseID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	if !purchased {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Необходимо купить курс для доступа к тесту"})
+	// Проверяем, куплен ли курс и открыт ли урок
+	if err := h.progressService.CheckLessonAccess(c.Request.Context(), userID, lesson); err != nil {
+		abort(c, err)
 		return
 	}
 
 	// Получаем тест
 	test, err := h.courseService.GetTestByLessonID(c.Request.Context(), lessonID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
 	if test == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Тест не найден"})
+		abort(c, errTestNotFound)
 		return
 	}
 
-	// Получаем вопросы теста
-	questions, err := h.courseService.GetTestQuestions(c.Request.Context(), test.ID)
+	// Получаем прогресс по уроку: как и отправка теста, попытка начинается только после просмотра урока,
+	// иначе студент тратил бы время и попытку, которую нельзя отправить
+	progress, err := h.progressRepo.GetLessonProgress(c.Request.Context(), userID, lessonID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		abort(c, err)
 		return
 	}
-
-	// Получаем прогресс по уроку
-	progress, err := h.progressRepo.GetLessonProgress(c.Request.Context(), userID, lessonID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if progress == nil || progress.ViewedAt == nil {
+		abort(c, errLessonNotViewed)
 		return
 	}
 
-	// Подготавливаем вопросы для отправки (удаляем правильные ответы)
-	testQuestions := make([]*models.Question, len(questions))
-	for i, q := range questions {
-		testQuestions[i] = &models.Question{
-			ID:           q.ID,
-			QuestionText: q.QuestionText,
-			Options:      q.Options,
-		}
+	// Начинаем попытку или продолжаем незавершенную
+	attempt, status, err := h.testService.StartAttempt(c.Request.Context(), userID, test)
+	if err != nil {
+		abort(c, err)
+		return
 	}
 
 	response := &models.TestResponse{
 		Test:          test,
-		Questions:     testQuestions,
+		Questions:     []*models.Question{},
+		Attempts:      status,
+		AnswerSchema:  grading.SchemaVersion,
 		PassingScore:  test.PassingScore,
 		LastScore:     nil,
 		Passed:        false,
 		AttemptsCount: 0,
 	}
 
-	// Если есть прогресс, обновляем значения
-	if progress != nil {
-		response.LastScore = progress.TestScore
-		response.Passed = progress.PassedTest
-		response.AttemptsCount = progress.AttemptsCount
+	// Подготавливаем вопросы попытки для отправки (удаляем правильные ответы и пояснения)
+	if attempt != nil {
+		response.AttemptID = &attempt.ID
+		response.StartedAt = &attempt.StartedAt
+		response.ExpiresAt = attempt.ExpiresAt
+		response.Questions = make([]*models.Question, len(attempt.Items))
+		for i, item := range attempt.Items {
+			response.Questions[i] = item.Served()
+		}
 	}
 
+	response.LastScore = progress.TestScore
+	response.Passed = progress.PassedTest
+	response.AttemptsCount = progress.AttemptsCount
+
 	c.JSON(http.StatusOK, response)
 }
@@ -54,28 +54,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
@@ -88,7 +79,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Возвращает таблицу лидеров игры-кликера",
+                "description": "Возвращает страницу таблицы лидеров игры-кликера и место текущего пользователя",
                 "produces": [
                     "application/json"
                 ],
@@ -98,15 +89,15 @@ const docTemplate = `{
                 "summary": "Получить таблицу лидеров",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Лимит записей (по умолчанию 10)",
-                        "name": "limit",
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor)",
+                        "name": "cursor",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Смещение (по умолчанию 0)",
-                        "name": "offset",
+                        "description": "Лимит записей (по умолчанию 10, не больше 100)",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
@@ -117,22 +108,22 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.LeaderboardResponse"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
@@ -163,19 +154,13 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
@@ -302,12 +287,20 @@ const docTemplate = `{
         "models.LeaderboardResponse": {
             "type": "object",
             "properties": {
-                "entries": {
+                "items": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.LeaderboardEntry"
                     }
                 },
+                "next_cursor": {
+                    "description": "NextCursor - курсор следующей страницы, отсутствует на последней странице",
+                    "type": "string"
+                },
+                "total_estimate": {
+                    "description": "TotalEstimate - число элементов во всем списке, см. EstimateTotal",
+                    "type": "integer"
+                },
                 "user_rank": {
                     "$ref": "#/definitions/models.LeaderboardEntry"
                 }
@@ -326,6 +319,47 @@ const docTemplate = `{
                     "$ref": "#/definitions/models.ClickerStats"
                 }
             }
+        },
+        "problem.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "rule": {
+                    "description": "Rule - нарушенное правило проверки, например required или email",
+                    "type": "string"
+                }
+            }
+        },
+        "problem.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/problem.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -2,7 +2,10 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
 	"time"
 
@@ -12,10 +15,17 @@ import (
 	"game/internal/repositories"
 	"game/internal/services"
 	"game/internal/transport/http/handler"
-	"game/internal/transport/http/middleware"
+	"platform/auth"
+	"platform/health"
+	"platform/lifecycle"
+	"platform/logging"
+	"platform/metrics"
+	"platform/problem"
+	"platform/tracing"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"google.golang.org/grpc"
@@ -24,8 +34,12 @@ import (
 
 type App struct {
 	cfg        *config.Config
-	httpServer *gin.Engine
+	logger     *slog.Logger
+	router     *gin.Engine
+	httpServer *http.Server
+	db         *sqlx.DB
 	grpcConn   *grpc.ClientConn
+	checker    *health.Checker
 }
 
 // @title Игровая платформа API
@@ -40,8 +54,8 @@ type App struct {
 // @schemes http https
 // @tags.name game "Игры"
 // @tags.description game "Операции с мини-играми (кликер и др.)"
-func New() (*App, error) {
-	a := &App{}
+func New(logger *slog.Logger) (*App, error) {
+	a := &App{logger: logger}
 
 	// Загрузка конфигурации
 	cfg, err := config.New()
@@ -54,6 +68,11 @@ func New() (*App, error) {
 	a.grpcConn, err = grpc.Dial(
 		a.cfg.Auth.GRPCAddress,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(
+			tracing.UnaryClientInterceptor(),
+			logging.UnaryClientInterceptor(),
+			metrics.UnaryClientInterceptor(),
+		),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка подключения к серверу авторизации: %w", err)
@@ -62,58 +81,100 @@ func New() (*App, error) {
 	// Настройка CORS
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowOrigins = []string{"*"}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", logging.HeaderRequestID}
+	corsConfig.ExposeHeaders = []string{logging.HeaderRequestID}
 	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
 	corsConfig.MaxAge = 12 * time.Hour
 
 	// Настройка Gin
 	gin.SetMode(os.Getenv("GIN_MODE"))
-	a.httpServer = gin.Default()
-	a.httpServer.Use(
+	a.router = gin.New()
+	a.router.Use(
 		cors.New(corsConfig),
-		gin.Recovery(),
-		gin.Logger(),
+		tracing.Middleware(),
+		logging.Middleware(logger),
+		logging.Recovery(logger),
 	)
+	metrics.Setup(a.router)
+	problem.Setup(a.router)
+
+	// Хелсчек для активных проверок API-шлюза
+	a.router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status": "ok",
+		})
+	})
 
 	// Подключение к базе данных
 	db, err := database.NewPostgresDB(a.cfg.Database.URL)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка подключения к базе данных: %w", err)
 	}
+	a.db = db
+	metrics.RegisterDB(db.DB, "game")
+
+	// Проверки живости и готовности: /livez и /readyz
+	a.checker = health.NewChecker(health.DefaultTimeout)
+	a.checker.Add("postgres", health.Postgres(db.DB))
+	a.checker.Add("auth_grpc", health.GRPCConn(a.grpcConn))
+	a.checker.Register(a.router)
 
 	// Инициализация репозиториев
 	clickerRepo := repositories.NewClickerRepository(db)
 
 	// Инициализация сервисов
-	clickerService := services.NewClickerService(clickerRepo)
+	clickerService := services.NewClickerService(clickerRepo, a.cfg.AntiCheat)
 
 	// Инициализация HTTP обработчиков
-	authRolesMiddleware := middleware.NewRolesMiddleware(a.grpcConn)
-	handler.NewClickerHandler(a.httpServer, clickerService, authRolesMiddleware)
+	authRolesMiddleware := auth.NewMiddleware(a.grpcConn, a.cfg.Auth.Check)
+	handler.NewClickerHandler(a.router, clickerService, authRolesMiddleware)
 
 	// Настройка Swagger
-	a.httpServer.GET("/api/v1/game/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	a.router.GET("/api/v1/game/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	a.httpServer = lifecycle.NewHTTPServer(a.cfg.Server.Port, a.router)
 
 	return a, nil
 }
 
-// Run запускает приложение
-func (a *App) Run() error {
-	fmt.Printf("Запуск HTTP сервера на порту %s\n", a.cfg.Server.Port)
-	if err := a.httpServer.Run(":" + a.cfg.Server.Port); err != nil {
-		return fmt.Errorf("ошибка HTTP сервера: %w", err)
-	}
-	return nil
+// Run запускает приложение и останавливает его после отмены контекста
+func (a *App) Run(ctx context.Context) error {
+	return lifecycle.Run(ctx, a.cfg.Shutdown.Timeout, a.Stop, func() error {
+		a.logger.Info("запуск HTTP сервера", slog.String("port", a.cfg.Server.Port))
+		if err := lifecycle.ServeHTTP(a.httpServer); err != nil {
+			return fmt.Errorf("ошибка HTTP сервера: %w", err)
+		}
+		return nil
+	})
 }
 
-// Stop останавливает приложение
+// Stop останавливает приложение: /readyz начинает отвечать 503, через DrainDelay
+// сервер перестает принимать соединения и ждет завершения начатых запросов,
+// затем закрываются gRPC соединение и пул соединений с базой данных
 func (a *App) Stop(ctx context.Context) error {
+	a.logger.Info("остановка приложения", slog.Duration("drain_delay", a.cfg.Shutdown.DrainDelay))
+
+	a.checker.Drain()
+	a.httpServer.SetKeepAlivesEnabled(false)
+	lifecycle.Wait(ctx, a.cfg.Shutdown.DrainDelay)
+
+	var errs []error
+	if err := a.httpServer.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("остановка HTTP сервера: %w", err))
+	}
+
 	// Закрываем gRPC соединение
-	if a.grpcConn != nil {
-		if err := a.grpcConn.Close(); err != nil {
-			return err
-		}
+	if err := a.grpcConn.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("закрытие gRPC соединения: %w", err))
 	}
 
+	if err := a.db.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("закрытие базы данных: %w", err))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	a.logger.Info("приложение остановлено")
 	return nil
 }
@@ -2,6 +2,7 @@ package database
 
 import (
 	"fmt"
+	"platform/tracing"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
@@ -9,10 +10,12 @@ import (
 
 // NewPostgresDB создает новое подключение к базе данных PostgreSQL
 func NewPostgresDB(dsn string) (*sqlx.DB, error) {
-	db, err := sqlx.Connect("postgres", dsn)
+	// Драйвер с трассировкой запросов; sqlx работает поверх него
+	sqlDB, err := tracing.OpenDB("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка подключения к базе данных: %w", err)
 	}
+	db := sqlx.NewDb(sqlDB, "postgres")
 
 	// Проверка подключения
 	if err := db.Ping(); err != nil {
@@ -0,0 +1,19 @@
+// Package metrics содержит бизнес-метрики игрового сервиса.
+// Метрики HTTP, gRPC и базы данных собирает platform/metrics.
+package metrics
+
+import (
+	platform "platform/metrics"
+)
+
+const subsystem = "game"
+
+var (
+	// ClicksAccepted - количество принятых кликов
+	ClicksAccepted = platform.NewCounter(subsystem, "clicks_accepted_total",
+		"Количество принятых кликов.")
+
+	// ClicksRejected - количество отклоненных античитом кликов по причине
+	ClicksRejected = platform.NewCounterVec(subsystem, "clicks_rejected_total",
+		"Количество кликов, отклоненных античитом, по причине.", "reason")
+)
@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 
+	"platform/pagination"
+
 	"github.com/google/uuid"
 )
 
@@ -54,10 +56,10 @@ type ClickResponse struct {
 	Status      string `json:"status"`
 }
 
-// LeaderboardResponse представляет ответ с таблицей лидеров
+// LeaderboardResponse представляет ответ со страницей таблицы лидеров
 type LeaderboardResponse struct {
-	Entries  []LeaderboardEntry `json:"entries"`
-	UserRank *LeaderboardEntry  `json:"user_rank,omitempty"`
+	pagination.Page[LeaderboardEntry]
+	UserRank *LeaderboardEntry `json:"user_rank,omitempty"`
 }
 
 // StatsResponse представляет ответ со статистикой пользователя
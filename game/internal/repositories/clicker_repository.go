@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"game/internal/models"
+	"platform/pagination"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -149,29 +150,56 @@ func (r *ClickerRepository) UpdateLeaderboard(ctx context.Context, entry *models
 	return err
 }
 
-// GetLeaderboard получает таблицу лидеров
-func (r *ClickerRepository) GetLeaderboard(ctx context.Context, limit int, offset int) ([]models.LeaderboardEntry, error) {
+// leaderboardKey - ключ последней записи страницы таблицы лидеров
+type leaderboardKey struct {
+	Score  int64     `json:"s"`
+	UserID uuid.UUID `json:"u"`
+}
+
+// GetLeaderboard получает страницу таблицы лидеров, упорядоченной по убыванию счета.
+// Место считается так же, как в GetUserRank: игроки с одинаковым счетом делят место.
+func (r *ClickerRepository) GetLeaderboard(ctx context.Context, page pagination.Params) (*pagination.Page[models.LeaderboardEntry], error) {
+	const kind = "leaderboard"
+	var after leaderboardKey
+	ok, err := page.Decode(kind, &after)
+	if err != nil {
+		return nil, err
+	}
+
+	var afterScore *int64
+	var afterUserID *uuid.UUID
+	if ok {
+		afterScore, afterUserID = &after.Score, &after.UserID
+	}
+
 	entries := []models.LeaderboardEntry{}
 
 	query := `
-		SELECT id, user_id, username, score, rank, updated_at
-		FROM clicker_leaderboard
-		ORDER BY score DESC
-		LIMIT $1 OFFSET $2
+		SELECT l.id, l.user_id, l.username, l.score, l.updated_at,
+		       (SELECT COUNT(*) FROM clicker_leaderboard WHERE score > l.score) + 1 AS rank
+		FROM clicker_leaderboard l
+		WHERE $1::bigint IS NULL OR (l.score, l.user_id) < ($1::bigint, $2::uuid)
+		ORDER BY l.score DESC, l.user_id DESC
+		LIMIT $3
 	`
 
-	err := r.db.SelectContext(ctx, &entries, query, limit, offset)
+	err = r.db.SelectContext(ctx, &entries, query, afterScore, afterUserID, page.Limit+1)
 	if err != nil {
 		return nil, err
 	}
 
-	// Обновляем ранги
-	for i := range entries {
-		rank := offset + i + 1
-		entries[i].Rank = &rank
+	result, err := pagination.NewPage(entries, page, kind, func(entry models.LeaderboardEntry) any {
+		return leaderboardKey{Score: entry.Score, UserID: entry.UserID}
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return entries, nil
+	result.TotalEstimate, err = pagination.EstimateTotal(ctx, r.db, "FROM clicker_leaderboard")
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // GetUserRank получает ранг пользователя в таблице лидеров
@@ -6,20 +6,15 @@ import (
 	"math"
 	"time"
 
+	"game/internal/config"
+	"game/internal/metrics"
 	"game/internal/models"
 	"game/internal/repositories"
+	"platform/pagination"
 
 	"github.com/google/uuid"
 )
 
-// Константы для проверки на читерство
-const (
-	MaxClicksPerSecond     = 20.0  // Максимальное количество кликов в секунду
-	SuspiciousClicksPerSec = 15.0  // Подозрительное количество кликов в секунду
-	MaxSessionTime         = 300.0 // Максимальное время сессии в секундах (5 минут)
-	MaxTimeDrift           = 10.0  // Максимальное расхождение во времени клиента и сервера в секундах
-)
-
 var (
 	ErrInvalidClickRate = errors.New("недопустимая скорость кликов")
 	ErrInvalidSession   = errors.New("недопустимая сессия")
@@ -28,13 +23,15 @@ var (
 
 // ClickerService представляет сервис для работы с кликером
 type ClickerService struct {
-	repo *repositories.ClickerRepository
+	repo      *repositories.ClickerRepository
+	antiCheat config.AntiCheatConfig
 }
 
 // NewClickerService создает новый экземпляр ClickerService
-func NewClickerService(repo *repositories.ClickerRepository) *ClickerService {
+func NewClickerService(repo *repositories.ClickerRepository, antiCheat config.AntiCheatConfig) *ClickerService {
 	return &ClickerService{
-		repo: repo,
+		repo:      repo,
+		antiCheat: antiCheat,
 	}
 }
 
@@ -42,6 +39,7 @@ func NewClickerService(repo *repositories.ClickerRepository) *ClickerService {
 func (s *ClickerService) SaveClicks(ctx context.Context, userID uuid.UUID, req *models.ClickRequest) (*models.ClickResponse, error) {
 	// Проверка на читерство
 	if err := s.validateClickRequest(req); err != nil {
+		metrics.ClicksRejected.WithLabelValues(rejectReason(err)).Add(float64(req.ClickCount))
 		return &models.ClickResponse{
 			Status: "rejected",
 		}, err
@@ -102,12 +100,28 @@ func (s *ClickerService) SaveClicks(ctx context.Context, userID uuid.UUID, req *
 		return nil, err
 	}
 
+	metrics.ClicksAccepted.Add(float64(req.ClickCount))
+
 	return &models.ClickResponse{
 		TotalClicks: stats.TotalClicks,
 		Status:      "accepted",
 	}, nil
 }
 
+// rejectReason возвращает причину отклонения кликов для метрик
+func rejectReason(err error) string {
+	switch err {
+	case ErrInvalidClickRate:
+		return "click_rate"
+	case ErrInvalidSession:
+		return "session_time"
+	case ErrInvalidTime:
+		return "time_drift"
+	default:
+		return "other"
+	}
+}
+
 // GetStats получает статистику пользователя
 func (s *ClickerService) GetStats(ctx context.Context, userID uuid.UUID) (*models.StatsResponse, error) {
 	// Получаем статистику пользователя
@@ -129,9 +143,9 @@ func (s *ClickerService) GetStats(ctx context.Context, userID uuid.UUID) (*model
 }
 
 // GetLeaderboard получает таблицу лидеров
-func (s *ClickerService) GetLeaderboard(ctx context.Context, userID uuid.UUID, limit, offset int) (*models.LeaderboardResponse, error) {
-	// Получаем таблицу лидеров
-	entries, err := s.repo.GetLeaderboard(ctx, limit, offset)
+func (s *ClickerService) GetLeaderboard(ctx context.Context, userID uuid.UUID, page pagination.Params) (*models.LeaderboardResponse, error) {
+	// Получаем страницу таблицы лидеров
+	entries, err := s.repo.GetLeaderboard(ctx, page)
 	if err != nil {
 		return nil, err
 	}
@@ -143,7 +157,7 @@ func (s *ClickerService) GetLeaderboard(ctx context.Context, userID uuid.UUID, l
 	}
 
 	return &models.LeaderboardResponse{
-		Entries:  entries,
+		Page:     *entries,
 		UserRank: userRank,
 	}, nil
 }
@@ -157,12 +171,12 @@ func (s *ClickerService) RefreshLeaderboard(ctx context.Context) error {
 func (s *ClickerService) validateClickRequest(req *models.ClickRequest) error {
 	// Проверка скорости кликов
 	clicksPerSecond := float64(req.ClickCount) / req.SessionTime
-	if clicksPerSecond > MaxClicksPerSecond {
+	if clicksPerSecond > s.antiCheat.MaxClicksPerSecond {
 		return ErrInvalidClickRate
 	}
 
 	// Проверка времени сессии
-	if req.SessionTime <= 0 || req.SessionTime > MaxSessionTime {
+	if req.SessionTime <= 0 || req.SessionTime > s.antiCheat.MaxSessionTime.Seconds() {
 		return ErrInvalidSession
 	}
 
@@ -171,7 +185,7 @@ func (s *ClickerService) validateClickRequest(req *models.ClickRequest) error {
 	serverTime := time.Now()
 	timeDiff := math.Abs(serverTime.Sub(clientTime).Seconds())
 
-	if timeDiff > MaxTimeDrift {
+	if timeDiff > s.antiCheat.MaxTimeDrift.Seconds() {
 		return ErrInvalidTime
 	}
 
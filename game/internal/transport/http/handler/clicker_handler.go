@@ -1,25 +1,28 @@
 package handler
 
 import (
-	"log"
+	"fmt"
 	"net/http"
-	"strconv"
 
 	"game/internal/models"
 	"game/internal/services"
-	"game/internal/transport/http/middleware"
+	"platform/auth"
+	"platform/pagination"
+	"platform/problem"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
+// leaderboardLimits - размер страницы таблицы лидеров
+var leaderboardLimits = pagination.Limits{Default: 10, Max: 100}
+
 // ClickerHandler представляет обработчик для API кликера
 type ClickerHandler struct {
 	service *services.ClickerService
 }
 
 // NewClickerHandler создает новый экземпляр ClickerHandler и регистрирует маршруты
-func NewClickerHandler(router *gin.Engine, service *services.ClickerService, authMiddleware *middleware.RolesMiddleware) {
+func NewClickerHandler(router *gin.Engine, service *services.ClickerService, authMiddleware *auth.Middleware) {
 	handler := &ClickerHandler{
 		service: service,
 	}
@@ -49,39 +52,29 @@ func NewClickerHandler(router *gin.Engine, service *services.ClickerService, aut
 // @Produce json
 // @Param request body models.ClickRequest true "Данные о кликах"
 // @Success 200 {object} models.ClickResponse
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
 // @Router /clicker/clicks [post]
 // @Security BearerAuth
 func (h *ClickerHandler) SaveClicks(c *gin.Context) {
 	var req models.ClickRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "неверный формат запроса"})
+		abort(c, problem.Validation(err))
 		return
 	}
 
 	// Получаем ID пользователя из контекста
-	userID, exists := c.Get("user_id")
+	userID, exists := auth.UserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": "пользователь не аутентифицирован"})
+		abort(c, problem.Unauthorized)
 		return
 	}
 
 	// Сохраняем клики
-	response, err := h.service.SaveClicks(c.Request.Context(), userID.(uuid.UUID), &req)
+	response, err := h.service.SaveClicks(c.Request.Context(), userID, &req)
 	if err != nil {
-		switch err {
-		case services.ErrInvalidClickRate:
-			c.JSON(http.StatusBadRequest, gin.H{"message": "обнаружена подозрительная активность: слишком высокая скорость кликов"})
-		case services.ErrInvalidSession:
-			c.JSON(http.StatusBadRequest, gin.H{"message": "обнаружена подозрительная активность: недопустимое время сессии"})
-		case services.ErrInvalidTime:
-			c.JSON(http.StatusBadRequest, gin.H{"message": "обнаружена подозрительная активность: несоответствие времени"})
-		default:
-			log.Println(err)
-			c.JSON(http.StatusInternalServerError, gin.H{"message": "ошибка при сохранении кликов"})
-		}
+		abort(c, fmt.Errorf("сохранение кликов: %w", err))
 		return
 	}
 
@@ -94,23 +87,22 @@ func (h *ClickerHandler) SaveClicks(c *gin.Context) {
 // @Tags game
 // @Produce json
 // @Success 200 {object} models.StatsResponse
-// @Failure 401 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
 // @Router /clicker/stats [get]
 // @Security BearerAuth
 func (h *ClickerHandler) GetStats(c *gin.Context) {
 	// Получаем ID пользователя из контекста
-	userID, exists := c.Get("user_id")
+	userID, exists := auth.UserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": "пользователь не аутентифицирован"})
+		abort(c, problem.Unauthorized)
 		return
 	}
 
 	// Получаем статистику
-	stats, err := h.service.GetStats(c.Request.Context(), userID.(uuid.UUID))
+	stats, err := h.service.GetStats(c.Request.Context(), userID)
 	if err != nil {
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "ошибка при получении статистики"})
+		abort(c, fmt.Errorf("получение статистики: %w", err))
 		return
 	}
 
@@ -119,42 +111,36 @@ func (h *ClickerHandler) GetStats(c *gin.Context) {
 
 // GetLeaderboard godoc
 // @Summary Получить таблицу лидеров
-// @Description Возвращает таблицу лидеров игры-кликера
+// @Description Возвращает страницу таблицы лидеров игры-кликера и место текущего пользователя
 // @Tags game
 // @Produce json
-// @Param limit query int false "Лимит записей (по умолчанию 10)"
-// @Param offset query int false "Смещение (по умолчанию 0)"
+// @Param cursor query string false "Курсор следующей страницы (next_cursor)"
+// @Param limit query int false "Лимит записей (по умолчанию 10, не больше 100)"
 // @Success 200 {object} models.LeaderboardResponse
-// @Failure 401 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
 // @Router /clicker/leaderboard [get]
 // @Security BearerAuth
 func (h *ClickerHandler) GetLeaderboard(c *gin.Context) {
 	// Получаем ID пользователя из контекста
-	userID, exists := c.Get("user_id")
+	userID, exists := auth.UserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": "пользователь не аутентифицирован"})
+		abort(c, problem.Unauthorized)
 		return
 	}
 
-	// Получаем параметры запроса
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 {
-		limit = 10
-	}
-
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	// Получаем параметры страницы
+	page, err := pagination.FromRequest(c, leaderboardLimits)
+	if err != nil {
+		abort(c, err)
+		return
 	}
 
 	// Получаем таблицу лидеров
-	leaderboard, err := h.service.GetLeaderboard(c.Request.Context(), userID.(uuid.UUID), limit, offset)
+	leaderboard, err := h.service.GetLeaderboard(c.Request.Context(), userID, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "ошибка при получении таблицы лидеров"})
+		abort(c, fmt.Errorf("получение таблицы лидеров: %w", err))
 		return
 	}
 
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"game/internal/services"
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Коды ошибок игрового сервиса
+var (
+	errClickRateExceeded = problem.Define("CLICK_RATE_EXCEEDED", http.StatusBadRequest, problem.Messages{
+		"ru": "Обнаружена подозрительная активность: слишком высокая скорость кликов",
+		"en": "Suspicious activity detected: click rate is too high",
+	})
+	errInvalidSession = problem.Define("INVALID_SESSION", http.StatusBadRequest, problem.Messages{
+		"ru": "Обнаружена подозрительная активность: недопустимое время сессии",
+		"en": "Suspicious activity detected: invalid session duration",
+	})
+	errTimeMismatch = problem.Define("TIME_MISMATCH", http.StatusBadRequest, problem.Messages{
+		"ru": "Обнаружена подозрительная активность: несоответствие времени",
+		"en": "Suspicious activity detected: time mismatch",
+	})
+)
+
+// errorMapping сопоставляет ошибки сервиса кодам ответов.
+// Ошибки, которых нет в таблице, отдаются как INTERNAL_ERROR и пишутся в журнал.
+var errorMapping = problem.Mapping{
+	problem.Map(errClickRateExceeded, services.ErrInvalidClickRate),
+	problem.Map(errInvalidSession, services.ErrInvalidSession),
+	problem.Map(errTimeMismatch, services.ErrInvalidTime),
+}
+
+// abort отвечает ошибкой в формате application/problem+json и прерывает обработку запроса
+func abort(c *gin.Context, err error) {
+	errorMapping.Abort(c, err)
+}
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Сохраненные ответы API-шлюза для запросов с заголовком Idempotency-Key
+CREATE TABLE idempotency_keys (
+    user_id UUID NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_method VARCHAR(10) NOT NULL,
+    request_path TEXT NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('in_progress', 'completed')),
+    response_status INTEGER,
+    response_headers JSONB,
+    response_body BYTEA,
+    locked_until TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (user_id, idempotency_key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS idempotency_keys;
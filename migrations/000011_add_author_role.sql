@@ -0,0 +1,15 @@
+-- +goose Up
+-- Роль автора курсов
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users
+ADD CONSTRAINT users_role_check CHECK (role IN ('student', 'author', 'admin'));
+
+-- Курсы автора в кабинете автора
+CREATE INDEX idx_courses_created_by ON courses (created_by, created_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_courses_created_by;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users
+ADD CONSTRAINT users_role_check CHECK (role IN ('student', 'admin'));
@@ -0,0 +1,51 @@
+-- +goose Up
+-- Статусы курса: draft -> pending_review -> published / rejected -> archived
+UPDATE courses SET status = 'pending_review' WHERE status = 'pending';
+ALTER TABLE courses
+ADD CONSTRAINT courses_status_check
+CHECK (status IN ('draft', 'pending_review', 'published', 'rejected', 'archived'));
+
+-- Проверяющий, назначенный на курс в очереди модерации
+ALTER TABLE courses ADD COLUMN reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX idx_courses_review_queue ON courses (status, reviewer_id, updated_at);
+
+-- История модерации курса
+CREATE TABLE course_moderation_events (
+    id UUID PRIMARY KEY,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(50) NOT NULL,
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_course_moderation_events_course ON course_moderation_events (course_id, created_at);
+
+-- Уведомления пользователей
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    course_id UUID REFERENCES courses(id) ON DELETE CASCADE,
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_notifications_user ON notifications (user_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS course_moderation_events;
+
+DROP INDEX IF EXISTS idx_courses_review_queue;
+ALTER TABLE courses DROP COLUMN IF EXISTS reviewer_id;
+
+ALTER TABLE courses DROP CONSTRAINT IF EXISTS courses_status_check;
+UPDATE courses SET status = 'pending' WHERE status = 'pending_review';
+UPDATE courses SET status = 'rejected' WHERE status = 'archived';
@@ -0,0 +1,27 @@
+-- +goose Up
+-- Полнотекстовый поиск по каталогу: название весит больше описания,
+-- текст индексируется с русской и английской морфологией
+ALTER TABLE courses ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian'::regconfig, COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('english'::regconfig, COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('russian'::regconfig, COALESCE(description, '')), 'B') ||
+    setweight(to_tsvector('english'::regconfig, COALESCE(description, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_courses_search_vector ON courses USING GIN (search_vector);
+
+-- Сортировки и фильтры публичного каталога
+CREATE INDEX idx_courses_published_popular ON courses (students_count DESC, created_at DESC) WHERE status = 'published';
+CREATE INDEX idx_courses_published_rating ON courses (rating DESC NULLS LAST) WHERE status = 'published';
+CREATE INDEX idx_courses_published_price ON courses (price) WHERE status = 'published';
+CREATE INDEX idx_courses_published_newest ON courses (created_at DESC) WHERE status = 'published';
+CREATE INDEX idx_courses_published_category ON courses (category_id) WHERE status = 'published';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_courses_published_category;
+DROP INDEX IF EXISTS idx_courses_published_newest;
+DROP INDEX IF EXISTS idx_courses_published_price;
+DROP INDEX IF EXISTS idx_courses_published_rating;
+DROP INDEX IF EXISTS idx_courses_published_popular;
+DROP INDEX IF EXISTS idx_courses_search_vector;
+ALTER TABLE courses DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,46 @@
+-- +goose Up
+-- Постраничная выдача по курсору: индексы повторяют ключ сортировки списков вместе с id,
+-- чтобы условие (key, id) < ($1, $2) читало индекс с нужного места
+DROP INDEX IF EXISTS idx_courses_published_popular;
+DROP INDEX IF EXISTS idx_courses_published_rating;
+DROP INDEX IF EXISTS idx_courses_published_price;
+DROP INDEX IF EXISTS idx_courses_published_newest;
+
+CREATE INDEX idx_courses_published_popular ON courses ((COALESCE(students_count, 0)) DESC, id DESC) WHERE status = 'published';
+CREATE INDEX idx_courses_published_rating ON courses ((COALESCE(rating, 0)) DESC, id DESC) WHERE status = 'published';
+CREATE INDEX idx_courses_published_price ON courses ((COALESCE(price, 0)), id) WHERE status = 'published';
+CREATE INDEX idx_courses_published_newest ON courses (created_at DESC, id DESC) WHERE status = 'published';
+
+DROP INDEX IF EXISTS idx_courses_created_by;
+CREATE INDEX idx_courses_created_by ON courses (created_by, created_at DESC, id DESC);
+
+CREATE INDEX idx_courses_status_updated ON courses (status, updated_at, id);
+
+DROP INDEX IF EXISTS idx_notifications_user;
+CREATE INDEX idx_notifications_user ON notifications (user_id, created_at DESC, id DESC);
+
+CREATE INDEX idx_purchased_courses_user ON purchased_courses (user_id, purchased_at DESC, course_id DESC);
+
+CREATE INDEX idx_clicker_leaderboard_score ON clicker_leaderboard (score DESC, user_id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_clicker_leaderboard_score;
+DROP INDEX IF EXISTS idx_purchased_courses_user;
+
+DROP INDEX IF EXISTS idx_notifications_user;
+CREATE INDEX idx_notifications_user ON notifications (user_id, created_at DESC);
+
+DROP INDEX IF EXISTS idx_courses_status_updated;
+
+DROP INDEX IF EXISTS idx_courses_created_by;
+CREATE INDEX idx_courses_created_by ON courses (created_by, created_at DESC);
+
+DROP INDEX IF EXISTS idx_courses_published_newest;
+DROP INDEX IF EXISTS idx_courses_published_price;
+DROP INDEX IF EXISTS idx_courses_published_rating;
+DROP INDEX IF EXISTS idx_courses_published_popular;
+
+CREATE INDEX idx_courses_published_popular ON courses (students_count DESC, created_at DESC) WHERE status = 'published';
+CREATE INDEX idx_courses_published_rating ON courses (rating DESC NULLS LAST) WHERE status = 'published';
+CREATE INDEX idx_courses_published_price ON courses (price) WHERE status = 'published';
+CREATE INDEX idx_courses_published_newest ON courses (created_at DESC) WHERE status = 'published';
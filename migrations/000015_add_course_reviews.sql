@@ -0,0 +1,58 @@
+-- +goose Up
+-- Отзывы о курсах: один отзыв пользователя на курс
+CREATE TABLE reviews (
+    id UUID PRIMARY KEY,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    text TEXT NOT NULL DEFAULT '',
+    helpful_count INTEGER NOT NULL DEFAULT 0,
+    reports_count INTEGER NOT NULL DEFAULT 0,
+    -- Скрытый администратором отзыв не показывается и не учитывается в рейтинге
+    hidden_at TIMESTAMPTZ,
+    hidden_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (course_id, user_id)
+);
+
+CREATE INDEX idx_reviews_course_newest ON reviews (course_id, created_at DESC, id DESC) WHERE hidden_at IS NULL;
+CREATE INDEX idx_reviews_course_helpful ON reviews (course_id, helpful_count DESC, id DESC) WHERE hidden_at IS NULL;
+CREATE INDEX idx_reviews_reported ON reviews (reports_count DESC, id DESC) WHERE reports_count > 0 AND hidden_at IS NULL;
+
+-- Отметки «полезный отзыв»
+CREATE TABLE review_votes (
+    review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (review_id, user_id)
+);
+
+-- Жалобы на отзывы
+CREATE TABLE review_reports (
+    review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (review_id, user_id)
+);
+
+CREATE INDEX idx_review_reports_review ON review_reports (review_id, created_at DESC, user_id DESC);
+
+-- Распределение оценок курса по видимым отзывам. courses.rating - среднее по тем же отзывам.
+CREATE TABLE course_ratings (
+    course_id UUID PRIMARY KEY REFERENCES courses(id) ON DELETE CASCADE,
+    reviews_count INTEGER NOT NULL DEFAULT 0,
+    stars_1 INTEGER NOT NULL DEFAULT 0,
+    stars_2 INTEGER NOT NULL DEFAULT 0,
+    stars_3 INTEGER NOT NULL DEFAULT 0,
+    stars_4 INTEGER NOT NULL DEFAULT 0,
+    stars_5 INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS course_ratings;
+DROP TABLE IF EXISTS review_reports;
+DROP TABLE IF EXISTS review_votes;
+DROP TABLE IF EXISTS reviews;
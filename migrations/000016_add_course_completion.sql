@@ -0,0 +1,22 @@
+-- +goose Up
+-- Время завершения курса: все уроки курса пройдены
+ALTER TABLE purchased_courses ADD COLUMN completed_at TIMESTAMPTZ;
+
+-- Курсы, пройденные до этой миграции, отмечаются завершенными без бонуса XP
+UPDATE purchased_courses pc
+SET completed_at = done.completed_at
+FROM (
+    SELECT lp.user_id, l.course_id, MAX(lp.completed_at) AS completed_at
+    FROM lessons l
+    JOIN lesson_progress lp ON lp.lesson_id = l.id AND lp.is_completed
+    GROUP BY lp.user_id, l.course_id
+    HAVING COUNT(*) = (SELECT COUNT(*) FROM lessons WHERE course_id = l.course_id)
+) done
+WHERE pc.user_id = done.user_id AND pc.course_id = done.course_id;
+
+-- Бонус за завершение курса начисляется один раз
+CREATE UNIQUE INDEX idx_xp_entries_course_complete ON xp_entries (user_id, course_id) WHERE type = 'course_complete';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_xp_entries_course_complete;
+ALTER TABLE purchased_courses DROP COLUMN IF EXISTS completed_at;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Сертификаты о прохождении курса. Имя студента и название курса сохраняются
+-- на момент выдачи: сертификат не меняется при переименовании курса или профиля.
+CREATE TABLE certificates (
+    id UUID PRIMARY KEY,
+    serial VARCHAR(32) NOT NULL UNIQUE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    student_name VARCHAR(255) NOT NULL,
+    course_title VARCHAR(255) NOT NULL,
+    completed_at TIMESTAMPTZ NOT NULL,
+    issued_at TIMESTAMPTZ DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ,
+    revoked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    revoke_reason TEXT NOT NULL DEFAULT '',
+    UNIQUE (user_id, course_id)
+);
+
+CREATE INDEX idx_certificates_user ON certificates (user_id, issued_at DESC, id DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS certificates;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Порядок прохождения уроков курса: свободный, строго по order_num или по предварительным урокам
+ALTER TABLE courses ADD COLUMN progression VARCHAR(20) NOT NULL DEFAULT 'free'
+    CHECK (progression IN ('free', 'sequential', 'prerequisites'));
+
+-- Уроки, которые нужно пройти до урока lesson_id. Учитываются, если у курса progression = 'prerequisites'.
+CREATE TABLE lesson_prerequisites (
+    lesson_id UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    prerequisite_id UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    PRIMARY KEY (lesson_id, prerequisite_id),
+    CHECK (lesson_id <> prerequisite_id)
+);
+
+CREATE INDEX idx_lesson_prerequisites_prerequisite ON lesson_prerequisites (prerequisite_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS lesson_prerequisites;
+ALTER TABLE courses DROP COLUMN IF EXISTS progression;
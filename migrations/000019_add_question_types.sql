@@ -0,0 +1,30 @@
+-- +goose Up
+-- Типы вопросов, баллы и пояснения. Правильный ответ хранится в answer_key в формате,
+-- зависящем от типа; прежний correct_answer переносится в answer_key.choices.
+ALTER TABLE questions
+    ADD COLUMN type VARCHAR(20) NOT NULL DEFAULT 'single_choice'
+        CHECK (type IN ('single_choice', 'multiple_choice', 'true_false', 'numeric', 'short_text', 'ordering', 'matching')),
+    ADD COLUMN matches JSONB,
+    ADD COLUMN points INTEGER NOT NULL DEFAULT 1 CHECK (points > 0),
+    ADD COLUMN explanation TEXT NOT NULL DEFAULT '',
+    ADD COLUMN answer_key JSONB;
+
+UPDATE questions SET answer_key = jsonb_build_object('choices', jsonb_build_array(correct_answer));
+
+ALTER TABLE questions ALTER COLUMN answer_key SET NOT NULL;
+ALTER TABLE questions DROP COLUMN correct_answer;
+
+-- +goose Down
+-- Вопросы других типов не переносятся в прежнюю схему и удаляются
+DELETE FROM questions WHERE type <> 'single_choice';
+
+ALTER TABLE questions ADD COLUMN correct_answer INTEGER NOT NULL DEFAULT 0;
+UPDATE questions SET correct_answer = COALESCE((answer_key->'choices'->>0)::int, 0);
+ALTER TABLE questions ALTER COLUMN correct_answer DROP DEFAULT;
+
+ALTER TABLE questions
+    DROP COLUMN answer_key,
+    DROP COLUMN explanation,
+    DROP COLUMN points,
+    DROP COLUMN matches,
+    DROP COLUMN type;
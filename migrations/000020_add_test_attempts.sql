@@ -0,0 +1,35 @@
+-- +goose Up
+-- Пул вопросов: в попытку попадает question_count вопросов (0 - все), tag_quotas задает,
+-- сколько из них взять с каждым тегом. Вопросы и варианты ответа можно перемешивать.
+ALTER TABLE tests
+    ADD COLUMN question_count INTEGER NOT NULL DEFAULT 0 CHECK (question_count >= 0),
+    ADD COLUMN tag_quotas JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN shuffle_questions BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN shuffle_options BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE questions ADD COLUMN tags JSONB NOT NULL DEFAULT '[]';
+
+-- Попытка прохождения теста. items - снимок выданных вопросов вместе с правильными ответами
+-- и перестановками вариантов: ответы проверяются по нему, а не по текущим вопросам теста.
+CREATE TABLE test_attempts (
+    id UUID PRIMARY KEY,
+    test_id UUID NOT NULL REFERENCES tests(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    items JSONB NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    submitted_at TIMESTAMPTZ,
+    score INTEGER
+);
+
+-- Незавершенная попытка у пользователя может быть только одна
+CREATE UNIQUE INDEX idx_test_attempts_open ON test_attempts (user_id, test_id) WHERE submitted_at IS NULL;
+CREATE INDEX idx_test_attempts_user_test ON test_attempts (user_id, test_id, started_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS test_attempts;
+ALTER TABLE questions DROP COLUMN IF EXISTS tags;
+ALTER TABLE tests
+    DROP COLUMN IF EXISTS shuffle_options,
+    DROP COLUMN IF EXISTS shuffle_questions,
+    DROP COLUMN IF EXISTS tag_quotas,
+    DROP COLUMN IF EXISTS question_count;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- Ограничения попыток: max_attempts (0 - без ограничения), пауза между попытками cooldown_seconds,
+-- которая удваивается после каждой неудачной попытки подряд, и время на попытку time_limit_seconds
+-- (0 - без ограничения). scoring_policy - какой результат попыток идет в прогресс урока,
+-- late_submission - что делать с ответами, отправленными после истечения времени.
+ALTER TABLE tests
+    ADD COLUMN max_attempts INTEGER NOT NULL DEFAULT 0 CHECK (max_attempts >= 0),
+    ADD COLUMN cooldown_seconds INTEGER NOT NULL DEFAULT 0 CHECK (cooldown_seconds >= 0),
+    ADD COLUMN time_limit_seconds INTEGER NOT NULL DEFAULT 0 CHECK (time_limit_seconds >= 0),
+    ADD COLUMN scoring_policy VARCHAR(20) NOT NULL DEFAULT 'best'
+        CHECK (scoring_policy IN ('best', 'last', 'average')),
+    ADD COLUMN late_submission VARCHAR(20) NOT NULL DEFAULT 'reject'
+        CHECK (late_submission IN ('reject', 'grade'));
+
+-- expires_at считается от started_at при начале попытки, поэтому изменение лимита
+-- не влияет на уже начатые попытки. late - попытка завершена после истечения времени.
+ALTER TABLE test_attempts
+    ADD COLUMN expires_at TIMESTAMPTZ,
+    ADD COLUMN late BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE test_attempts
+    DROP COLUMN IF EXISTS late,
+    DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE tests
+    DROP COLUMN IF EXISTS late_submission,
+    DROP COLUMN IF EXISTS scoring_policy,
+    DROP COLUMN IF EXISTS time_limit_seconds,
+    DROP COLUMN IF EXISTS cooldown_seconds,
+    DROP COLUMN IF EXISTS max_attempts;
@@ -0,0 +1,32 @@
+-- +goose Up
+-- review_policy - когда студенту показываются правильные ответы при разборе попыток:
+-- never - никогда, after_submit - после каждой попытки, after_pass - после прохождения теста.
+ALTER TABLE tests ADD COLUMN review_policy VARCHAR(20) NOT NULL DEFAULT 'never'
+    CHECK (review_policy IN ('never', 'after_submit', 'after_pass'));
+
+ALTER TABLE test_attempts
+    ADD COLUMN points DOUBLE PRECISION,
+    ADD COLUMN max_points INTEGER;
+
+-- Ответы попытки по вопросам. Ответ хранится в исходном порядке вариантов вопроса,
+-- question_id без внешнего ключа: вопрос может быть удален, а его снимок остается в test_attempts.items.
+CREATE TABLE test_attempt_answers (
+    attempt_id UUID NOT NULL REFERENCES test_attempts(id) ON DELETE CASCADE,
+    question_id UUID NOT NULL,
+    position INTEGER NOT NULL,
+    answer JSONB,
+    points DOUBLE PRECISION NOT NULL,
+    max_points INTEGER NOT NULL,
+    correct BOOLEAN NOT NULL,
+    PRIMARY KEY (attempt_id, question_id)
+);
+
+CREATE INDEX idx_test_attempts_test ON test_attempts (test_id, submitted_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_test_attempts_test;
+DROP TABLE IF EXISTS test_attempt_answers;
+ALTER TABLE test_attempts
+    DROP COLUMN IF EXISTS max_points,
+    DROP COLUMN IF EXISTS points;
+ALTER TABLE tests DROP COLUMN IF EXISTS review_policy;
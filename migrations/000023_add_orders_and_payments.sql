@@ -0,0 +1,51 @@
+-- +goose Up
+-- Заказ курса. Статусы: created -> pending (создана платежная сессия) -> paid или failed,
+-- paid -> refunded. Из failed заказ может перейти в paid, если платеж прошел после ошибки.
+-- amount - в минимальных единицах валюты (копейках).
+CREATE TABLE orders (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'created'
+        CHECK (status IN ('created', 'pending', 'paid', 'failed', 'refunded')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Незавершенный заказ курса у пользователя может быть только один
+CREATE UNIQUE INDEX idx_orders_open ON orders (user_id, course_id) WHERE status IN ('created', 'pending');
+CREATE INDEX idx_orders_user ON orders (user_id, created_at DESC);
+
+-- Платежная сессия заказа у провайдера
+CREATE TABLE payments (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    session_id VARCHAR(255) NOT NULL,
+    checkout_url TEXT NOT NULL,
+    provider_payment_id VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'paid', 'failed', 'refunded')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, session_id)
+);
+
+CREATE INDEX idx_payments_order ON payments (order_id, created_at DESC);
+
+-- Обработанные уведомления провайдера: повторная доставка того же события ничего не меняет
+CREATE TABLE payment_events (
+    provider VARCHAR(50) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    payment_id UUID NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, event_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS payment_events;
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS orders;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Раньше сертификат студента без имени и фамилии выдавался на email, а имя в сертификате
+-- видно на публичной странице проверки. Заменяем такие имена на нейтральное.
+UPDATE certificates c
+SET student_name = 'Слушатель курса'
+FROM users u
+WHERE u.id = c.user_id AND c.student_name = u.email;
+
+-- +goose Down
+-- Исходные имена не восстанавливаются: email не должен снова попасть в сертификаты.
@@ -0,0 +1,91 @@
+// Package auth содержит общую для сервисов платформы проверку доступа через
+// gRPC-сервис авторизации: gin middleware с таймаутом, LRU-кэш решений по токенам
+// и типизированный доступ к пользователю запроса.
+// Контракт сервиса авторизации описан в authpb/auth.proto.
+package auth
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Ключи gin.Context, в которых middleware сохраняет пользователя запроса
+const (
+	ContextUserID = "user_id"
+	ContextRole   = "user_role"
+)
+
+// Значения по умолчанию
+const (
+	DefaultTimeout   = 3 * time.Second
+	DefaultCacheSize = 10000
+	DefaultCacheTTL  = 30 * time.Second
+)
+
+// Config содержит настройки проверки доступа
+type Config struct {
+	// Timeout - время ожидания ответа сервиса авторизации
+	Timeout time.Duration `yaml:"timeout" env:"AUTH_CHECK_TIMEOUT" default:"3s" validate:"gt=0"`
+	// CacheSize - максимальное количество токенов в кэше; 0 отключает кэш
+	CacheSize int `yaml:"cache_size" env:"AUTH_CACHE_SIZE" default:"10000" validate:"gte=0"`
+	// CacheTTL - время, в течение которого решение по токену берется из кэша.
+	// Смена пароля или роли вступает в силу не позже, чем через CacheTTL.
+	CacheTTL time.Duration `yaml:"cache_ttl" env:"AUTH_CACHE_TTL" default:"30s" validate:"gte=0"`
+}
+
+// DefaultConfig возвращает настройки по умолчанию
+func DefaultConfig() Config {
+	return Config{
+		Timeout:   DefaultTimeout,
+		CacheSize: DefaultCacheSize,
+		CacheTTL:  DefaultCacheTTL,
+	}
+}
+
+// Identity - владелец токена
+type Identity struct {
+	UserID uuid.UUID
+	Role   string
+}
+
+// HasAnyRole сообщает, есть ли у пользователя одна из ролей.
+// Пустой список ролей означает, что достаточно действительного токена.
+func (i Identity) HasAnyRole(roles ...string) bool {
+	if len(roles) == 0 {
+		return true
+	}
+	for _, role := range roles {
+		if i.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// UserID возвращает ID пользователя, прошедшего проверку доступа
+func UserID(c *gin.Context) (uuid.UUID, bool) {
+	value, ok := c.Get(ContextUserID)
+	if !ok {
+		return uuid.Nil, false
+	}
+	userID, ok := value.(uuid.UUID)
+	return userID, ok
+}
+
+// Role возвращает роль пользователя, прошедшего проверку доступа,
+// или пустую строку для анонимного запроса
+func Role(c *gin.Context) string {
+	return c.GetString(ContextRole)
+}
+
+// MustUserID возвращает ID пользователя для маршрутов за RequireRoles и паникует,
+// если проверка доступа не выполнялась
+func MustUserID(c *gin.Context) uuid.UUID {
+	userID, ok := UserID(c)
+	if !ok {
+		panic("auth: запрос не прошел проверку доступа")
+	}
+	return userID
+}
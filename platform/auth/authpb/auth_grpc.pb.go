@@ -2,9 +2,9 @@
 // versions:
 // - protoc-gen-go-grpc v1.5.1
 // - protoc             v6.30.2
-// source: internal/transport/grpc/auth.proto
+// source: auth/authpb/auth.proto
 
-package grpc
+package authpb
 
 import (
 	context "context"
@@ -117,5 +117,5 @@ var AuthService_ServiceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
-	Metadata: "internal/transport/grpc/auth.proto",
+	Metadata: "auth/authpb/auth.proto",
 }
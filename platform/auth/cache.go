@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"platform/metrics"
+)
+
+var cacheLookups = metrics.NewCounterVec("auth_client", "cache_lookups_total",
+	"Обращения к кэшу решений по токенам", "result")
+
+// cache - LRU-кэш владельцев токенов с ограниченным временем жизни записей.
+// Токены хранятся в виде SHA-256.
+type cache struct {
+	size int
+	ttl  time.Duration
+
+	mu    sync.Mutex
+	items map[[sha256.Size]byte]*list.Element
+	order *list.List
+}
+
+type cacheEntry struct {
+	key       [sha256.Size]byte
+	identity  Identity
+	expiresAt time.Time
+}
+
+// newCache создает кэш; при size <= 0 или ttl <= 0 возвращает nil, и кэширование отключено
+func newCache(size int, ttl time.Duration) *cache {
+	if size <= 0 || ttl <= 0 {
+		return nil
+	}
+	return &cache{
+		size:  size,
+		ttl:   ttl,
+		items: make(map[[sha256.Size]byte]*list.Element, size),
+		order: list.New(),
+	}
+}
+
+// Get возвращает владельца токена, если запись есть и не устарела
+func (c *cache) Get(token string) (Identity, bool) {
+	if c == nil {
+		return Identity{}, false
+	}
+
+	key := sha256.Sum256([]byte(token))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		cacheLookups.WithLabelValues("miss").Inc()
+		return Identity{}, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.items, key)
+		cacheLookups.WithLabelValues("expired").Inc()
+		return Identity{}, false
+	}
+
+	c.order.MoveToFront(element)
+	cacheLookups.WithLabelValues("hit").Inc()
+	return entry.identity, true
+}
+
+// Add сохраняет владельца токена, вытесняя давно не использованные записи
+func (c *cache) Add(token string, identity Identity) {
+	if c == nil {
+		return
+	}
+
+	key := sha256.Sum256([]byte(token))
+	now := time.Now()
+	expiresAt := now.Add(c.ttl)
+	// Запись не должна пережить сам токен
+	if exp, ok := tokenExpiry(token); ok && exp.Before(expiresAt) {
+		expiresAt = exp
+	}
+	if !expiresAt.After(now) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		entry := element.Value.(*cacheEntry)
+		entry.identity = identity
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&cacheEntry{key: key, identity: identity, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// tokenExpiry читает срок действия exp из JWT без проверки подписи: подпись проверил сервис
+// авторизации, а exp нужен только для того, чтобы ограничить время жизни записи кэша
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp *float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(*claims.Exp), 0), true
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"platform/auth/authpb"
+	"platform/logging"
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+// errInsufficientPermissions совпадает с ответом сервиса авторизации, чтобы
+// решение из кэша не отличалось от решения сервиса
+const errInsufficientPermissions = "insufficient permissions"
+
+// DeniedError - отказ в доступе: токен недействителен (401) или у пользователя нет нужной роли (403)
+type DeniedError struct {
+	Status  int
+	Message string
+}
+
+func (e *DeniedError) Error() string {
+	return e.Message
+}
+
+// Middleware проверяет токены запросов через сервис авторизации
+type Middleware struct {
+	client authpb.AuthServiceClient
+	cfg    Config
+	cache  *cache
+}
+
+// NewMiddleware создает проверку доступа поверх соединения с сервисом авторизации
+func NewMiddleware(conn grpc.ClientConnInterface, cfg Config) *Middleware {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	return &Middleware{
+		client: authpb.NewAuthServiceClient(conn),
+		cfg:    cfg,
+		cache:  newCache(cfg.CacheSize, cfg.CacheTTL),
+	}
+}
+
+// RequireRoles пропускает запрос с действительным токеном и, если роли указаны,
+// хотя бы одной из них. ID и роль пользователя доступны через UserID и Role.
+func (m *Middleware) RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m.Authorize(c, roles...) {
+			c.Next()
+		}
+	}
+}
+
+// Authorize проверяет токен из заголовка Authorization и, если указаны роли, наличие хотя бы одной из них.
+// При успехе сохраняет пользователя в контексте, иначе прерывает запрос с ошибкой.
+func (m *Middleware) Authorize(c *gin.Context, roles ...string) bool {
+	token := bearerToken(c.GetHeader("Authorization"))
+	if token == "" {
+		problem.Abort(c, problem.Unauthorized)
+		return false
+	}
+
+	identity, err := m.Check(c.Request.Context(), token, roles...)
+	if denied, ok := err.(*DeniedError); ok {
+		if denied.Status == http.StatusUnauthorized {
+			problem.Abort(c, problem.TokenInvalid)
+		} else {
+			problem.Abort(c, problem.Forbidden)
+		}
+		return false
+	}
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "ошибка при проверке прав доступа", slog.Any("error", err))
+		problem.Abort(c, problem.ServiceUnavailable)
+		return false
+	}
+
+	c.Set(ContextUserID, identity.UserID)
+	if identity.Role != "" {
+		c.Set(ContextRole, identity.Role)
+	}
+	logging.SetUserID(c, identity.UserID)
+	return true
+}
+
+// Check возвращает владельца токена. Если указаны роли, у пользователя должна быть хотя бы одна из них.
+// Отказ возвращается как *DeniedError, прочие ошибки означают, что сервис авторизации недоступен.
+// Решения по действительным токенам кэшируются на Config.CacheTTL, но не дольше срока действия токена (exp).
+func (m *Middleware) Check(ctx context.Context, token string, roles ...string) (Identity, error) {
+	if identity, ok := m.cache.Get(token); ok {
+		if !identity.HasAnyRole(roles...) {
+			return identity, &DeniedError{Status: http.StatusForbidden, Message: errInsufficientPermissions}
+		}
+		return identity, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	defer cancel()
+
+	resp, err := m.client.CheckAccess(ctx, &authpb.CheckAccessRequest{
+		Token:         token,
+		RequiredRoles: roles,
+	})
+	if err != nil {
+		return Identity{}, fmt.Errorf("запрос к сервису авторизации: %w", err)
+	}
+
+	// Сервис не возвращает пользователя, если токен недействителен
+	if resp.UserId == "" {
+		return Identity{}, &DeniedError{Status: http.StatusUnauthorized, Message: resp.Error}
+	}
+
+	userID, err := uuid.Parse(resp.UserId)
+	if err != nil {
+		return Identity{}, fmt.Errorf("некорректный формат ID пользователя: %w", err)
+	}
+	identity := Identity{UserID: userID, Role: resp.Role}
+
+	// Без роли решение зависит от набора ролей запроса, поэтому не кэшируется
+	if identity.Role != "" {
+		m.cache.Add(token, identity)
+	}
+
+	if !resp.Allowed {
+		return identity, &DeniedError{Status: http.StatusForbidden, Message: resp.Error}
+	}
+	return identity, nil
+}
+
+// bearerToken извлекает токен из заголовка Authorization; префикс "Bearer " необязателен
+func bearerToken(header string) string {
+	return strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+}
@@ -0,0 +1,144 @@
+// Package config заполняет структуру конфигурации сервиса по тегам полей.
+//
+// Источники значений в порядке возрастания приоритета:
+//   - тег default;
+//   - YAML-файл из флага -config или переменной CONFIG_FILE, ключи - из тега yaml;
+//   - переменные окружения из тега env (через запятую можно указать устаревшие имена);
+//   - флаги командной строки: имя получается из первого имени env, JWT_SECRET -> -jwt-secret.
+//
+// После загрузки значения проверяются по тегу validate (go-playground/validator).
+// Некорректное значение в любом источнике - ошибка: сервис не запускается
+// с молча подставленным значением по умолчанию.
+//
+// Флаг -print-config печатает итоговую конфигурацию в формате YAML и завершает процесс.
+// Поля с тегом secret:"true" и пароли в URL при печати скрываются.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvFile - переменная окружения с путем к YAML-файлу конфигурации
+const EnvFile = "CONFIG_FILE"
+
+// Load заполняет cfg (указатель на структуру) из всех источников и проверяет результат.
+// args - аргументы командной строки без имени программы, обычно os.Args[1:].
+func Load(cfg any, args []string) error {
+	fields, err := collect(cfg)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, f := range fields {
+		if f.def == "" {
+			continue
+		}
+		if err := f.set(f.def); err != nil {
+			errs = append(errs, fmt.Errorf("%s: значение по умолчанию: %w", f.name(), err))
+		}
+	}
+
+	flags := flag.NewFlagSet(programName(), flag.ExitOnError)
+	file := flags.String("config", os.Getenv(EnvFile), "путь к YAML-файлу конфигурации")
+	printConfig := flags.Bool("print-config", false, "напечатать итоговую конфигурацию и завершить работу")
+	flagValues := make(map[*field]string)
+	for _, f := range fields {
+		if len(f.env) == 0 {
+			continue
+		}
+		flags.Func(f.flagName(), "переменная окружения "+f.env[0], func(value string) error {
+			flagValues[f] = value
+			return nil
+		})
+	}
+	// При ошибке разбора флагов FlagSet сам печатает справку и завершает процесс
+	_ = flags.Parse(args)
+
+	if *file != "" {
+		if err := loadFile(cfg, *file); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range fields {
+		for i, name := range f.env {
+			value, ok := os.LookupEnv(name)
+			if !ok || value == "" {
+				continue
+			}
+			if i > 0 {
+				slog.Warn("устаревшее имя переменной окружения", slog.String("name", name), slog.String("use", f.env[0]))
+			}
+			if err := f.set(value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+			break
+		}
+	}
+
+	for _, f := range fields {
+		value, ok := flagValues[f]
+		if !ok {
+			continue
+		}
+		if err := f.set(value); err != nil {
+			errs = append(errs, fmt.Errorf("-%s: %w", f.flagName(), err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	err = validate(cfg)
+
+	if *printConfig {
+		if printErr := Print(os.Stdout, cfg); printErr != nil {
+			err = errors.Join(err, printErr)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		os.Exit(0)
+	}
+
+	return err
+}
+
+// loadFile читает YAML-файл конфигурации. Неизвестные ключи считаются ошибкой,
+// чтобы опечатка в имени не приводила к молчаливому использованию значения по умолчанию.
+func loadFile(cfg any, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("файл конфигурации: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("файл конфигурации %s: %w", path, err)
+	}
+	return nil
+}
+
+// programName возвращает имя программы для справки по флагам
+func programName() string {
+	if len(os.Args) == 0 {
+		return "service"
+	}
+	name := os.Args[0]
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
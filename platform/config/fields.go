@@ -0,0 +1,163 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// field - конечное поле конфигурации
+type field struct {
+	path  string
+	env   []string
+	def   string
+	value reflect.Value
+}
+
+// name возвращает основное имя переменной окружения или путь к полю
+func (f *field) name() string {
+	if len(f.env) > 0 {
+		return f.env[0]
+	}
+	return f.path
+}
+
+// flagName возвращает имя флага: JWT_SECRET -> jwt-secret
+func (f *field) flagName() string {
+	return strings.ReplaceAll(strings.ToLower(f.env[0]), "_", "-")
+}
+
+// set разбирает строковое значение и записывает его в поле
+func (f *field) set(raw string) error {
+	return setValue(f.value, raw)
+}
+
+// collect обходит структуру и возвращает ее конечные поля.
+// Вложенные структуры обходятся рекурсивно, если они не разбираются из строки сами.
+func collect(cfg any) ([]*field, error) {
+	value := reflect.ValueOf(cfg)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("config: ожидается указатель на структуру")
+	}
+
+	var fields []*field
+	walk(value.Elem(), "", func(path string, sf reflect.StructField, v reflect.Value) {
+		f := &field{
+			path:  path,
+			def:   sf.Tag.Get("default"),
+			value: v,
+		}
+		if env := sf.Tag.Get("env"); env != "" {
+			f.env = strings.Split(env, ",")
+		}
+		fields = append(fields, f)
+	})
+	return fields, nil
+}
+
+// walk вызывает visit для каждого конечного экспортируемого поля структуры
+func walk(v reflect.Value, prefix string, visit func(path string, sf reflect.StructField, v reflect.Value)) {
+	t := v.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		path := yamlName(sf)
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+
+		fv := v.Field(i)
+		if isNested(fv) {
+			walk(fv, path, visit)
+			continue
+		}
+		visit(path, sf, fv)
+	}
+}
+
+// isNested сообщает, нужно ли обходить поле как вложенную структуру
+func isNested(v reflect.Value) bool {
+	return v.Kind() == reflect.Struct && !v.Addr().Type().Implements(textUnmarshalerType)
+}
+
+// yamlName возвращает ключ поля в YAML-файле
+func yamlName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+	if name == "" {
+		return strings.ToLower(sf.Name)
+	}
+	return name
+}
+
+// setValue разбирает строку в значение поля.
+// Срезы строк задаются через запятую, остальные срезы - через encoding.TextUnmarshaler.
+func setValue(v reflect.Value, raw string) error {
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	if v.Type() == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("некорректная длительность %q", raw)
+		}
+		v.SetInt(int64(duration))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("некорректное логическое значение %q", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("некорректное целое число %q", raw)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("некорректное целое число %q", raw)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("некорректное число %q", raw)
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("неподдерживаемый тип %s", v.Type())
+		}
+		items := reflect.MakeSlice(v.Type(), 0, 0)
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = reflect.Append(items, reflect.ValueOf(item).Convert(v.Type().Elem()))
+			}
+		}
+		v.Set(items)
+	default:
+		return fmt.Errorf("неподдерживаемый тип %s", v.Type())
+	}
+	return nil
+}
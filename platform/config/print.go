@@ -0,0 +1,112 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted заменяет значение секрета при печати
+const redacted = "***"
+
+// Print печатает конфигурацию в формате YAML, пригодном для файла конфигурации.
+// Рядом с каждым значением указана переменная окружения. Значения полей
+// с тегом secret:"true" и пароли в URL заменяются на ***.
+func Print(w io.Writer, cfg any) error {
+	value := reflect.ValueOf(cfg)
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("config: ожидается структура, получен %s", value.Kind())
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(structNode(value)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// structNode строит YAML-узел для структуры
+func structNode(v reflect.Value) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	t := v.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: yamlName(sf)}
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct && !fv.Type().Implements(textMarshalerType) && fv.Type() != durationType {
+			node.Content = append(node.Content, key, structNode(fv))
+			continue
+		}
+
+		valueNode := scalarNode(fv, sf.Tag.Get("secret") == "true")
+		if env, _, _ := strings.Cut(sf.Tag.Get("env"), ","); env != "" {
+			valueNode.LineComment = env
+		}
+		node.Content = append(node.Content, key, valueNode)
+	}
+	return node
+}
+
+// scalarNode строит YAML-узел для конечного значения
+func scalarNode(v reflect.Value, secret bool) *yaml.Node {
+	if secret {
+		value := ""
+		if !v.IsZero() {
+			value = redacted
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	}
+
+	if marshaler, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			text = []byte(err.Error())
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(text)}
+	}
+
+	switch {
+	case v.Type() == durationType:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: time.Duration(v.Int()).String()}
+	case v.Kind() == reflect.String:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: redactURL(v.String())}
+	case v.Kind() == reflect.Slice:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for i := range v.Len() {
+			node.Content = append(node.Content, scalarNode(v.Index(i), false))
+		}
+		return node
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(v.Interface())}
+	}
+}
+
+// redactURL скрывает пароль, если строка - URL с учетными данными
+func redactURL(value string) string {
+	if !strings.Contains(value, "://") {
+		return value
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	if _, ok := u.User.Password(); !ok {
+		return value
+	}
+	// Строка собирается заменой, чтобы *** не экранировалось как %2A%2A%2A
+	return strings.Replace(value, u.User.String()+"@", url.User(u.User.Username()).String()+":"+redacted+"@", 1)
+}
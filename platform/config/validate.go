@@ -0,0 +1,63 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validate проверяет конфигурацию по тегам validate и возвращает все нарушения сразу
+func validate(cfg any) error {
+	v := validator.New()
+	// В сообщениях поле называется так же, как переменная окружения
+	v.RegisterTagNameFunc(func(sf reflect.StructField) string {
+		if env := sf.Tag.Get("env"); env != "" {
+			name, _, _ := strings.Cut(env, ",")
+			return name
+		}
+		return yamlName(sf)
+	})
+
+	err := v.Struct(cfg)
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	errs := make([]error, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		errs = append(errs, fmt.Errorf("%s: %s", fe.Field(), describe(fe)))
+	}
+	return errors.Join(errs...)
+}
+
+// describe возвращает описание нарушенного правила
+func describe(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "обязательное значение не задано"
+	case "oneof":
+		return "допустимые значения: " + fe.Param()
+	case "min", "gte":
+		if fe.Kind() == reflect.String {
+			return "длина должна быть не меньше " + fe.Param()
+		}
+		return "значение должно быть не меньше " + fe.Param()
+	case "gt":
+		return "значение должно быть больше " + fe.Param()
+	case "lte", "max":
+		return "значение должно быть не больше " + fe.Param()
+	case "gtfield":
+		return "значение должно быть больше поля " + fe.Param()
+	case "ltefield":
+		return "значение должно быть не больше поля " + fe.Param()
+	default:
+		if fe.Param() != "" {
+			return fmt.Sprintf("не выполнено правило %s=%s", fe.Tag(), fe.Param())
+		}
+		return "не выполнено правило " + fe.Tag()
+	}
+}
@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// Postgres проверяет соединение с базой данных
+func Postgres(db *sql.DB) Check {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// GRPCConn проверяет состояние соединения gRPC. Простаивающее соединение
+// переводится в активное, проверка ждет установки соединения до таймаута.
+func GRPCConn(conn *grpc.ClientConn) Check {
+	return func(ctx context.Context) error {
+		for {
+			state := conn.GetState()
+			switch state {
+			case connectivity.Ready:
+				return nil
+			case connectivity.Idle:
+				conn.Connect()
+			case connectivity.TransientFailure, connectivity.Shutdown:
+				return fmt.Errorf("соединение gRPC в состоянии %s", state)
+			}
+
+			if !conn.WaitForStateChange(ctx, state) {
+				return fmt.Errorf("соединение gRPC в состоянии %s: %w", state, ctx.Err())
+			}
+		}
+	}
+}
+
+// TCP проверяет, что адрес принимает TCP-соединения
+func TCP(addr string) Check {
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// HTTP проверяет, что GET по адресу отвечает статусом 2xx
+func HTTP(client *http.Client, url string) Check {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("статус ответа %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
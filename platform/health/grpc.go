@@ -0,0 +1,65 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// RegisterGRPC регистрирует на сервере стандартный сервис grpc.health.v1 и выставляет
+// его статус по результатам проверок готовности. Статус выставляется для всего сервера ("")
+// и для перечисленных сервисов. Статус обновляется в GRPCHealth.Watch, при Drain
+// все сервисы сразу переходят в NOT_SERVING.
+func (c *Checker) RegisterGRPC(server *grpc.Server, services ...string) *GRPCHealth {
+	h := &GRPCHealth{
+		checker:  c,
+		server:   grpchealth.NewServer(),
+		services: services,
+	}
+	healthpb.RegisterHealthServer(server, h.server)
+
+	c.mu.Lock()
+	c.onDrain = append(c.onDrain, h.server.Shutdown)
+	c.mu.Unlock()
+
+	h.update(context.Background())
+	return h
+}
+
+// GRPCHealth - статус grpc.health.v1, зависящий от проверок готовности
+type GRPCHealth struct {
+	checker  *Checker
+	server   *grpchealth.Server
+	services []string
+}
+
+// Watch обновляет статус с периодом interval до отмены контекста
+func (h *GRPCHealth) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.update(ctx)
+		}
+	}
+}
+
+// update выполняет проверки и выставляет статус.
+// После Shutdown сервер health сам игнорирует обновления и остается NOT_SERVING.
+func (h *GRPCHealth) update(ctx context.Context) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if !h.checker.Run(ctx).OK() {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	h.server.SetServingStatus("", status)
+	for _, service := range h.services {
+		h.server.SetServingStatus(service, status)
+	}
+}
@@ -0,0 +1,185 @@
+// Package health содержит общие для сервисов платформы проверки работоспособности:
+// /livez (процесс жив), /readyz (зависимости доступны) и grpc.health.v1.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Статусы проверок
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusFail     = "fail"
+)
+
+// DefaultTimeout - таймаут одной проверки по умолчанию
+const DefaultTimeout = 2 * time.Second
+
+// Check проверяет одну зависимость. Ошибка означает, что зависимость недоступна.
+type Check func(ctx context.Context) error
+
+// Result - результат одной проверки
+type Result struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report - результат всех проверок
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// OK сообщает, прошли ли все проверки
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Checker выполняет проверки готовности сервиса
+type Checker struct {
+	timeout time.Duration
+
+	mu      sync.RWMutex
+	checks  map[string]Check
+	onDrain []func()
+
+	draining atomic.Bool
+}
+
+// NewChecker создает набор проверок с таймаутом на каждую проверку
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Checker{
+		timeout: timeout,
+		checks:  make(map[string]Check),
+	}
+}
+
+// Add добавляет проверку зависимости
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// Drain переводит сервис в режим остановки: с этого момента готовность всегда
+// отрицательная, чтобы балансировщики перестали направлять запросы на экземпляр.
+// Живость не меняется.
+func (c *Checker) Drain() {
+	if c.draining.Swap(true) {
+		return
+	}
+
+	c.mu.RLock()
+	onDrain := c.onDrain
+	c.mu.RUnlock()
+
+	for _, fn := range onDrain {
+		fn()
+	}
+}
+
+// Draining сообщает, остановлен ли сервис
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+// Run выполняет все проверки параллельно.
+// В режиме остановки проверки не выполняются: зависимости могут уже закрываться.
+func (c *Checker) Run(ctx context.Context) Report {
+	if c.Draining() {
+		return Report{
+			Status: StatusFail,
+			Checks: map[string]Result{
+				"shutdown": {Status: StatusFail, Error: "сервис останавливается"},
+			},
+		}
+	}
+
+	c.mu.RLock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.RUnlock()
+
+	results := RunChecks(ctx, c.timeout, checks)
+
+	status := StatusOK
+	for _, result := range results {
+		if result.Status != StatusOK {
+			status = StatusFail
+			break
+		}
+	}
+
+	return Report{Status: status, Checks: results}
+}
+
+// RunChecks выполняет проверки параллельно, каждую со своим таймаутом
+func RunChecks(ctx context.Context, timeout time.Duration, checks map[string]Check) map[string]Result {
+	results := make(map[string]Result, len(checks))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check(checkCtx)
+			result := Result{
+				Status:    StatusOK,
+				LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				result.Status = StatusFail
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Register добавляет маршруты GET /livez и GET /readyz.
+// /livez отвечает 200, пока процесс обслуживает запросы; /readyz - 503, если не прошла хотя бы одна проверка.
+func (c *Checker) Register(router gin.IRoutes) {
+	router.GET("/livez", Livez)
+	router.GET("/readyz", c.Readyz)
+}
+
+// Livez сообщает, что процесс жив
+func Livez(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": StatusOK})
+}
+
+// Readyz выполняет проверки готовности
+func (c *Checker) Readyz(ctx *gin.Context) {
+	report := c.Run(ctx.Request.Context())
+
+	status := http.StatusOK
+	if !report.OK() {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, report)
+}
@@ -0,0 +1,106 @@
+// Package lifecycle управляет запуском и корректной остановкой сервисов платформы:
+// серверы работают до сигнала SIGTERM или SIGINT, после чего сервис выводится из
+// балансировки, дожидается завершения начатых запросов и освобождает ресурсы.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Значения по умолчанию для остановки сервиса
+const (
+	// DefaultShutdownTimeout - общее время на остановку, включая DrainDelay
+	DefaultShutdownTimeout = 20 * time.Second
+	// DefaultDrainDelay - время между переходом readiness в "не готов" и закрытием серверов
+	DefaultDrainDelay = 5 * time.Second
+)
+
+// readHeaderTimeout ограничивает время чтения заголовков запроса
+const readHeaderTimeout = 10 * time.Second
+
+// SignalContext возвращает контекст, который отменяется при получении SIGTERM или SIGINT
+func SignalContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(parent, syscall.SIGTERM, os.Interrupt)
+}
+
+// NewHTTPServer создает HTTP-сервер на порту port
+func NewHTTPServer(port string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              ":" + port,
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+}
+
+// ServeHTTP принимает соединения до вызова Shutdown.
+// http.ErrServerClosed после Shutdown ошибкой не считается.
+func ServeHTTP(server *http.Server) error {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Run запускает серверы и ждет отмены контекста или завершения любого из них,
+// затем вызывает stop с контекстом, ограниченным timeout.
+// Возвращает ошибку сервера, завершившегося раньше времени, и ошибку остановки.
+func Run(ctx context.Context, timeout time.Duration, stop func(context.Context) error, servers ...func() error) error {
+	errCh := make(chan error, len(servers))
+	for _, serve := range servers {
+		go func(serve func() error) {
+			errCh <- serve()
+		}(serve)
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+	case runErr = <-errCh:
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return errors.Join(runErr, stop(stopCtx))
+}
+
+// Wait ждет d или отмены контекста
+func Wait(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// StopGRPC останавливает gRPC-сервер: перестает принимать соединения и ждет
+// завершения начатых вызовов. Если контекст истекает раньше, оставшиеся
+// вызовы прерываются.
+func StopGRPC(ctx context.Context, server *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		server.Stop()
+		<-done
+	}
+}
@@ -0,0 +1,47 @@
+package lifecycle
+
+import (
+	"context"
+	"sync"
+)
+
+// Workers запускает фоновые задачи сервиса с общим контекстом,
+// который отменяется при остановке
+type Workers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWorkers создает пустой набор фоновых задач
+func NewWorkers() *Workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Workers{ctx: ctx, cancel: cancel}
+}
+
+// Go запускает задачу. Задача должна завершиться после отмены переданного контекста.
+func (w *Workers) Go(task func(ctx context.Context)) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		task(w.ctx)
+	}()
+}
+
+// Stop отменяет контекст задач и ждет их завершения, но не дольше ctx
+func (w *Workers) Stop(ctx context.Context) error {
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
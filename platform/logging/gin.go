@@ -0,0 +1,183 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// HeaderRequestID - заголовок с идентификатором запроса
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength - максимальная длина принимаемого от клиента идентификатора
+const maxRequestIDLength = 128
+
+// Middleware присваивает запросу идентификатор, пишет строку журнала на каждый запрос
+// и добавляет request_id, user_id и route в JSON-ответы с ошибкой, в том числе problem+json.
+// Заменяет gin.Logger.
+func Middleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(HeaderRequestID)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.NewString()
+		}
+
+		// Заголовок запроса передается дальше при проксировании
+		c.Request.Header.Set(HeaderRequestID, requestID)
+		c.Header(HeaderRequestID, requestID)
+
+		ctx := WithRequestID(c.Request.Context(), requestID)
+		if route := c.FullPath(); route != "" {
+			ctx = WithRoute(ctx, route)
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		w := &errorWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+
+		c.Next()
+
+		ctx = c.Request.Context()
+		w.flush(ctx)
+
+		status := c.Writer.Status()
+		attrs := []any{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+		if w.message != "" {
+			attrs = append(attrs, slog.String("error", w.message))
+		}
+
+		switch {
+		case status >= 500:
+			logger.ErrorContext(ctx, "request failed", attrs...)
+		case status >= 400:
+			logger.WarnContext(ctx, "request rejected", attrs...)
+		default:
+			logger.InfoContext(ctx, "request completed", attrs...)
+		}
+	}
+}
+
+// Recovery перехватывает панику в обработчике, пишет ее в журнал и отвечает 500.
+// Заменяет gin.Recovery.
+func Recovery(logger *slog.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		logger.ErrorContext(c.Request.Context(), "panic recovered",
+			slog.Any("panic", recovered),
+			slog.String("stack", string(debug.Stack())),
+		)
+		problem.Abort(c, problem.Internal)
+	})
+}
+
+// SetUserID сохраняет ID пользователя в контексте запроса для журнала
+func SetUserID(c *gin.Context, userID fmt.Stringer) {
+	c.Request = c.Request.WithContext(WithUserID(c.Request.Context(), userID.String()))
+}
+
+// SetRoute сохраняет маршрут в контексте запроса для журнала
+func SetRoute(c *gin.Context, route string) {
+	c.Request = c.Request.WithContext(WithRoute(c.Request.Context(), route))
+}
+
+// errorWriter задерживает JSON-ответы с ошибкой, чтобы добавить в них атрибуты запроса
+type errorWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	buffering bool
+	// message - текст ошибки из ответа для журнала
+	message string
+}
+
+// Write буферизует тело ответа с ошибкой или передает остальные ответы без изменений
+func (w *errorWriter) Write(b []byte) (int, error) {
+	if w.buffering || w.startBuffering() {
+		return w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString буферизует тело ответа с ошибкой или передает остальные ответы без изменений
+func (w *errorWriter) WriteString(s string) (int, error) {
+	if w.buffering || w.startBuffering() {
+		return w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Written сообщает, начата ли запись ответа
+func (w *errorWriter) Written() bool {
+	return w.buffering || w.ResponseWriter.Written()
+}
+
+// Flush не отправляет задержанный ответ раньше времени
+func (w *errorWriter) Flush() {
+	if !w.buffering {
+		w.ResponseWriter.Flush()
+	}
+}
+
+func (w *errorWriter) startBuffering() bool {
+	if w.ResponseWriter.Written() || w.Status() < 400 {
+		return false
+	}
+	contentType := w.Header().Get("Content-Type")
+	if !strings.HasPrefix(contentType, "application/json") && !strings.HasPrefix(contentType, problem.ContentType) {
+		return false
+	}
+	w.buffering = true
+	return true
+}
+
+// flush дополняет задержанный ответ атрибутами запроса и отправляет его
+func (w *errorWriter) flush(ctx context.Context) {
+	if !w.buffering {
+		return
+	}
+
+	body := w.body.Bytes()
+	var fields map[string]any
+	if err := json.Unmarshal(body, &fields); err == nil && fields != nil {
+		for _, key := range []string{"code", "error", "message"} {
+			if message, ok := fields[key].(string); ok && w.message == "" {
+				w.message = message
+			}
+		}
+		add := func(key, value string) {
+			if _, exists := fields[key]; !exists && value != "" {
+				fields[key] = value
+			}
+		}
+		add("request_id", RequestID(ctx))
+		add("user_id", UserID(ctx))
+		add("route", Route(ctx))
+		if enriched, err := json.Marshal(fields); err == nil {
+			body = enriched
+		}
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.ResponseWriter.Write(body)
+}
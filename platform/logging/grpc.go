@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataRequestID - ключ метаданных gRPC с идентификатором запроса
+const MetadataRequestID = "x-request-id"
+
+// UnaryClientInterceptor передает идентификатор запроса из контекста в метаданных gRPC
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if requestID := RequestID(ctx); requestID != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, MetadataRequestID, requestID)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// UnaryServerInterceptor принимает идентификатор запроса из метаданных gRPC
+// и пишет строку журнала на каждый вызов
+func UnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		requestID := ""
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(MetadataRequestID); len(values) > 0 {
+				requestID = values[0]
+			}
+		}
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.NewString()
+		}
+
+		ctx = WithRoute(WithRequestID(ctx, requestID), info.FullMethod)
+
+		resp, err := handler(ctx, req)
+
+		attrs := []any{
+			slog.String("code", status.Code(err).String()),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+		}
+		if err != nil {
+			logger.ErrorContext(ctx, "grpc call failed", append(attrs, slog.String("error", err.Error()))...)
+		} else {
+			logger.DebugContext(ctx, "grpc call completed", attrs...)
+		}
+
+		return resp, err
+	}
+}
@@ -0,0 +1,115 @@
+// Package logging содержит общую для сервисов платформы настройку журналирования
+// на основе log/slog: JSON в stdout и атрибуты запроса из контекста.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+	userIDKey
+	routeKey
+)
+
+// Setup создает логгер сервиса и делает его логгером по умолчанию для slog
+func Setup(service string) *slog.Logger {
+	logger := New(service)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// New создает JSON-логгер сервиса. Уровень задается переменной LOG_LEVEL
+// (debug, info, warn, error), по умолчанию info.
+func New(service string) *slog.Logger {
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: parseLevel(os.Getenv("LOG_LEVEL")),
+	})
+
+	return slog.New(&ContextHandler{Handler: handler}).With(slog.String("service", service))
+}
+
+// ContextHandler добавляет в каждую запись request_id, user_id, route и trace_id из контекста
+type ContextHandler struct {
+	slog.Handler
+}
+
+// Handle дополняет запись атрибутами запроса
+func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	if userID := UserID(ctx); userID != "" {
+		record.AddAttrs(slog.String("user_id", userID))
+	}
+	if route := Route(ctx); route != "" {
+		record.AddAttrs(slog.String("route", route))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs возвращает обработчик с дополнительными атрибутами
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup возвращает обработчик с группой атрибутов
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// WithRequestID добавляет в контекст идентификатор запроса
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID возвращает идентификатор запроса из контекста
+func RequestID(ctx context.Context) string {
+	value, _ := ctx.Value(requestIDKey).(string)
+	return value
+}
+
+// WithUserID добавляет в контекст ID пользователя
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserID возвращает ID пользователя из контекста
+func UserID(ctx context.Context) string {
+	value, _ := ctx.Value(userIDKey).(string)
+	return value
+}
+
+// WithRoute добавляет в контекст маршрут запроса
+func WithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey, route)
+}
+
+// Route возвращает маршрут запроса из контекста
+func Route(ctx context.Context) string {
+	value, _ := ctx.Value(routeKey).(string)
+	return value
+}
+
+func parseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
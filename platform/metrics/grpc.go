@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcClientRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "grpc_client",
+		Name:      "requests_total",
+		Help:      "Количество исходящих вызовов gRPC по методу и коду ответа.",
+	}, []string{"method", "code"})
+
+	grpcClientDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "grpc_client",
+		Name:      "request_duration_seconds",
+		Help:      "Время исходящего вызова gRPC по методу.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	grpcServerRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "grpc_server",
+		Name:      "requests_total",
+		Help:      "Количество входящих вызовов gRPC по методу и коду ответа.",
+	}, []string{"method", "code"})
+
+	grpcServerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "grpc_server",
+		Name:      "request_duration_seconds",
+		Help:      "Время обработки входящего вызова gRPC по методу.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+)
+
+func init() {
+	prometheus.MustRegister(grpcClientRequests, grpcClientDuration, grpcServerRequests, grpcServerDuration)
+}
+
+// UnaryClientInterceptor считает исходящие вызовы gRPC (например, CheckAccess) и их ошибки
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		grpcClientRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+		grpcClientDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// UnaryServerInterceptor считает входящие вызовы gRPC и их ошибки
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		grpcServerRequests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		grpcServerDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"platform/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute - значение метки route для запросов без маршрута.
+// Путь запроса в метку не попадает, чтобы не раздувать количество рядов.
+const unmatchedRoute = "unmatched"
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Количество HTTP-запросов по методу, шаблону маршрута и статусу ответа.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Время обработки HTTP-запроса по методу, шаблону маршрута и статусу ответа.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+func init() {
+	prometheus.MustRegister(httpRequests, httpDuration)
+}
+
+// Middleware считает HTTP-запросы и время их обработки.
+// Маршрут берется из контекста журнала (logging.SetRoute), иначе из шаблона gin,
+// поэтому middleware подключается после logging.Middleware.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := logging.Route(c.Request.Context())
+		if route == "" {
+			route = c.FullPath()
+		}
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		status := strconv.Itoa(c.Writer.Status())
+		httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,58 @@
+// Package metrics содержит общие для сервисов платформы метрики Prometheus:
+// HTTP-запросы, вызовы gRPC и состояние пула соединений с базой данных.
+// Все метрики платформы имеют префикс Namespace, поэтому имена совпадают во всех сервисах.
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace - общий префикс имен метрик платформы
+const Namespace = "eduplatform"
+
+// NewCounter регистрирует счетчик с префиксом платформы.
+// subsystem - имя сервиса, например auth или edu.
+func NewCounter(subsystem, name, help string) prometheus.Counter {
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: subsystem,
+		Name:      name,
+		Help:      help,
+	})
+	prometheus.MustRegister(counter)
+	return counter
+}
+
+// NewCounterVec регистрирует счетчик с метками и префиксом платформы
+func NewCounterVec(subsystem, name, help string, labels ...string) *prometheus.CounterVec {
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: subsystem,
+		Name:      name,
+		Help:      help,
+	}, labels)
+	prometheus.MustRegister(counter)
+	return counter
+}
+
+// RegisterDB регистрирует метрики пула соединений из sql.DB.Stats
+// (go_sql_open_connections, go_sql_wait_count_total и другие) с меткой db_name
+func RegisterDB(db *sql.DB, name string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler отдает метрики в формате Prometheus
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{}))
+}
+
+// Setup добавляет сбор метрик HTTP-запросов и маршрут GET /metrics
+func Setup(router *gin.Engine) {
+	router.Use(Middleware())
+	router.GET("/metrics", Handler())
+}
@@ -0,0 +1,49 @@
+package pagination
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+)
+
+// ExactCountLimit - до этого числа строк total_estimate считается точно
+const ExactCountLimit = 1000
+
+// Queryer - *sql.DB, *sqlx.DB или транзакция
+type Queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// EstimateTotal оценивает число строк выборки from - части запроса после SELECT,
+// например "FROM courses c WHERE c.status = $1".
+//
+// Небольшие выборки считаются точно. Если строк больше ExactCountLimit, полный COUNT(*)
+// прочитал бы их все, поэтому берется оценка планировщика Postgres (EXPLAIN), но не меньше
+// уже посчитанного.
+func EstimateTotal(ctx context.Context, db Queryer, from string, args ...any) (int64, error) {
+	var count int64
+	query := "SELECT COUNT(*) FROM (SELECT 1 " + from + " LIMIT " + strconv.Itoa(ExactCountLimit+1) + ") t"
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	if count <= ExactCountLimit {
+		return count, nil
+	}
+
+	var plan []byte
+	if err := db.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) SELECT 1 "+from, args...).Scan(&plan); err != nil {
+		return 0, err
+	}
+
+	var explain []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &explain); err != nil || len(explain) == 0 {
+		return count, err
+	}
+
+	return max(count, int64(explain[0].Plan.Rows)), nil
+}
@@ -0,0 +1,140 @@
+// Package pagination реализует постраничную выдачу списков по курсору (keyset pagination).
+//
+// Клиент получает страницу {items, next_cursor, total_estimate} и, чтобы получить следующую,
+// передает next_cursor в параметре cursor. Курсор непрозрачен для клиента: это закодированный
+// ключ сортировки последнего элемента страницы. Следующая страница продолжает выборку с этого
+// ключа (WHERE (key, id) < ($1, $2)), поэтому глубокие страницы читаются так же быстро,
+// как первая, - в отличие от OFFSET, который перебирает все пропущенные строки.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"platform/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Ошибки параметров страницы
+var (
+	InvalidCursor = problem.Define("INVALID_CURSOR", http.StatusBadRequest, problem.Messages{
+		"ru": "Некорректный курсор страницы",
+		"en": "Invalid page cursor",
+	})
+	InvalidLimit = problem.Define("INVALID_LIMIT", http.StatusBadRequest, problem.Messages{
+		"ru": "Параметр limit должен быть положительным целым числом",
+		"en": "limit must be a positive integer",
+	})
+)
+
+// Limits - размер страницы эндпоинта
+type Limits struct {
+	// Default - размер страницы без параметра limit
+	Default int
+	// Max - наибольший размер страницы, больший limit уменьшается до него
+	Max int
+}
+
+// DefaultLimits - размер страницы по умолчанию для списков
+var DefaultLimits = Limits{Default: 20, Max: 100}
+
+// Params - запрошенная страница
+type Params struct {
+	Limit int
+	// Cursor - next_cursor предыдущей страницы, пустой для первой страницы
+	Cursor string
+}
+
+// First сообщает, что запрошена первая страница
+func (p Params) First() bool {
+	return p.Cursor == ""
+}
+
+// FromRequest читает параметры limit и cursor из строки запроса
+func FromRequest(c *gin.Context, limits Limits) (Params, error) {
+	params := Params{Limit: limits.Default, Cursor: c.Query("cursor")}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return Params{}, InvalidLimit
+		}
+		params.Limit = min(limit, limits.Max)
+	}
+
+	return params, nil
+}
+
+// cursor - содержимое курсора: вид списка и ключ последнего элемента.
+// Вид не дает применить курсор одного списка или сортировки к другому.
+type cursor struct {
+	Kind string          `json:"k"`
+	Key  json.RawMessage `json:"v"`
+}
+
+// Encode кодирует ключ сортировки key списка kind в курсор
+func Encode(kind string, key any) (string, error) {
+	raw, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(cursor{Kind: kind, Key: raw})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Decode разбирает курсор списка kind в key. Для первой страницы возвращает false.
+// Поврежденный курсор или курсор другого списка - ошибка InvalidCursor.
+func (p Params) Decode(kind string, key any) (bool, error) {
+	if p.First() {
+		return false, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(p.Cursor)
+	if err != nil {
+		return false, InvalidCursor
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Kind != kind {
+		return false, InvalidCursor
+	}
+	if err := json.Unmarshal(c.Key, key); err != nil {
+		return false, InvalidCursor
+	}
+	return true, nil
+}
+
+// Page - страница списка
+type Page[T any] struct {
+	Items []T `json:"items"`
+	// NextCursor - курсор следующей страницы, отсутствует на последней странице
+	NextCursor string `json:"next_cursor,omitempty"`
+	// TotalEstimate - число элементов во всем списке, см. EstimateTotal
+	TotalEstimate int64 `json:"total_estimate"`
+}
+
+// NewPage собирает страницу из выборки до Limit+1 элементов: лишний элемент означает,
+// что следующая страница есть. Курсор строится по ключу key последнего элемента страницы.
+func NewPage[T any](items []T, params Params, kind string, key func(T) any) (*Page[T], error) {
+	page := &Page[T]{Items: items}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+
+	if len(items) > params.Limit {
+		page.Items = items[:params.Limit]
+		next, err := Encode(kind, key(page.Items[params.Limit-1]))
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = next
+	}
+
+	return page, nil
+}
@@ -0,0 +1,47 @@
+package problem
+
+import "net/http"
+
+// Общие для всех сервисов ошибки
+var (
+	Internal = Define("INTERNAL_ERROR", http.StatusInternalServerError, Messages{
+		"ru": "Внутренняя ошибка сервера",
+		"en": "Internal server error",
+	})
+	InvalidRequest = Define("INVALID_REQUEST", http.StatusBadRequest, Messages{
+		"ru": "Некорректный запрос",
+		"en": "Invalid request",
+	})
+	InvalidID = Define("INVALID_ID", http.StatusBadRequest, Messages{
+		"ru": "Некорректный формат идентификатора",
+		"en": "Invalid identifier format",
+	})
+	Unauthorized = Define("UNAUTHORIZED", http.StatusUnauthorized, Messages{
+		"ru": "Требуется авторизация",
+		"en": "Authentication required",
+	})
+	TokenInvalid = Define("TOKEN_INVALID", http.StatusUnauthorized, Messages{
+		"ru": "Токен недействителен или истек",
+		"en": "Invalid or expired token",
+	})
+	Forbidden = Define("FORBIDDEN", http.StatusForbidden, Messages{
+		"ru": "Недостаточно прав для выполнения операции",
+		"en": "Insufficient permissions",
+	})
+	NotFound = Define("NOT_FOUND", http.StatusNotFound, Messages{
+		"ru": "Ресурс не найден",
+		"en": "Resource not found",
+	})
+	MethodNotAllowed = Define("METHOD_NOT_ALLOWED", http.StatusMethodNotAllowed, Messages{
+		"ru": "Метод не поддерживается",
+		"en": "Method not allowed",
+	})
+	RateLimited = Define("RATE_LIMITED", http.StatusTooManyRequests, Messages{
+		"ru": "Слишком много запросов, попробуйте позже",
+		"en": "Too many requests, try again later",
+	})
+	ServiceUnavailable = Define("SERVICE_UNAVAILABLE", http.StatusServiceUnavailable, Messages{
+		"ru": "Сервис временно недоступен",
+		"en": "Service temporarily unavailable",
+	})
+)
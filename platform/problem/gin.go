@@ -0,0 +1,140 @@
+package problem
+
+import (
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// В ошибках проверки поля называются так же, как в JSON запроса
+func init() {
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			for _, tag := range []string{"json", "form", "uri"} {
+				name := strings.Split(field.Tag.Get(tag), ",")[0]
+				if name == "-" {
+					return ""
+				}
+				if name != "" {
+					return name
+				}
+			}
+			return field.Name
+		})
+	}
+}
+
+// Error - ошибка с описанием и подробностями для ответа
+type Error struct {
+	Definition *Definition
+	// Errors - ошибки проверки полей запроса
+	Errors []FieldError
+}
+
+func (e *Error) Error() string {
+	return e.Definition.Code
+}
+
+// Validation преобразует ошибку разбора тела или параметров запроса в INVALID_REQUEST.
+// В ответ попадают только имена полей и нарушенные правила, текст ошибки - только в журнал.
+func Validation(err error) error {
+	result := &Error{Definition: InvalidRequest}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		for _, fieldError := range validationErrors {
+			result.Errors = append(result.Errors, FieldError{
+				Field: fieldError.Field(),
+				Rule:  fieldError.Tag(),
+			})
+		}
+	}
+
+	return result
+}
+
+// Rule сопоставляет ошибки сервиса описанию ответа
+type Rule struct {
+	Definition *Definition
+	Errors     []error
+}
+
+// Map создает правило: любая из ошибок errs (с учетом errors.Is) отдается как def
+func Map(def *Definition, errs ...error) Rule {
+	return Rule{Definition: def, Errors: errs}
+}
+
+// Mapping - таблица соответствия ошибок сервиса кодам ответов.
+// Ошибки, которых нет в таблице, отдаются как INTERNAL_ERROR.
+type Mapping []Rule
+
+// Resolve находит описание ответа для ошибки
+func (m Mapping) Resolve(err error) (*Definition, []FieldError, bool) {
+	var problemError *Error
+	if errors.As(err, &problemError) {
+		return problemError.Definition, problemError.Errors, true
+	}
+
+	var def *Definition
+	if errors.As(err, &def) {
+		return def, nil, true
+	}
+
+	for _, rule := range m {
+		for _, target := range rule.Errors {
+			if errors.Is(err, target) {
+				return rule.Definition, nil, true
+			}
+		}
+	}
+
+	return nil, nil, false
+}
+
+// Abort отвечает ошибкой err и прерывает обработку запроса.
+// err может быть *Definition, *Error или ошибкой из таблицы. Прочие ошибки
+// и ошибки со статусом 5xx пишутся в журнал, клиент получает только код.
+func (m Mapping) Abort(c *gin.Context, err error) {
+	def, fieldErrors, ok := m.Resolve(err)
+	if !ok {
+		def = Internal
+	}
+
+	if def.Status >= 500 {
+		slog.ErrorContext(c.Request.Context(), "ошибка при обработке запроса",
+			slog.String("code", def.Code), slog.Any("error", err))
+	}
+	_ = c.Error(err)
+
+	p := New(c.Request, def)
+	p.Errors = fieldErrors
+	AbortWith(c, p)
+}
+
+// Abort отвечает ошибкой без таблицы соответствия
+func Abort(c *gin.Context, err error) {
+	Mapping(nil).Abort(c, err)
+}
+
+// AbortWith отправляет подготовленный ответ и прерывает обработку запроса
+func AbortWith(c *gin.Context, p *Problem) {
+	c.Header("Content-Type", ContentType)
+	c.Header("Content-Language", Language(c.Request))
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// Setup отвечает в формате problem+json на запросы к неизвестным маршрутам и методам
+func Setup(router *gin.Engine) {
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(func(c *gin.Context) {
+		Abort(c, NotFound)
+	})
+	router.NoMethod(func(c *gin.Context) {
+		Abort(c, MethodNotAllowed)
+	})
+}
@@ -0,0 +1,35 @@
+package problem
+
+import (
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguage - язык сообщений, если клиент не указал поддерживаемый язык
+const DefaultLanguage = "ru"
+
+// Поддерживаемые языки сообщений; первый используется по умолчанию
+var (
+	languages = []string{DefaultLanguage, "en"}
+	matcher   = language.NewMatcher([]language.Tag{language.Russian, language.English})
+)
+
+// Language выбирает язык сообщений по заголовку Accept-Language
+func Language(r *http.Request) string {
+	header := r.Header.Get("Accept-Language")
+	if header == "" {
+		return DefaultLanguage
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return DefaultLanguage
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLanguage
+	}
+	return languages[index]
+}
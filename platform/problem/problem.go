@@ -0,0 +1,124 @@
+// Package problem формирует ответы с ошибками в формате RFC 7807 (application/problem+json).
+// Каждая ошибка имеет постоянный машиночитаемый код, например COURSE_NOT_FOUND,
+// и сообщение на языке из заголовка Accept-Language. Внутренние подробности
+// ошибок в ответ не попадают, а пишутся в журнал.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ContentType - тип содержимого ответа с ошибкой
+const ContentType = "application/problem+json"
+
+// typePrefix - префикс URI типа ошибки; полный тип имеет вид urn:eduplatform:problem:course-not-found
+const typePrefix = "urn:eduplatform:problem:"
+
+// Messages - сообщения об ошибке по языкам: "ru", "en"
+type Messages map[string]string
+
+// Definition описывает ошибку: постоянный код, HTTP-статус и сообщения.
+// Definition реализует error, поэтому ее можно вернуть из обработчика напрямую.
+type Definition struct {
+	Code     string
+	Status   int
+	Messages Messages
+}
+
+// Define создает описание ошибки
+func Define(code string, status int, messages Messages) *Definition {
+	return &Definition{Code: code, Status: status, Messages: messages}
+}
+
+// Error возвращает код ошибки
+func (d *Definition) Error() string {
+	return d.Code
+}
+
+// Message возвращает сообщение на языке lang или на языке по умолчанию
+func (d *Definition) Message(lang string) string {
+	if message, ok := d.Messages[lang]; ok {
+		return message
+	}
+	if message, ok := d.Messages[DefaultLanguage]; ok {
+		return message
+	}
+	return d.Code
+}
+
+// Type возвращает URI типа ошибки
+func (d *Definition) Type() string {
+	return typePrefix + strings.ReplaceAll(strings.ToLower(d.Code), "_", "-")
+}
+
+// FieldError - ошибка проверки одного поля запроса
+type FieldError struct {
+	Field string `json:"field"`
+	// Rule - нарушенное правило проверки, например required или email
+	Rule string `json:"rule"`
+}
+
+// Problem - тело ответа с ошибкой (RFC 7807)
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Code     string       `json:"code"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+	// Extensions - дополнительные поля ответа, например route или upstream
+	Extensions map[string]any `json:"-"`
+}
+
+// MarshalJSON добавляет дополнительные поля на верхний уровень ответа
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type plain Problem
+	body, err := json.Marshal(plain(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return body, err
+	}
+
+	fields := make(map[string]any, len(p.Extensions))
+	for key, value := range p.Extensions {
+		fields[key] = value
+	}
+	var standard map[string]any
+	if err := json.Unmarshal(body, &standard); err != nil {
+		return nil, err
+	}
+	for key, value := range standard {
+		fields[key] = value
+	}
+	return json.Marshal(fields)
+}
+
+// New создает тело ответа для запроса r на языке из Accept-Language
+func New(r *http.Request, def *Definition) *Problem {
+	return &Problem{
+		Type:     def.Type(),
+		Title:    def.Message(Language(r)),
+		Status:   def.Status,
+		Code:     def.Code,
+		Instance: r.URL.Path,
+	}
+}
+
+// With добавляет в ответ дополнительное поле
+func (p *Problem) With(key string, value any) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+// Write отправляет ответ с ошибкой через http.ResponseWriter
+func Write(w http.ResponseWriter, r *http.Request, p *Problem) {
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Language", Language(r))
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor создает клиентский спан на вызов gRPC (например, CheckAccess)
+// и передает traceparent в метаданных
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := tracer().Start(ctx, strings.TrimPrefix(method, "/"),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(rpcAttributes(method)...),
+		)
+		defer span.End()
+
+		md, _ := metadata.FromOutgoingContext(ctx)
+		md = md.Copy()
+		otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+		ctx = metadata.NewOutgoingContext(ctx, md)
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		recordStatus(span, err)
+		return err
+	}
+}
+
+// UnaryServerInterceptor продолжает трассу из метаданных и создает серверный спан на вызов gRPC
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+		}
+
+		ctx, span := tracer().Start(ctx, strings.TrimPrefix(info.FullMethod, "/"),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(rpcAttributes(info.FullMethod)...),
+		)
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+		recordStatus(span, err)
+		return resp, err
+	}
+}
+
+// rpcAttributes разбирает полное имя метода вида /package.Service/Method
+func rpcAttributes(fullMethod string) []attribute.KeyValue {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return []attribute.KeyValue{
+		semconv.RPCSystemGRPC,
+		semconv.RPCService(service),
+		semconv.RPCMethod(method),
+	}
+}
+
+// recordStatus записывает код ответа gRPC в спан
+func recordStatus(span trace.Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, code.String())
+	}
+}
+
+// metadataCarrier позволяет пропагатору читать и писать метаданные gRPC
+type metadataCarrier metadata.MD
+
+// Get возвращает первое значение ключа
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Set заменяет значение ключа
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys возвращает все ключи
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+
+	"github.com/XSAM/otelsql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OpenDB открывает базу данных PostgreSQL через драйвер с трассировкой запросов.
+// Каждый запрос получает спан с именем вида "SELECT courses" и параметризованным
+// текстом в db.statement; значения параметров в спан не попадают.
+// Запросы вне трассы (без родительского спана) не трассируются.
+func OpenDB(driverName, dsn string) (*sql.DB, error) {
+	return otelsql.Open(driverName, dsn,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanNameFormatter(spanName),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+			OmitConnPrepare:      true,
+			OmitRows:             true,
+			OmitConnectorConnect: true,
+			SpanFilter: func(ctx context.Context, _ otelsql.Method, _ string, _ []driver.NamedValue) bool {
+				return trace.SpanContextFromContext(ctx).IsValid()
+			},
+		}),
+	)
+}
+
+// spanName возвращает имя спана запроса: операцию и основную таблицу
+func spanName(_ context.Context, method otelsql.Method, query string) string {
+	if name := StatementName(query); name != "" {
+		return name
+	}
+	return string(method)
+}
+
+// StatementName возвращает короткое имя SQL-запроса вида "SELECT courses",
+// "INSERT purchases" или "UPDATE users". Для запросов без таблицы возвращается только операция.
+func StatementName(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	operation := strings.ToUpper(fields[0])
+
+	// Ключевое слово, за которым следует основная таблица
+	var marker string
+	switch operation {
+	case "SELECT", "DELETE":
+		marker = "FROM"
+	case "INSERT":
+		marker = "INTO"
+	case "UPDATE":
+		if len(fields) > 1 {
+			return operation + " " + tableName(fields[1])
+		}
+		return operation
+	default:
+		return operation
+	}
+
+	for i := 1; i < len(fields)-1; i++ {
+		if strings.EqualFold(fields[i], marker) {
+			if table := tableName(fields[i+1]); table != "" {
+				return operation + " " + table
+			}
+			break
+		}
+	}
+
+	return operation
+}
+
+// tableName очищает имя таблицы от кавычек, скобок и запятых
+func tableName(field string) string {
+	field = strings.Trim(field, `"(),;`)
+	if field == "" || strings.HasPrefix(strings.ToUpper(field), "SELECT") {
+		return ""
+	}
+	return strings.ToLower(field)
+}
@@ -0,0 +1,109 @@
+// Package tracing содержит общую для сервисов платформы настройку OpenTelemetry:
+// провайдер трассировки с выбираемым экспортером, распространение контекста
+// в формате W3C traceparent и трассировку HTTP, gRPC и SQL.
+//
+// Экспортер выбирается переменной OTEL_TRACES_EXPORTER: otlp, stdout или none
+// (по умолчанию none). Адрес OTLP-коллектора и остальные параметры задаются
+// стандартными переменными OTEL_EXPORTER_OTLP_*, выборка - OTEL_TRACES_SAMPLER.
+//
+// В тестах вместо экспортера подключается tracetest.SpanRecorder:
+//
+//	recorder := tracetest.NewSpanRecorder()
+//	tracing.SetupWithProcessor("edu", recorder)
+//	...
+//	spans := recorder.Ended()
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Допустимые значения OTEL_TRACES_EXPORTER
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterNone   = "none"
+)
+
+// instrumentationName - имя библиотеки инструментирования в спанах
+const instrumentationName = "platform/tracing"
+
+// ShutdownFunc отправляет накопленные спаны и останавливает провайдер
+type ShutdownFunc func(ctx context.Context) error
+
+// Setup настраивает глобальный провайдер трассировки и W3C-пропагатор.
+// При экспортере none спаны не записываются, но traceparent передается дальше.
+func Setup(ctx context.Context, service string) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch name := strings.ToLower(os.Getenv("OTEL_TRACES_EXPORTER")); name {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err = otlptracegrpc.New(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	default:
+		return nil, fmt.Errorf("неизвестный экспортер трассировки %q: ожидается otlp, stdout или none", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания экспортера трассировки: %w", err)
+	}
+
+	provider := SetupWithProcessor(service, sdktrace.NewBatchSpanProcessor(exporter))
+	return provider.Shutdown, nil
+}
+
+// SetupWithProcessor делает глобальным провайдер с указанным обработчиком спанов.
+// Используется в Setup и в тестах с tracetest.SpanRecorder.
+func SetupWithProcessor(service string, processor sdktrace.SpanProcessor) *sdktrace.TracerProvider {
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(service),
+	))
+	if err != nil {
+		res = resource.Default()
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider
+}
+
+// tracer возвращает трассировщик глобального провайдера.
+// Провайдер берется при каждом вызове, чтобы учитывать SetupWithProcessor в тестах.
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start начинает внутренний спан, например для участка бизнес-логики
+func Start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer().Start(ctx, name)
+}